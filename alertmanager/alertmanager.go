@@ -0,0 +1,112 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/swilcox/led-kurokku-go/config"
+)
+
+// Payload is the body of an Alertmanager webhook notification (version 4).
+type Payload struct {
+	Version  string  `json:"version"`
+	GroupKey string  `json:"groupKey"`
+	Status   string  `json:"status"`
+	Receiver string  `json:"receiver"`
+	Alerts   []Alert `json:"alerts"`
+}
+
+// Alert is one alert of a notification.
+type Alert struct {
+	Status      string            `json:"status"` // "firing" or "resolved"
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	Fingerprint string            `json:"fingerprint"`
+}
+
+// Store keeps the clock's alerts. *redis.Client and *engine.AlertList
+// implement it.
+type Store interface {
+	SetAlert(ctx context.Context, a config.AlertConfig) error
+	DeleteAlert(ctx context.Context, id string) error
+}
+
+// DefaultPriorities maps the severity label to an alert priority.
+var DefaultPriorities = map[string]int{
+	"critical": 1,
+	"warning":  5,
+	"info":     10,
+}
+
+// defaultPriority applies to alerts without a known severity label.
+const defaultPriority = 5
+
+// Handler receives Alertmanager webhook notifications. Firing alerts are
+// written to Store as clock alerts with the fingerprint as ID; resolved
+// alerts are deleted.
+type Handler struct {
+	Store      Store
+	Priorities map[string]int // severity label → priority; nil uses DefaultPriorities
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var p Payload
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "invalid payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, a := range p.Alerts {
+		if a.Fingerprint == "" {
+			continue
+		}
+		var err error
+		if a.Status == "resolved" {
+			err = h.Store.DeleteAlert(r.Context(), a.Fingerprint)
+		} else {
+			err = h.Store.SetAlert(r.Context(), h.AlertConfig(a))
+		}
+		if err != nil {
+			// Alertmanager retries the whole notification.
+			log.Printf("alertmanager %s: %v", a.Fingerprint, err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AlertConfig maps a firing alert to a clock alert: the severity label sets
+// priority and severity, the summary annotation the message (the alert name
+// when missing), and alerts with the same name are grouped.
+func (h *Handler) AlertConfig(a Alert) config.AlertConfig {
+	priorities := h.Priorities
+	if priorities == nil {
+		priorities = DefaultPriorities
+	}
+	sev := a.Labels["severity"]
+	priority, ok := priorities[sev]
+	if !ok {
+		priority = defaultPriority
+	}
+	msg := a.Annotations["summary"]
+	if msg == "" {
+		msg = a.Labels["alertname"]
+	}
+	ac := config.AlertConfig{
+		ID:          a.Fingerprint,
+		Message:     msg,
+		Priority:    priority,
+		Group:       a.Labels["alertname"],
+		Fingerprint: a.Fingerprint,
+	}
+	switch sev {
+	case "critical":
+		ac.Severity = config.SeverityCritical
+	case "warning":
+		ac.Severity = config.SeverityWarn
+	}
+	return ac
+}
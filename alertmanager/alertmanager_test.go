@@ -0,0 +1,113 @@
+package alertmanager_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/swilcox/led-kurokku-go/alertmanager"
+	"github.com/swilcox/led-kurokku-go/config"
+)
+
+type memStore struct {
+	alerts map[string]config.AlertConfig
+	err    error
+}
+
+func (m *memStore) SetAlert(_ context.Context, a config.AlertConfig) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.alerts[a.ID] = a
+	return nil
+}
+
+func (m *memStore) DeleteAlert(_ context.Context, id string) error {
+	if m.err != nil {
+		return m.err
+	}
+	delete(m.alerts, id)
+	return nil
+}
+
+const payload = `{
+  "version": "4",
+  "status": "firing",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "DiskFull", "severity": "critical"},
+      "annotations": {"summary": "disk full on nas"},
+      "fingerprint": "a1"
+    },
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighLoad", "severity": "warning"},
+      "annotations": {},
+      "fingerprint": "b2"
+    },
+    {
+      "status": "resolved",
+      "labels": {"alertname": "Backup"},
+      "fingerprint": "c3"
+    }
+  ]
+}`
+
+func post(h http.Handler, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/webhooks/alertmanager", strings.NewReader(body)))
+	return rec
+}
+
+func TestHandler_FiringAndResolved(t *testing.T) {
+	store := &memStore{alerts: map[string]config.AlertConfig{"c3": {ID: "c3"}}}
+	h := &alertmanager.Handler{Store: store}
+
+	if rec := post(h, payload); rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rec.Code)
+	}
+
+	if _, ok := store.alerts["c3"]; ok {
+		t.Error("resolved alert c3 was not deleted")
+	}
+	disk := store.alerts["a1"]
+	if disk.Message != "disk full on nas" || disk.Priority != 1 || disk.Severity != config.SeverityCritical {
+		t.Errorf("a1 = %+v, want summary message, priority 1, critical", disk)
+	}
+	if disk.Group != "DiskFull" || disk.Fingerprint != "a1" {
+		t.Errorf("a1 group/fingerprint = %q/%q", disk.Group, disk.Fingerprint)
+	}
+	load := store.alerts["b2"]
+	if load.Message != "HighLoad" || load.Priority != 5 || load.Severity != config.SeverityWarn {
+		t.Errorf("b2 = %+v, want alertname message, priority 5, warn", load)
+	}
+}
+
+func TestHandler_CustomPriorities(t *testing.T) {
+	h := &alertmanager.Handler{Priorities: map[string]int{"critical": 0}}
+	got := h.AlertConfig(alertmanager.Alert{
+		Labels:      map[string]string{"severity": "critical"},
+		Fingerprint: "x",
+	})
+	if got.Priority != 0 {
+		t.Errorf("priority = %d, want 0", got.Priority)
+	}
+	got = h.AlertConfig(alertmanager.Alert{Fingerprint: "y"})
+	if got.Priority != 5 || got.Severity != "" {
+		t.Errorf("unlabelled alert = %+v, want priority 5 and no severity", got)
+	}
+}
+
+func TestHandler_Errors(t *testing.T) {
+	h := &alertmanager.Handler{Store: &memStore{err: errors.New("down")}}
+	if rec := post(h, "{"); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad payload status = %d, want 400", rec.Code)
+	}
+	if rec := post(h, payload); rec.Code != http.StatusBadGateway {
+		t.Errorf("store error status = %d, want 502", rec.Code)
+	}
+}
@@ -0,0 +1,45 @@
+package button
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/host/v3"
+)
+
+// debounce ignores contact bounce after a press.
+const debounce = 300 * time.Millisecond
+
+// Watch calls fn each time the push button on GPIO pin name is pressed. The
+// button connects the pin to ground; the internal pull-up holds it high
+// otherwise. Watch blocks until ctx is cancelled.
+func Watch(ctx context.Context, name string, fn func()) error {
+	if _, err := host.Init(); err != nil {
+		return fmt.Errorf("periph init: %w", err)
+	}
+	pin := gpioreg.ByName(name)
+	if pin == nil {
+		return fmt.Errorf("GPIO pin %q not found", name)
+	}
+	if err := pin.In(gpio.PullUp, gpio.FallingEdge); err != nil {
+		return fmt.Errorf("GPIO pin %q: %w", name, err)
+	}
+	defer pin.Halt()
+
+	var last time.Time
+	for ctx.Err() == nil {
+		// Time out periodically to notice cancellation.
+		if !pin.WaitForEdge(500 * time.Millisecond) {
+			continue
+		}
+		if pin.Read() != gpio.Low || time.Since(last) < debounce {
+			continue
+		}
+		last = time.Now()
+		fn()
+	}
+	return nil
+}
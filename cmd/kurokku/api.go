@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/alertmanager"
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/engine"
+	"github.com/swilcox/led-kurokku-go/webhook"
+)
+
+// api serves the clock's local HTTP endpoints.
+type api struct {
+	acker *engine.Acker
+	ctrl  *engine.Control
+	mux   *http.ServeMux
+}
+
+// controlTimeout bounds how long a control request waits for the engine,
+// e.g. while it restarts on a config reload.
+const controlTimeout = 5 * time.Second
+
+// newAPI creates the API. Alerts received by the Alertmanager webhook go to
+// alerts; the push webhook is served when push is non-nil. Endpoints that
+// change state require auth.
+func newAPI(acker *engine.Acker, ctrl *engine.Control, alerts alertmanager.Store, push *webhook.Handler, auth webhook.Auth) *api {
+	a := &api{acker: acker, ctrl: ctrl, mux: http.NewServeMux()}
+	guarded := func(pattern string, h http.HandlerFunc) {
+		a.mux.Handle(pattern, auth.Require(h))
+	}
+	a.mux.HandleFunc("GET /status", a.handleStatus)
+	guarded("POST /control/next", a.command(a.ctrl.Next))
+	guarded("POST /control/prev", a.command(a.ctrl.Prev))
+	guarded("POST /control/pause", a.command(a.ctrl.Pause))
+	guarded("POST /control/resume", a.command(a.ctrl.Resume))
+	guarded("POST /control/pin", a.handlePin)
+	guarded("POST /control/message", a.handleMessage)
+	guarded("POST /control/brightness", a.handleBrightness)
+	guarded("DELETE /control/brightness", a.handleBrightnessClear)
+	a.mux.HandleFunc("GET /alerts/pending", a.handlePending)
+	guarded("POST /alerts/ack", a.handleAckAll)
+	guarded("POST /alerts/{id}/ack", a.handleAck)
+	a.mux.Handle("POST /webhooks/alertmanager", auth.Require(&alertmanager.Handler{Store: alerts}))
+	if push != nil {
+		a.mux.Handle("POST /webhooks/push", push)
+	}
+	return a
+}
+
+// ServeHTTP implements http.Handler.
+func (a *api) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+func (a *api) handlePending(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string][]string{"pending": a.acker.Pending()})
+}
+
+func (a *api) handleAckAll(w http.ResponseWriter, r *http.Request) {
+	a.ack(w, r, engine.AckAll)
+}
+
+func (a *api) handleAck(w http.ResponseWriter, r *http.Request) {
+	a.ack(w, r, r.PathValue("id"))
+}
+
+func (a *api) ack(w http.ResponseWriter, r *http.Request, id string) {
+	err := a.acker.Ack(r.Context(), id, engine.AckSourceHTTP)
+	if errors.Is(err, engine.ErrUnknownAlert) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("http ack %s: %v", id, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write json: %v", err)
+	}
+}
+
+func (a *api) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, a.ctrl.Status())
+}
+
+// command returns a handler running a control command without arguments.
+func (a *api) command(fn func(context.Context) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a.control(w, r, fn)
+	}
+}
+
+// control runs fn against the engine, replying with the new status.
+func (a *api) control(w http.ResponseWriter, r *http.Request, fn func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(r.Context(), controlTimeout)
+	defer cancel()
+	if err := fn(ctx); err != nil {
+		status := http.StatusBadRequest
+		if ctx.Err() != nil {
+			status = http.StatusServiceUnavailable
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	writeJSON(w, a.ctrl.Status())
+}
+
+// controlRequest is the JSON body of the pin, message and brightness
+// endpoints.
+type controlRequest struct {
+	Widget   string          `json:"widget"`   // pin: rotation index or widget ID
+	Text     string          `json:"text"`     // message
+	Level    *byte           `json:"level"`    // brightness, 0-15
+	Duration config.Duration `json:"duration"` // how long it lasts
+}
+
+func decodeControl(w http.ResponseWriter, r *http.Request) (controlRequest, bool) {
+	var req controlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+func (a *api) handlePin(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeControl(w, r)
+	if !ok {
+		return
+	}
+	a.control(w, r, func(ctx context.Context) error {
+		return a.ctrl.Pin(ctx, req.Widget, req.Duration.Unwrap())
+	})
+}
+
+func (a *api) handleMessage(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeControl(w, r)
+	if !ok {
+		return
+	}
+	if req.Text == "" {
+		http.Error(w, "text is required", http.StatusBadRequest)
+		return
+	}
+	a.control(w, r, func(ctx context.Context) error {
+		return a.ctrl.Show(ctx, req.Text, req.Duration.Unwrap())
+	})
+}
+
+func (a *api) handleBrightness(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeControl(w, r)
+	if !ok {
+		return
+	}
+	if req.Level == nil || req.Duration <= 0 {
+		http.Error(w, "level and a positive duration are required", http.StatusBadRequest)
+		return
+	}
+	a.control(w, r, func(context.Context) error {
+		return a.ctrl.SetBrightness(*req.Level, req.Duration.Unwrap())
+	})
+}
+
+func (a *api) handleBrightnessClear(w http.ResponseWriter, r *http.Request) {
+	a.control(w, r, func(context.Context) error {
+		return a.ctrl.SetBrightness(0, 0)
+	})
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/engine"
+	"github.com/swilcox/led-kurokku-go/webhook"
+)
+
+var testAuth = webhook.Auth{Token: "t0ken", Secret: "s3cret"}
+
+// newTestAPI returns an api with alert "door" waiting for an ack.
+func newTestAPI() *api {
+	acker := engine.NewAcker(nil)
+	acker.Apply([]config.AlertConfig{{ID: "door", Message: "DOOR", AckRequired: true}})
+	return newAPI(acker, engine.NewControl(), engine.NewAlertList(), nil, testAuth)
+}
+
+// do sends a request to a, with header as name/value pairs.
+func do(a http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAPI_AckRequiresAuth(t *testing.T) {
+	a := newTestAPI()
+	sig := "sha256=" + hex.EncodeToString(webhook.Sign([]byte("s3cret"), nil))
+	tests := []struct {
+		name   string
+		path   string
+		header []string
+		want   int
+	}{
+		{"all, none", "/alerts/ack", nil, http.StatusUnauthorized},
+		{"all, wrong token", "/alerts/ack", []string{"Authorization", "Bearer nope"}, http.StatusUnauthorized},
+		{"one, none", "/alerts/door/ack", nil, http.StatusUnauthorized},
+		{"one, signature", "/alerts/door/ack", []string{webhook.SignatureHeader, sig}, http.StatusNoContent},
+		{"unknown", "/alerts/nope/ack", []string{"Authorization", "Bearer t0ken"}, http.StatusNotFound},
+		{"all, bearer", "/alerts/ack", []string{"Authorization", "Bearer t0ken"}, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		if rec := do(a, "POST", tt.path, "", tt.header...); rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestAPI_AckRefusedWithoutAuthConfigured(t *testing.T) {
+	a := newAPI(engine.NewAcker(nil), engine.NewControl(), engine.NewAlertList(), nil, webhook.Auth{})
+	if rec := do(a, "POST", "/alerts/ack", "", "Authorization", "Bearer "); rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rec.Code)
+	}
+}
+
+func TestAPI_AlertmanagerRequiresAuth(t *testing.T) {
+	alerts := engine.NewAlertList()
+	a := newAPI(engine.NewAcker(nil), engine.NewControl(), alerts, nil, testAuth)
+	body := `{"alerts":[{"status":"firing","fingerprint":"f1","labels":{"alertname":"Down"}}]}`
+
+	if rec := do(a, "POST", "/webhooks/alertmanager", body); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated: status = %d, want 401", rec.Code)
+	}
+	if got, _ := alerts.FetchAlerts(context.Background()); len(got) != 0 {
+		t.Fatalf("unauthenticated request stored %d alerts", len(got))
+	}
+	if rec := do(a, "POST", "/webhooks/alertmanager", body, "Authorization", "Bearer t0ken"); rec.Code != http.StatusNoContent {
+		t.Errorf("bearer: status = %d, want 204", rec.Code)
+	}
+	if got, _ := alerts.FetchAlerts(context.Background()); len(got) != 1 || got[0].ID != "f1" {
+		t.Errorf("alerts = %+v, want f1", got)
+	}
+}
+
+// runEngine starts an engine rotating two static messages under ctrl.
+func runEngine(t *testing.T, ctrl *engine.Control) {
+	t.Helper()
+	hour := config.Duration(time.Hour)
+	cfg := &config.Config{Widgets: []config.WidgetConfig{
+		{Type: "message", Enabled: true, ID: "a", Text: "A", Duration: hour},
+		{Type: "message", Enabled: true, ID: "b", Text: "B", Duration: hour},
+	}}
+	e := engine.New(&testutil.SpyDisplay{}, cfg, nil)
+	e.SetControl(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx) //nolint:errcheck
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestAPI_ControlRequiresAuth(t *testing.T) {
+	ctrl := engine.NewControl()
+	runEngine(t, ctrl)
+	a := newAPI(engine.NewAcker(nil), ctrl, engine.NewAlertList(), nil, testAuth)
+	bearer := []string{"Authorization", "Bearer t0ken"}
+
+	tests := []struct {
+		method, path, body string
+	}{
+		{"POST", "/control/next", ""},
+		{"POST", "/control/prev", ""},
+		{"POST", "/control/pause", ""},
+		{"POST", "/control/resume", ""},
+		{"POST", "/control/pin", `{"widget":"b","duration":"1m"}`},
+		{"POST", "/control/message", `{"text":"hi","duration":"1m"}`},
+		{"POST", "/control/brightness", `{"level":2,"duration":"1m"}`},
+		{"DELETE", "/control/brightness", ""},
+	}
+	for _, tt := range tests {
+		if rec := do(a, tt.method, tt.path, tt.body); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s without auth: status = %d, want 401", tt.method, tt.path, rec.Code)
+		}
+		if rec := do(a, tt.method, tt.path, tt.body, bearer...); rec.Code != http.StatusOK {
+			t.Errorf("%s %s: status = %d (%s), want 200", tt.method, tt.path, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestAPI_ControlPinAndStatus(t *testing.T) {
+	ctrl := engine.NewControl()
+	runEngine(t, ctrl)
+	a := newAPI(engine.NewAcker(nil), ctrl, engine.NewAlertList(), nil, testAuth)
+
+	rec := do(a, "POST", "/control/pin", `{"widget":"b","duration":"10m"}`, "Authorization", "Bearer t0ken")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("pin: status = %d (%s)", rec.Code, rec.Body)
+	}
+	var s engine.Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Widget != "b" || s.PinnedTo == nil {
+		t.Errorf("pin reply = %+v, want widget b pinned", s)
+	}
+
+	// Status is read-only and open.
+	rec = do(a, "GET", "/status", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status: %d", rec.Code)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil || s.Widget != "b" {
+		t.Errorf("status = %+v (%v), want widget b", s, err)
+	}
+}
+
+func TestAPI_ControlBadRequests(t *testing.T) {
+	a := newTestAPI()
+	bearer := []string{"Authorization", "Bearer t0ken"}
+	tests := []struct {
+		path, body string
+	}{
+		{"/control/message", `{"duration":"1m"}`},
+		{"/control/brightness", `{"level":2}`},
+		{"/control/brightness", `{"level":16,"duration":"1m"}`},
+		{"/control/pin", `not json`},
+	}
+	for _, tt := range tests {
+		if rec := do(a, "POST", tt.path, tt.body, bearer...); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: status = %d, want 400", tt.path, tt.body, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/engine"
+	"github.com/swilcox/led-kurokku-go/redis"
+)
+
+// version is set at build time with -ldflags "-X main.version=...".
+var version = "dev"
+
+// heartbeat publishes this clock's status to Redis every interval.
+type heartbeat struct {
+	rds      *redis.Client
+	ctrl     *engine.Control
+	errs     *engine.ErrorLog // failures reported by main and the engine
+	instance string
+	display  config.DisplayType
+	started  time.Time
+	interval time.Duration
+
+	mu         sync.Mutex
+	configHash string // of the config the engine is running
+}
+
+func (h *heartbeat) setConfigHash(hash string) {
+	h.mu.Lock()
+	h.configHash = hash
+	h.mu.Unlock()
+}
+
+// run publishes heartbeats until ctx is cancelled. Each expires after three
+// intervals, so a clock that stops reporting drops out of Redis.
+func (h *heartbeat) run(ctx context.Context) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		h.publish(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *heartbeat) publish(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if err := h.rds.Ping(ctx); err != nil {
+		h.errs.Printf("heartbeat: redis ping failed: %v", err)
+		return
+	}
+	status := h.ctrl.Status()
+	lastErr, lastErrAt := h.errs.Last()
+	h.mu.Lock()
+	hb := redis.Heartbeat{
+		Instance:     h.instance,
+		Version:      version,
+		Started:      h.started,
+		Interval:     h.interval,
+		Display:      string(h.display),
+		Widget:       status.Widget,
+		Brightness:   status.Brightness,
+		LastError:    lastErr,
+		LastErrorAt:  lastErrAt,
+		RedisLatency: time.Since(start),
+		ConfigHash:   h.configHash,
+	}
+	h.mu.Unlock()
+	if err := h.rds.PublishHeartbeat(ctx, hb, 3*h.interval); err != nil {
+		h.errs.Printf("heartbeat: %v", err)
+	}
+}
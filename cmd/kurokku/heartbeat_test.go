@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/swilcox/led-kurokku-go/engine"
+	"github.com/swilcox/led-kurokku-go/redis"
+)
+
+func TestHeartbeat_Publish(t *testing.T) {
+	mr := miniredis.RunT(t)
+	t.Setenv("REDIS_URL", "redis://"+mr.Addr())
+	rds, err := redis.NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rds.Close()
+
+	errs := &engine.ErrorLog{}
+	errs.Printf("config reload error: %v", "boom")
+	h := &heartbeat{
+		rds:      rds,
+		ctrl:     engine.NewControl(),
+		errs:     errs,
+		instance: "kitchen",
+		display:  "terminal",
+		started:  time.Now().Add(-time.Minute),
+		interval: 15 * time.Second,
+	}
+	h.setConfigHash("abc123")
+	h.publish(context.Background())
+
+	key := redis.HeartbeatKeyPrefix + "kitchen"
+	if !mr.Exists(key) {
+		t.Fatalf("%s not written", key)
+	}
+	want := map[string]string{
+		"instance":    "kitchen",
+		"version":     version,
+		"display":     "terminal",
+		"interval_s":  "15",
+		"config_hash": "abc123",
+		"last_error":  "config reload error: boom",
+	}
+	for field, v := range want {
+		if got := mr.HGet(key, field); got != v {
+			t.Errorf("%s = %q, want %q", field, got, v)
+		}
+	}
+	if up, _ := strconv.Atoi(mr.HGet(key, "uptime_s")); up < 60 {
+		t.Errorf("uptime_s = %d, want at least 60", up)
+	}
+	if mr.HGet(key, "last_error_at") == "" {
+		t.Error("last_error_at not set")
+	}
+	if ttl := mr.TTL(key); ttl != 45*time.Second {
+		t.Errorf("TTL = %v, want three intervals", ttl)
+	}
+}
+
+func TestHeartbeat_NoErrorReported(t *testing.T) {
+	mr := miniredis.RunT(t)
+	t.Setenv("REDIS_URL", "redis://"+mr.Addr())
+	rds, err := redis.NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rds.Close()
+
+	// Ordinary log output mentioning a failure is not an error.
+	log.Printf("redis failover ok")
+	h := &heartbeat{
+		rds:      rds,
+		ctrl:     engine.NewControl(),
+		errs:     &engine.ErrorLog{},
+		instance: "office",
+		started:  time.Now(),
+		interval: time.Second,
+	}
+	h.publish(context.Background())
+
+	key := redis.HeartbeatKeyPrefix + "office"
+	if got := mr.HGet(key, "last_error"); got != "" {
+		t.Errorf("last_error = %q, want empty", got)
+	}
+	if got := mr.HGet(key, "last_error_at"); got != "" {
+		t.Errorf("last_error_at = %q, want empty", got)
+	}
+}
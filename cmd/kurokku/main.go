@@ -5,35 +5,54 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/swilcox/led-kurokku-go/alertmanager"
 	"github.com/swilcox/led-kurokku-go/config"
 	"github.com/swilcox/led-kurokku-go/display"
 	"github.com/swilcox/led-kurokku-go/engine"
 	"github.com/swilcox/led-kurokku-go/redis"
+	"github.com/swilcox/led-kurokku-go/webhook"
 )
 
 func main() {
 	displayOverride := flag.String("display", "", "display type override (terminal, max7219, tm1637, ht16k33, terminal_seg7, terminal_seg14)")
 	configPath := flag.String("config", "config.json", "path to config file")
+	listenAddr := flag.String("listen", "", "HTTP API listen address, e.g. :8081 (disabled when empty)")
+	pushRate := flag.Int("push-rate", 60, "push webhook requests allowed per minute")
+	execAllow := flag.String("exec-allow", "", "comma-separated command lines, with args, exec widgets may run when the config comes from Redis")
+	envAllow := flag.String("env-allow", "", "comma-separated environment variables templates may read when the config comes from Redis")
+	fileRoot := flag.String("file-root", "", "directory file widgets may read when the config comes from Redis")
+	heartbeatInterval := flag.Duration("heartbeat", 15*time.Second, "interval of the heartbeat published to Redis (disabled when 0)")
 	flag.Parse()
 
+	started := time.Now()
+	// Failures reported here and by the engine show up in the heartbeat.
+	errs := &engine.ErrorLog{}
+
 	// Initialize Redis (optional).
 	rds, err := redis.NewFromEnv()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "redis config error: %v\n", err)
 		os.Exit(1)
 	}
+	// Without a connection at startup the client is kept only as a probe,
+	// so the splash and network widget report Redis as down.
+	var redisProbe *redis.Client
 	if rds != nil {
 		pingCtx, pingCancel := context.WithTimeout(context.Background(), 5*time.Second)
 		if err := rds.Ping(pingCtx); err != nil {
-			log.Printf("redis ping failed, running without redis: %v", err)
-			rds = nil
+			errs.Printf("redis ping failed, running without redis: %v", err)
+			redisProbe, rds = rds, nil
+			defer redisProbe.Close()
 		} else {
 			log.Println("redis connected")
+			rds.SetInstance(instanceID())
 			defer rds.Close()
 		}
 		pingCancel()
@@ -46,13 +65,23 @@ func main() {
 		redisCfg, found, fetchErr := rds.FetchConfig(loadCtx)
 		loadCancel()
 		if fetchErr != nil {
-			log.Printf("redis config fetch error, falling back to file: %v", fetchErr)
+			errs.Printf("redis config fetch error, falling back to file: %v", fetchErr)
 		} else if found {
 			cfg = redisCfg
 			log.Println("config loaded from Redis")
 		}
 	}
+
+	// Only the local config file may run any command or read any file or
+	// variable; a config from Redis is limited to -exec-allow, -env-allow
+	// and -file-root.
+	policy := engine.Policy{
+		ExecAllow: splitList(*execAllow),
+		EnvAllow:  splitList(*envAllow),
+		FileRoot:  *fileRoot,
+	}
 	if cfg == nil {
+		policy.Trusted = true
 		cfg, err = config.Load(*configPath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "config error: %v\n", err)
@@ -60,6 +89,9 @@ func main() {
 		}
 	}
 
+	// The hash is taken before the overrides below so it matches the source.
+	configHash := cfg.Hash()
+
 	// CLI -display flag overrides config display.type
 	if *displayOverride != "" {
 		cfg.Display.Type = config.DisplayType(*displayOverride)
@@ -88,16 +120,86 @@ func main() {
 	var configCh <-chan struct{}
 	if rds != nil {
 		if ch, err := rds.SubscribeConfig(ctx); err != nil {
-			log.Printf("config subscribe failed: %v", err)
+			errs.Printf("config subscribe failed: %v", err)
 		} else {
 			configCh = ch
 		}
 	}
 
+	// The acker, the control and the in-process stores are shared across engine restarts
+	// so the HTTP API keeps working.
+	acker := engine.NewAcker(rds)
+	if cfg.AckButton != "" {
+		// The pin is set up once; changing ack_button takes a restart.
+		go acker.WatchButton(ctx, cfg.AckButton)
+	}
+	ctrl := engine.NewControl()
+	alerts := engine.NewAlertList()
+	texts := engine.NewTextStore()
+	var store pushStore = memStore{alerts, texts}
+	if rds != nil {
+		store = rds
+	}
+	if *listenAddr != "" {
+		// The push token and secret also guard the API's other endpoints
+		// that change state.
+		var push *webhook.Handler
+		auth := webhook.Auth{Token: os.Getenv("KUROKKU_PUSH_TOKEN"), Secret: os.Getenv("KUROKKU_PUSH_SECRET")}
+		if auth.Enabled() {
+			push = &webhook.Handler{
+				Store:   store,
+				Token:   auth.Token,
+				Secret:  auth.Secret,
+				Limiter: webhook.NewLimiter(*pushRate),
+			}
+		} else {
+			log.Println("push and Alertmanager webhooks, HTTP acks and control disabled: set KUROKKU_PUSH_TOKEN or KUROKKU_PUSH_SECRET")
+		}
+		srv := &http.Server{Addr: *listenAddr, Handler: newAPI(acker, ctrl, store, push, auth)}
+		go func() {
+			log.Printf("HTTP API listening on %s", *listenAddr)
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errs.Printf("HTTP API: %v", err)
+			}
+		}()
+		defer srv.Close()
+	}
+
+	var hb *heartbeat
+	if rds != nil && *heartbeatInterval > 0 {
+		hb = &heartbeat{
+			rds:      rds,
+			ctrl:     ctrl,
+			errs:     errs,
+			instance: instanceID(),
+			display:  cfg.Display.Type,
+			started:  started,
+			interval: *heartbeatInterval,
+		}
+		hb.setConfigHash(configHash)
+		go hb.run(ctx)
+	}
+
+	splash := engine.New(disp, cfg, rds)
+	if redisProbe != nil {
+		splash.SetRedisProbe(redisProbe)
+	}
+	splash.RunSplash(ctx)
+
 	for {
 		engCtx, engCancel := context.WithCancel(ctx)
 		done := make(chan error, 1)
-		go func() { done <- engine.New(disp, cfg, rds).Run(engCtx) }()
+		eng := engine.New(disp, cfg, rds)
+		eng.SetAcker(acker)
+		eng.SetControl(ctrl)
+		eng.SetPolicy(policy)
+		eng.SetErrorLog(errs)
+		if redisProbe != nil {
+			eng.SetRedisProbe(redisProbe)
+		}
+		eng.SetAlertList(alerts)
+		eng.SetTextStore(texts)
+		go func() { done <- eng.Run(engCtx) }()
 
 		select {
 		case err := <-done:
@@ -116,10 +218,14 @@ func main() {
 			newCfg, found, fetchErr := rds.FetchConfig(reloadCtx)
 			reloadCancel()
 			if fetchErr != nil {
-				log.Printf("config reload error: %v", fetchErr)
+				errs.Printf("config reload error: %v", fetchErr)
 			} else if found {
 				cfg = newCfg
+				policy.Trusted = false
 				log.Println("config reloaded from Redis")
+				if hb != nil {
+					hb.setConfigHash(cfg.Hash())
+				}
 			}
 			// loop → restart engine with (possibly updated) cfg
 
@@ -132,6 +238,43 @@ func main() {
 	}
 }
 
+// instanceID identifies this clock in the alert log and its heartbeat:
+// $KUROKKU_INSTANCE_ID, or the hostname.
+func instanceID() string {
+	if id := os.Getenv("KUROKKU_INSTANCE_ID"); id != "" {
+		return id
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		log.Printf("hostname: %v", err)
+		return "kurokku"
+	}
+	return host
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// pushStore receives alerts and text from the webhooks.
+type pushStore interface {
+	alertmanager.Store
+	webhook.Store
+}
+
+// memStore combines the in-process stores for clocks without Redis.
+type memStore struct {
+	*engine.AlertList
+	*engine.TextStore
+}
+
 func createDisplay(dc config.DisplayConfig) (display.Display, error) {
 	switch dc.Type {
 	case config.DisplayTerminal:
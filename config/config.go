@@ -1,6 +1,8 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -75,8 +77,36 @@ type Config struct {
 	Location   *LocationConfig  `json:"location,omitempty"`
 	Brightness BrightnessConfig `json:"brightness"`
 	Widgets    []WidgetConfig   `json:"widgets"`
+	// NetworkSplash shows the network widget for this long at startup.
+	NetworkSplash Duration `json:"network_splash,omitempty"`
+	// AckButton is the GPIO pin of a push button (wired to ground) that
+	// acknowledges all pending alerts, e.g. "GPIO17".
+	AckButton string `json:"ack_button,omitempty"`
+	// AlertSchedules maps a priority to the cron schedule of alerts at that
+	// priority that have none of their own. Nil uses DefaultAlertSchedules.
+	AlertSchedules map[int]string `json:"alert_schedules,omitempty"`
+	// SeverityProfiles overrides the presentation of alerts by severity;
+	// severities not listed use DefaultSeverityProfiles.
+	SeverityProfiles map[string]SeverityProfile `json:"severity_profiles,omitempty"`
+	// Interrupts limits which new Redis alerts cut into the widget rotation.
+	Interrupts InterruptConfig `json:"interrupts,omitempty"`
 }
 
+// InterruptConfig limits which newly created Redis alerts interrupt the
+// widget rotation. Other alerts wait for the alert widget's turn.
+type InterruptConfig struct {
+	// MaxPriority is the least urgent priority that interrupts (lower
+	// numbers are more urgent). Nil lets every new alert interrupt.
+	MaxPriority *int `json:"max_priority,omitempty"`
+	// Quiet hours (HH:MM, may span midnight) let only critical alerts interrupt.
+	QuietStart string `json:"quiet_start,omitempty"`
+	QuietEnd   string `json:"quiet_end,omitempty"`
+}
+
+// DefaultAlertSchedules throttles priority-10 alerts to every 10 minutes
+// unless alert_schedules is configured.
+var DefaultAlertSchedules = map[int]string{10: "*/10 * * * *"}
+
 // BrightnessConfig controls time-of-day brightness.
 type BrightnessConfig struct {
 	High        byte   `json:"high"`
@@ -86,6 +116,30 @@ type BrightnessConfig struct {
 	UseLocation bool   `json:"use_location,omitempty"`
 }
 
+// Alert severities.
+const (
+	SeverityInfo     = "info"
+	SeverityWarn     = "warn"
+	SeverityCritical = "critical"
+)
+
+// SeverityProfile describes how alerts of one severity are presented.
+type SeverityProfile struct {
+	Flashes       int      `json:"flashes,omitempty"`        // whole-display flashes before the text; several strobe
+	FlashInterval Duration `json:"flash_interval,omitempty"` // on and off time of each flash, default 100ms
+	Invert        bool     `json:"invert,omitempty"`         // inverted text; blinking text on segment displays
+	MaxBrightness bool     `json:"max_brightness,omitempty"` // full brightness while shown
+	Icon          bool     `json:"icon,omitempty"`           // leading warning icon
+}
+
+// DefaultSeverityProfiles gives warnings a single flash and critical alerts
+// a strobe, inverted text, full brightness and a warning icon.
+var DefaultSeverityProfiles = map[string]SeverityProfile{
+	SeverityInfo:     {},
+	SeverityWarn:     {Flashes: 1},
+	SeverityCritical: {Flashes: 6, Invert: true, MaxBrightness: true, Icon: true},
+}
+
 // AlertConfig describes a single alert entry.
 type AlertConfig struct {
 	ID                string   `json:"id"`
@@ -93,6 +147,84 @@ type AlertConfig struct {
 	Priority          int      `json:"priority"`
 	DisplayDuration   Duration `json:"display_duration"`
 	DeleteAfterDisplay bool    `json:"delete_after_display"`
+	// Acknowledgement: an ack_required alert is shown every cycle, and never
+	// deleted after display, until it is acknowledged.
+	AckRequired bool       `json:"ack_required,omitempty"`
+	AckedAt     *time.Time `json:"acked_at,omitempty"`
+	AckedBy     string     `json:"acked_by,omitempty"` // "redis", "http" or "button"
+	// Time window: the alert is shown from starts_at until expires_at, when
+	// it is deleted, and hidden until snooze_until. Schedule is a cron
+	// expression limiting it to matching minutes.
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Schedule    string     `json:"schedule,omitempty"`
+	SnoozeUntil *time.Time `json:"snooze_until,omitempty"`
+	Severity    string     `json:"severity,omitempty"` // "info" (default), "warn" or "critical"
+	// Alerts with the same fingerprint (or message) are shown once with a
+	// count; alerts in the same group are shown together after a summary.
+	Group       string `json:"group,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// ProfileFor returns the presentation profile for the alert's severity from
+// profiles, falling back to DefaultSeverityProfiles. Unknown severities get
+// the zero profile.
+func (a AlertConfig) ProfileFor(profiles map[string]SeverityProfile) SeverityProfile {
+	if p, ok := profiles[a.Severity]; ok {
+		return p
+	}
+	return DefaultSeverityProfiles[a.Severity]
+}
+
+// Expired reports whether the alert's expires_at has passed.
+func (a AlertConfig) Expired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
+}
+
+// Live reports whether the alert is within its time window at now: started,
+// not expired and not snoozed. The schedule is checked separately.
+func (a AlertConfig) Live(now time.Time) bool {
+	if a.StartsAt != nil && now.Before(*a.StartsAt) {
+		return false
+	}
+	if a.SnoozeUntil != nil && now.Before(*a.SnoozeUntil) {
+		return false
+	}
+	return !a.Expired(now)
+}
+
+// ScheduleFor returns the alert's schedule, falling back to the schedule
+// for its priority in byPriority (DefaultAlertSchedules when nil).
+func (a AlertConfig) ScheduleFor(byPriority map[int]string) string {
+	if a.Schedule != "" {
+		return a.Schedule
+	}
+	if byPriority == nil {
+		byPriority = DefaultAlertSchedules
+	}
+	return byPriority[a.Priority]
+}
+
+// KeepState returns a with the acknowledgement and snooze recorded on prev,
+// an earlier version of the same alert, unless a sets its own.
+func (a AlertConfig) KeepState(prev AlertConfig) AlertConfig {
+	if a.AckedAt == nil {
+		a.AckedAt, a.AckedBy = prev.AckedAt, prev.AckedBy
+	}
+	if a.SnoozeUntil == nil {
+		a.SnoozeUntil = prev.SnoozeUntil
+	}
+	return a
+}
+
+// Pending reports whether the alert is waiting to be acknowledged.
+func (a AlertConfig) Pending() bool {
+	return a.AckRequired && a.AckedAt == nil
+}
+
+// Acked reports whether an ack_required alert has been acknowledged.
+func (a AlertConfig) Acked() bool {
+	return a.AckRequired && a.AckedAt != nil
 }
 
 // FrameConfig describes a single pixel animation frame.
@@ -114,6 +246,7 @@ type WidgetConfig struct {
 	Enabled  bool     `json:"enabled"`
 	Duration Duration `json:"duration"`
 	Cron     string   `json:"cron,omitempty"` // optional cron expression, e.g. "*/15 * * * *"
+	ID       string   `json:"id,omitempty"`   // optional name for the control API, e.g. "weather"
 	// Clock
 	Format24h *bool `json:"format_24h,omitempty"`
 	// Message / Alert
@@ -129,6 +262,68 @@ type WidgetConfig struct {
 	Frames        []FrameConfig       `json:"frames,omitempty"`
 	SegmentFrames []SegmentFrameConfig `json:"segment_frames,omitempty"`
 	FrameDuration Duration            `json:"frame_duration,omitempty"`
+	// Params tunes procedural animations: speed, density, seed, invert.
+	Params map[string]interface{} `json:"params,omitempty"`
+	// Prometheus
+	URL        string `json:"url,omitempty"`
+	Query      string `json:"query,omitempty"`
+	Label      string `json:"label,omitempty"`       // vector result label to select/show
+	LabelValue string `json:"label_value,omitempty"` // pick the series where label == label_value
+	// Numeric formatting and thresholds
+	Prefix         string   `json:"prefix,omitempty"`
+	Unit           string   `json:"unit,omitempty"`
+	Format         string   `json:"format,omitempty"` // printf verb, e.g. "%.1f"
+	Scale          float64  `json:"scale,omitempty"`
+	Threshold      *float64 `json:"threshold,omitempty"`
+	ThresholdBelow bool     `json:"threshold_below,omitempty"`
+	AlertStyle     string   `json:"alert_style,omitempty"` // "blink" (default) or "brightness"
+	// System info
+	Metrics  []string `json:"metrics,omitempty"` // temp, load, mem, uptime, disk
+	ProcRoot string   `json:"proc_root,omitempty"`
+	SysRoot  string   `json:"sys_root,omitempty"`
+	DiskPath string   `json:"disk_path,omitempty"`
+	Interval Duration `json:"interval,omitempty"`
+	// Network
+	Interfaces []string `json:"interfaces,omitempty"`
+	IPv6       bool     `json:"ipv6,omitempty"`
+	// Exec
+	Command  string   `json:"command,omitempty"`
+	Args     []string `json:"args,omitempty"`
+	Timeout  Duration `json:"timeout,omitempty"`
+	Output   string   `json:"output,omitempty"` // "first_line" (default) or "all"
+	EnvAllow []string `json:"env_allow,omitempty"`
+	// File tail
+	Path      string `json:"path,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`   // regexp; only matching lines are shown
+	Transform string `json:"transform,omitempty"` // capture-group template, e.g. "build $1"
+	// Calendar (source is path or url)
+	Timezone    string   `json:"timezone,omitempty"` // defaults to location.timezone
+	LookAhead   Duration `json:"look_ahead,omitempty"`
+	ShowCurrent bool     `json:"show_current,omitempty"`
+	// Feed (a single url/interval may be used instead of feeds)
+	Feeds []FeedConfig `json:"feeds,omitempty"`
+	Count int          `json:"count,omitempty"` // headlines per run
+	// MPD (timeout applies to dial and commands)
+	Addr     string `json:"addr,omitempty"` // host:port, default localhost:6600
+	Password string `json:"password,omitempty"`
+	// Transit (GTFS zip in path; realtime is a file path or URL)
+	StopIDs  []string `json:"stop_ids,omitempty"`
+	Routes   []string `json:"routes,omitempty"` // route_id or route_short_name
+	Realtime string   `json:"realtime,omitempty"`
+	// Gauge (value from dynamic_source, url + json_path, or path)
+	JSONPath string  `json:"json_path,omitempty"` // e.g. "jobs.0.progress"
+	Min      float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"` // default 100
+	Style    string  `json:"style,omitempty"`
+	// Chart (Redis series in dynamic_source; min/max fix the scale when set)
+	Samples   int  `json:"samples,omitempty"`
+	ShowValue bool `json:"show_value,omitempty"`
+}
+
+// FeedConfig describes one RSS/Atom feed of a feed widget.
+type FeedConfig struct {
+	URL      string   `json:"url"`
+	Interval Duration `json:"interval,omitempty"` // refresh interval, default 15m
 }
 
 // Parse parses JSON config data.
@@ -148,3 +343,14 @@ func Load(path string) (*Config, error) {
 	}
 	return Parse(data)
 }
+
+// Hash returns a short fingerprint of the config, comparing equal for
+// configs that parse to the same values regardless of their formatting.
+func (c *Config) Hash() string {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
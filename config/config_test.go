@@ -121,3 +121,83 @@ func TestParse_InvalidDuration(t *testing.T) {
 		t.Error("expected error for invalid duration in widget")
 	}
 }
+
+func TestAlertConfig_ScheduleFor(t *testing.T) {
+	p10 := config.AlertConfig{Priority: 10}
+	if got := p10.ScheduleFor(nil); got != "*/10 * * * *" {
+		t.Errorf("default schedule = %q, want */10 * * * *", got)
+	}
+	if got := p10.ScheduleFor(map[int]string{}); got != "" {
+		t.Errorf("schedule with empty map = %q, want none", got)
+	}
+	own := config.AlertConfig{Priority: 10, Schedule: "0 9 * * *"}
+	if got := own.ScheduleFor(nil); got != "0 9 * * *" {
+		t.Errorf("own schedule = %q, want 0 9 * * *", got)
+	}
+}
+
+func TestParse_AlertWindow(t *testing.T) {
+	cfg, err := config.Parse([]byte(`{
+		"alert_schedules": {"5": "0 * * * *"},
+		"widgets": [{"type": "alert", "alerts": [{
+			"id": "a", "message": "hi",
+			"starts_at": "2024-01-01T08:00:00Z",
+			"expires_at": "2024-01-01T18:00:00Z",
+			"snooze_until": "2024-01-01T09:00:00Z"
+		}]}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AlertSchedules[5] != "0 * * * *" {
+		t.Errorf("alert_schedules = %v", cfg.AlertSchedules)
+	}
+	a := cfg.Widgets[0].Alerts[0]
+	at := func(h int) time.Time { return time.Date(2024, 1, 1, h, 30, 0, 0, time.UTC) }
+	for h, live := range map[int]bool{7: false, 8: false, 9: true, 17: true, 18: false} {
+		if got := a.Live(at(h)); got != live {
+			t.Errorf("Live at %02d:30 = %v, want %v", h, got, live)
+		}
+	}
+	if a.Expired(at(17)) || !a.Expired(at(18)) {
+		t.Error("expected alert to expire at 18:00")
+	}
+}
+
+func TestAlertConfig_ProfileFor(t *testing.T) {
+	crit := config.AlertConfig{Severity: config.SeverityCritical}
+	if p := crit.ProfileFor(nil); !p.MaxBrightness || !p.Invert || !p.Icon || p.Flashes < 2 {
+		t.Errorf("default critical profile = %+v", p)
+	}
+	custom := map[string]config.SeverityProfile{config.SeverityCritical: {Flashes: 1}}
+	if p := crit.ProfileFor(custom); p != (config.SeverityProfile{Flashes: 1}) {
+		t.Errorf("configured critical profile = %+v", p)
+	}
+	warn := config.AlertConfig{Severity: config.SeverityWarn}
+	if p := warn.ProfileFor(custom); p.Flashes != 1 {
+		t.Errorf("warn profile should fall back to the default, got %+v", p)
+	}
+	if p := (config.AlertConfig{}).ProfileFor(nil); p != (config.SeverityProfile{}) {
+		t.Errorf("no severity should have no effects, got %+v", p)
+	}
+}
+
+func TestConfig_Hash(t *testing.T) {
+	a, err := config.Parse([]byte(`{"widgets":[{"type":"clock","enabled":true,"duration":"10s"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := config.Parse([]byte(`{
+		"widgets": [ {"enabled": true, "type": "clock", "duration": "10000ms"} ]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Hash() == "" || a.Hash() != b.Hash() {
+		t.Errorf("equal configs hash to %q and %q", a.Hash(), b.Hash())
+	}
+	b.Widgets[0].Duration = config.Duration(5 * time.Second)
+	if a.Hash() == b.Hash() {
+		t.Error("different configs should hash differently")
+	}
+}
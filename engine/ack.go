@@ -0,0 +1,272 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/button"
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/redis"
+)
+
+// AckAll acknowledges every pending alert when passed to Acker.Ack.
+const AckAll = "all"
+
+// Ack sources recorded in an alert's acked_by field.
+const (
+	AckSourceRedis  = "redis"
+	AckSourceHTTP   = "http"
+	AckSourceButton = "button"
+)
+
+// ErrUnknownAlert is returned by Acker.Ack for an alert that is neither in
+// the alert source nor waiting for an ack.
+var ErrUnknownAlert = errors.New("unknown alert")
+
+// ackStore persists acknowledgements. *redis.Client implements it.
+type ackStore interface {
+	AckAlert(ctx context.Context, id, source string, at time.Time) (bool, error)
+	ConsumeAcks(ctx context.Context) ([]string, error)
+}
+
+type ack struct {
+	at     time.Time
+	source string
+}
+
+// Acker tracks which ack_required alerts are waiting for an acknowledgement
+// and records acks from the Redis ack keys, the HTTP API and the ack button.
+// It implements widget.AckTracker and outlives engine restarts on reload.
+type Acker struct {
+	store   ackStore
+	nowFunc func() time.Time
+
+	mu      sync.Mutex
+	acks    map[string]ack
+	pending map[string]bool
+}
+
+// NewAcker creates an Acker that also records acks in Redis when rds is
+// non-nil.
+func NewAcker(rds *redis.Client) *Acker {
+	a := &Acker{acks: map[string]ack{}, pending: map[string]bool{}}
+	if rds != nil {
+		a.store = rds
+	}
+	return a
+}
+
+func (a *Acker) now() time.Time {
+	if a.nowFunc != nil {
+		return a.nowFunc()
+	}
+	return time.Now()
+}
+
+// Ack acknowledges alert id, or every pending alert when id is AckAll. It
+// returns ErrUnknownAlert when id is neither pending nor in the store.
+func (a *Acker) Ack(ctx context.Context, id, source string) error {
+	at := a.now()
+	a.mu.Lock()
+	ids := []string{id}
+	known := true
+	if id == AckAll {
+		ids = a.pendingLocked()
+	} else {
+		known = a.pending[id]
+	}
+	for _, id := range ids {
+		a.acks[id] = ack{at: at, source: source}
+		delete(a.pending, id)
+	}
+	a.mu.Unlock()
+
+	for _, id := range ids {
+		if a.store != nil {
+			found, err := a.store.AckAlert(ctx, id, source, at)
+			if err != nil {
+				return err
+			}
+			known = known || found
+		}
+		if !known {
+			return ErrUnknownAlert
+		}
+		log.Printf("alert %s acknowledged via %s", id, source)
+	}
+	return nil
+}
+
+// WatchButton acknowledges all pending alerts each time the push button on
+// GPIO pin is pressed, until ctx is cancelled. Call it once per process, not
+// per engine run, so the pin is only set up once.
+func (a *Acker) WatchButton(ctx context.Context, pin string) {
+	err := button.Watch(ctx, pin, func() {
+		if err := a.Ack(ctx, AckAll, AckSourceButton); err != nil {
+			log.Printf("ack button: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("ack button %s disabled: %v", pin, err)
+	}
+}
+
+// Apply fills in acks recorded in memory, which may not have reached the
+// alert source yet, and replaces the pending set with the alerts still
+// waiting for an ack.
+func (a *Acker) Apply(alerts []config.AlertConfig) []config.AlertConfig {
+	now := a.now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]config.AlertConfig, len(alerts))
+	pending := map[string]bool{}
+	seen := map[string]bool{}
+	for i, alert := range alerts {
+		seen[alert.ID] = true
+		if k, ok := a.acks[alert.ID]; ok && alert.AckRequired && alert.AckedAt == nil {
+			at := k.at
+			alert.AckedAt = &at
+			alert.AckedBy = k.source
+		}
+		// Alerts outside their time window do not light the indicator.
+		if alert.Pending() && alert.Live(now) {
+			pending[alert.ID] = true
+		}
+		out[i] = alert
+	}
+	// Forget acks for alerts that no longer exist.
+	for id := range a.acks {
+		if !seen[id] {
+			delete(a.acks, id)
+		}
+	}
+	a.pending = pending
+	return out
+}
+
+// Pending returns the IDs of alerts waiting for an ack, sorted.
+func (a *Acker) Pending() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.pendingLocked()
+}
+
+func (a *Acker) pendingLocked() []string {
+	ids := make([]string, 0, len(a.pending))
+	for id := range a.pending {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (a *Acker) hasPending() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.pending) > 0
+}
+
+// consume handles acks requested through Redis ack keys, reporting
+// failures to errs.
+func (a *Acker) consume(ctx context.Context, errs *ErrorLog) {
+	if a.store == nil {
+		return
+	}
+	ids, err := a.store.ConsumeAcks(ctx)
+	if err != nil {
+		errs.Printf("redis ack fetch failed: %v", err)
+	}
+	for _, id := range ids {
+		err := a.Ack(ctx, id, AckSourceRedis)
+		switch {
+		case errors.Is(err, ErrUnknownAlert):
+			log.Printf("redis ack %s: %v", id, err)
+		case err != nil:
+			errs.Printf("redis ack %s: %v", id, err)
+		}
+	}
+}
+
+// ackLoop polls for Redis ack keys and keeps the pending set current so the
+// indicator shows between alert widget runs.
+func (e *Engine) ackLoop(ctx context.Context) {
+	ackTicker := time.NewTicker(2 * time.Second)
+	defer ackTicker.Stop()
+	refreshTicker := time.NewTicker(30 * time.Second)
+	defer refreshTicker.Stop()
+
+	refresh := func() {
+		alerts, err := e.rds.FetchAlerts(ctx)
+		if err != nil {
+			e.errs.Printf("redis ack refresh failed: %v", err)
+			return
+		}
+		e.acker.Apply(alerts)
+	}
+	refresh()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ackTicker.C:
+			e.acker.consume(ctx, e.errs)
+		case <-refreshTicker.C:
+			refresh()
+		}
+	}
+}
+
+// configAlerts returns the alerts of all enabled alert widgets.
+func (e *Engine) configAlerts() []config.AlertConfig {
+	var alerts []config.AlertConfig
+	for _, wc := range e.cfg.Widgets {
+		if wc.Enabled && wc.Type == "alert" {
+			alerts = append(alerts, wc.Alerts...)
+		}
+	}
+	return alerts
+}
+
+// withAckIndicator wraps disp so every frame shows an indicator while an
+// alert is waiting for an ack: the top-right pixel on pixel displays, the
+// colon on segment displays.
+func withAckIndicator(disp display.Display, a *Acker) display.Display {
+	switch d := disp.(type) {
+	case *pixelAckIndicator, *segmentAckIndicator:
+		return disp
+	case display.PixelDisplay:
+		return &pixelAckIndicator{PixelDisplay: d, acker: a}
+	case display.SegmentDisplay:
+		return &segmentAckIndicator{SegmentDisplay: d, acker: a}
+	}
+	return disp
+}
+
+type pixelAckIndicator struct {
+	display.PixelDisplay
+	acker *Acker
+}
+
+func (p *pixelAckIndicator) WriteFramebuffer(buf []byte) {
+	if len(buf) > 0 && p.acker.hasPending() {
+		b := make([]byte, len(buf))
+		copy(b, buf)
+		b[len(b)-1] |= 0x01
+		buf = b
+	}
+	p.PixelDisplay.WriteFramebuffer(buf)
+}
+
+type segmentAckIndicator struct {
+	display.SegmentDisplay
+	acker *Acker
+}
+
+func (s *segmentAckIndicator) WriteSegments(segments []uint16, colon bool) {
+	s.SegmentDisplay.WriteSegments(segments, colon || s.acker.hasPending())
+}
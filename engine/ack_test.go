@@ -0,0 +1,170 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+)
+
+// mockAckStore records acks and hands out queued ack key IDs.
+type mockAckStore struct {
+	acked   map[string]string
+	pending []string
+	missing map[string]bool // IDs reported as not found
+}
+
+func (m *mockAckStore) AckAlert(_ context.Context, id, source string, _ time.Time) (bool, error) {
+	if m.missing[id] {
+		return false, nil
+	}
+	if m.acked == nil {
+		m.acked = map[string]string{}
+	}
+	m.acked[id] = source
+	return true, nil
+}
+
+func (m *mockAckStore) ConsumeAcks(_ context.Context) ([]string, error) {
+	ids := m.pending
+	m.pending = nil
+	return ids, nil
+}
+
+func ackAlerts() []config.AlertConfig {
+	return []config.AlertConfig{
+		{ID: "a", Message: "A", AckRequired: true},
+		{ID: "b", Message: "B", AckRequired: true},
+		{ID: "c", Message: "C"},
+	}
+}
+
+func TestAcker_ApplyTracksPending(t *testing.T) {
+	a := NewAcker(nil)
+	a.Apply(ackAlerts())
+	if got := a.Pending(); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("Pending() = %v, want [a b]", got)
+	}
+}
+
+func TestAcker_AckRecordsAndApplies(t *testing.T) {
+	store := &mockAckStore{}
+	a := NewAcker(nil)
+	a.store = store
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	a.nowFunc = func() time.Time { return at }
+	a.Apply(ackAlerts())
+
+	if err := a.Ack(context.Background(), "a", AckSourceHTTP); err != nil {
+		t.Fatal(err)
+	}
+	if store.acked["a"] != AckSourceHTTP {
+		t.Errorf("store acks = %v, want a via http", store.acked)
+	}
+
+	// The source has not caught up yet; the in-memory ack fills the gap.
+	out := a.Apply(ackAlerts())
+	if !out[0].Acked() || !out[0].AckedAt.Equal(at) || out[0].AckedBy != AckSourceHTTP {
+		t.Errorf("alert a = %+v, want acked at %v via http", out[0], at)
+	}
+	if out[1].Acked() || out[2].AckedAt != nil {
+		t.Errorf("only alert a should be acked: %+v", out)
+	}
+	if got := a.Pending(); len(got) != 1 || got[0] != "b" {
+		t.Errorf("Pending() = %v, want [b]", got)
+	}
+}
+
+func TestAcker_AckUnknown(t *testing.T) {
+	a := NewAcker(nil)
+	a.Apply(ackAlerts())
+	if err := a.Ack(context.Background(), "a", AckSourceHTTP); err != nil {
+		t.Errorf("pending alert: %v", err)
+	}
+	if err := a.Ack(context.Background(), "nope", AckSourceHTTP); !errors.Is(err, ErrUnknownAlert) {
+		t.Errorf("unknown alert without a store: err = %v, want ErrUnknownAlert", err)
+	}
+
+	// The store knows alerts not waiting for an ack here, such as c.
+	a.store = &mockAckStore{missing: map[string]bool{"nope": true}}
+	if err := a.Ack(context.Background(), "c", AckSourceHTTP); err != nil {
+		t.Errorf("alert in the store: %v", err)
+	}
+	if err := a.Ack(context.Background(), "nope", AckSourceHTTP); !errors.Is(err, ErrUnknownAlert) {
+		t.Errorf("unknown alert: err = %v, want ErrUnknownAlert", err)
+	}
+}
+
+func TestAcker_AckAll(t *testing.T) {
+	store := &mockAckStore{}
+	a := NewAcker(nil)
+	a.store = store
+	a.Apply(ackAlerts())
+
+	if err := a.Ack(context.Background(), AckAll, AckSourceButton); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.acked) != 2 || store.acked["a"] != AckSourceButton || store.acked["b"] != AckSourceButton {
+		t.Errorf("store acks = %v, want a and b via button", store.acked)
+	}
+	if got := a.Pending(); len(got) != 0 {
+		t.Errorf("Pending() = %v, want none", got)
+	}
+}
+
+func TestAcker_ConsumeRedisAcks(t *testing.T) {
+	store := &mockAckStore{pending: []string{"b"}}
+	a := NewAcker(nil)
+	a.store = store
+	a.Apply(ackAlerts())
+
+	a.consume(context.Background(), nil)
+	if store.acked["b"] != AckSourceRedis {
+		t.Errorf("store acks = %v, want b via redis", store.acked)
+	}
+	if got := a.Pending(); len(got) != 1 || got[0] != "a" {
+		t.Errorf("Pending() = %v, want [a]", got)
+	}
+}
+
+func TestAckIndicator_Pixel(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	a := NewAcker(nil)
+	disp := withAckIndicator(spy, a)
+	if withAckIndicator(disp, a) != disp {
+		t.Error("expected an indicator not to be wrapped twice")
+	}
+	pd := disp.(interface{ WriteFramebuffer([]byte) })
+
+	buf := make([]byte, 32)
+	pd.WriteFramebuffer(buf)
+	a.Apply(ackAlerts())
+	pd.WriteFramebuffer(buf)
+
+	if spy.Frames[0][31] != 0 {
+		t.Errorf("expected no indicator without pending alerts, got %#x", spy.Frames[0][31])
+	}
+	if spy.Frames[1][31] != 0x01 {
+		t.Errorf("expected top-right pixel lit while alerts are pending, got %#x", spy.Frames[1][31])
+	}
+	if buf[31] != 0 {
+		t.Error("expected the caller's buffer to be left unchanged")
+	}
+}
+
+func TestAckIndicator_Segment(t *testing.T) {
+	spy := &testutil.SpySegmentDisplay{}
+	a := NewAcker(nil)
+	sd := withAckIndicator(spy, a).(interface{ WriteSegments([]uint16, bool) })
+
+	sd.WriteSegments([]uint16{0, 0, 0, 0}, false)
+	a.Apply(ackAlerts())
+	sd.WriteSegments([]uint16{0, 0, 0, 0}, false)
+
+	if spy.Calls[0].Colon || !spy.Calls[1].Colon {
+		t.Errorf("colon = %v, %v; want false, true", spy.Calls[0].Colon, spy.Calls[1].Colon)
+	}
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+)
+
+// AlertList is an in-process alert store used in place of Redis when it is
+// unavailable. It implements widget.AlertFetcher and, like the Acker,
+// outlives engine restarts on reload.
+type AlertList struct {
+	nowFunc func() time.Time
+
+	mu     sync.Mutex
+	alerts []config.AlertConfig
+}
+
+// NewAlertList creates an empty alert list.
+func NewAlertList() *AlertList {
+	return &AlertList{}
+}
+
+func (l *AlertList) now() time.Time {
+	if l.nowFunc != nil {
+		return l.nowFunc()
+	}
+	return time.Now()
+}
+
+// SetAlert adds a or replaces the alert with the same ID, keeping its
+// acknowledgement and snooze.
+func (l *AlertList) SetAlert(_ context.Context, a config.AlertConfig) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, prev := range l.alerts {
+		if prev.ID == a.ID {
+			l.alerts[i] = a.KeepState(prev)
+			return nil
+		}
+	}
+	l.alerts = append(l.alerts, a)
+	return nil
+}
+
+// DeleteAlert removes the alert with the given ID, if any.
+func (l *AlertList) DeleteAlert(_ context.Context, id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, a := range l.alerts {
+		if a.ID == id {
+			l.alerts = append(l.alerts[:i], l.alerts[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// FetchAlerts returns the live alerts, dropping expired ones like
+// redis.Client.FetchAlerts.
+func (l *AlertList) FetchAlerts(_ context.Context) ([]config.AlertConfig, error) {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	kept := l.alerts[:0]
+	var live []config.AlertConfig
+	for _, a := range l.alerts {
+		if a.Expired(now) {
+			continue
+		}
+		kept = append(kept, a)
+		if a.Live(now) {
+			live = append(live, a)
+		}
+	}
+	l.alerts = kept
+	return live, nil
+}
@@ -0,0 +1,32 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+)
+
+func TestAlertList_SetKeepsAckAndDropsExpired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := NewAlertList()
+	l.nowFunc = func() time.Time { return now }
+	ctx := context.Background()
+
+	acked := now.Add(-time.Minute)
+	past := now.Add(-time.Second)
+	l.SetAlert(ctx, config.AlertConfig{ID: "a", Message: "one", AckedAt: &acked})     //nolint:errcheck
+	l.SetAlert(ctx, config.AlertConfig{ID: "a", Message: "two"})                      //nolint:errcheck
+	l.SetAlert(ctx, config.AlertConfig{ID: "old", Message: "gone", ExpiresAt: &past}) //nolint:errcheck
+	l.SetAlert(ctx, config.AlertConfig{ID: "b", Message: "three"})                    //nolint:errcheck
+	l.DeleteAlert(ctx, "b")                                                           //nolint:errcheck
+
+	got, _ := l.FetchAlerts(ctx)
+	if len(got) != 1 || got[0].Message != "two" {
+		t.Fatalf("alerts = %+v, want only the replaced alert a", got)
+	}
+	if got[0].AckedAt == nil || !got[0].AckedAt.Equal(acked) {
+		t.Errorf("acked_at = %v, want the earlier ack kept", got[0].AckedAt)
+	}
+}
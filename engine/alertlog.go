@@ -0,0 +1,43 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/redis"
+)
+
+// alertLogger records alert lifecycle events in the alert log.
+// *redis.Client implements it; creations, deletions, expiries and acks that
+// go through the client are recorded there.
+type alertLogger interface {
+	LogAlert(ctx context.Context, event string, a config.AlertConfig, detail string)
+	// LogAlertExpired records that Redis expired alert id, unless another
+	// clock already did.
+	LogAlertExpired(ctx context.Context, id string)
+}
+
+// logAlert records event for a when the store keeps an alert log.
+func (e *Engine) logAlert(ctx context.Context, event string, a config.AlertConfig) {
+	if l, ok := e.rds.(alertLogger); ok {
+		l.LogAlert(ctx, event, a, "")
+	}
+}
+
+// logExpired records that Redis expired alert id.
+func (e *Engine) logExpired(ctx context.Context, id string) {
+	if l, ok := e.rds.(alertLogger); ok {
+		l.LogAlertExpired(ctx, id)
+	}
+}
+
+// onAlertDisplay returns the alert widgets' OnDisplay hook, recording each
+// displayed alert, or nil without an alert log.
+func (e *Engine) onAlertDisplay() func(ctx context.Context, a config.AlertConfig) {
+	if _, ok := e.rds.(alertLogger); !ok {
+		return nil
+	}
+	return func(ctx context.Context, a config.AlertConfig) {
+		e.logAlert(ctx, redis.AlertDisplayed, a)
+	}
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/redis"
+)
+
+func TestAlertLog_RecordsLifecycle(t *testing.T) {
+	m := &mockRedis{}
+	expires := time.Now().Add(time.Hour)
+	a := config.AlertConfig{ID: "a", Message: "A", DisplayDuration: config.Duration(10 * time.Millisecond), ExpiresAt: &expires}
+	e := New(&testutil.SpyDisplay{}, &config.Config{}, nil)
+	e.rds = m
+	known := map[string]bool{}
+	ctx := context.Background()
+
+	m.SetAlert(ctx, a) //nolint:errcheck
+	e.shouldInterrupt(ctx, known, redis.AlertEvent{ID: "a"})
+	m.SetAlert(ctx, a) //nolint:errcheck // rewrite: not logged again
+	e.shouldInterrupt(ctx, known, redis.AlertEvent{ID: "a"})
+	e.runInterruptAlerts(ctx)
+	m.setAlerts()
+	e.shouldInterrupt(ctx, known, redis.AlertEvent{ID: "a", Removed: true, Expired: true})
+	e.shouldInterrupt(ctx, known, redis.AlertEvent{ID: "gone", Removed: true, Expired: true})
+
+	want := "created a, displayed a, expired a"
+	if got := strings.Join(m.logged, ", "); got != want {
+		t.Errorf("alert log = %q, want %q", got, want)
+	}
+}
+
+func TestAlertLog_OncePerEventAcrossClocks(t *testing.T) {
+	m := &mockRedis{}
+	ctx := context.Background()
+	var clocks []*Engine
+	var known []map[string]bool
+	for range 3 {
+		e := New(&testutil.SpyDisplay{}, &config.Config{}, nil)
+		e.rds = m
+		clocks = append(clocks, e)
+		known = append(known, map[string]bool{})
+	}
+	// Every clock sees the keyspace events of one alert's lifecycle.
+	event := func(ev redis.AlertEvent) {
+		for i, e := range clocks {
+			e.shouldInterrupt(ctx, known[i], ev)
+		}
+	}
+
+	expires := time.Now().Add(time.Hour)
+	m.SetAlert(ctx, config.AlertConfig{ID: "a", Message: "A", ExpiresAt: &expires}) //nolint:errcheck
+	event(redis.AlertEvent{ID: "a"})
+	m.setAlerts()
+	event(redis.AlertEvent{ID: "a", Removed: true, Expired: true})
+
+	want := "created a, expired a"
+	if got := strings.Join(m.logged, ", "); got != want {
+		t.Errorf("alert log = %q, want %q", got, want)
+	}
+}
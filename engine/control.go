@@ -0,0 +1,251 @@
+package engine
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display"
+)
+
+// Control command kinds.
+const (
+	cmdNext = iota
+	cmdPrev
+	cmdPause
+	cmdResume
+	cmdPin
+	cmdShow
+)
+
+// command is a request to the running engine's rotation.
+type command struct {
+	kind   int
+	widget string        // cmdPin: rotation index or widget ID
+	d      time.Duration // cmdPin, cmdShow
+	text   string        // cmdShow
+	reply  chan error
+}
+
+// Status describes what the clock is showing.
+type Status struct {
+	Widget    string           `json:"widget"`
+	Index     int              `json:"index"`
+	Remaining *config.Duration `json:"remaining"` // null: until the widget ends
+	Paused    bool             `json:"paused"`
+	PinnedTo  *time.Time       `json:"pinned_until,omitempty"`
+	// Brightness is the current level; Override is set while a brightness
+	// override is active, until OverrideUntil.
+	Brightness    byte       `json:"brightness"`
+	Override      *byte      `json:"brightness_override,omitempty"`
+	OverrideUntil *time.Time `json:"brightness_override_until,omitempty"`
+	// Frame is the last frame written, in hex: one byte per column on pixel
+	// displays, four hex digits per digit on segment displays.
+	Frame string `json:"frame"`
+	Colon bool   `json:"colon,omitempty"`
+}
+
+// Control is the runtime control surface of the engine: rotation commands,
+// messages and a brightness override, used by the HTTP API. Like the Acker
+// it outlives engine restarts; the running engine takes its commands.
+type Control struct {
+	cmds   chan command
+	bright chan struct{} // signals a brightness override change
+
+	mu       sync.Mutex
+	status   Status
+	deadline time.Time // end of the active widget's time; zero when paused or unlimited
+	left     time.Duration
+	level    byte
+	until    time.Time // end of the brightness override; zero when none
+	frame    []byte
+	segs     []uint16
+}
+
+// NewControl creates a Control.
+func NewControl() *Control {
+	return &Control{cmds: make(chan command), bright: make(chan struct{}, 1)}
+}
+
+// send hands cmd to the engine and waits for its reply.
+func (c *Control) send(ctx context.Context, cmd command) error {
+	cmd.reply = make(chan error, 1)
+	select {
+	case c.cmds <- cmd:
+	case <-ctx.Done():
+		return fmt.Errorf("engine busy: %w", ctx.Err())
+	}
+	select {
+	case err := <-cmd.reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Next skips to the next widget.
+func (c *Control) Next(ctx context.Context) error { return c.send(ctx, command{kind: cmdNext}) }
+
+// Prev goes back to the previous widget.
+func (c *Control) Prev(ctx context.Context) error { return c.send(ctx, command{kind: cmdPrev}) }
+
+// Pause keeps the current widget on the display until Resume.
+func (c *Control) Pause(ctx context.Context) error { return c.send(ctx, command{kind: cmdPause}) }
+
+// Resume continues a paused rotation, or ends a pin.
+func (c *Control) Resume(ctx context.Context) error { return c.send(ctx, command{kind: cmdResume}) }
+
+// Pin shows the widget with the given rotation index or ID for d.
+func (c *Control) Pin(ctx context.Context, widget string, d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("pin duration must be positive")
+	}
+	return c.send(ctx, command{kind: cmdPin, widget: widget, d: d})
+}
+
+// Show interrupts the rotation with text for d (10s when 0).
+func (c *Control) Show(ctx context.Context, text string, d time.Duration) error {
+	if d <= 0 {
+		d = 10 * time.Second
+	}
+	return c.send(ctx, command{kind: cmdShow, text: text, d: d})
+}
+
+// SetBrightness overrides the scheduled brightness with level (0-15) for d.
+// Alerts holding full brightness still take precedence. A zero d clears the
+// override.
+func (c *Control) SetBrightness(level byte, d time.Duration) error {
+	if level > 15 {
+		return fmt.Errorf("brightness %d out of range 0-15", level)
+	}
+	c.mu.Lock()
+	c.level, c.until = level, time.Time{}
+	if d > 0 {
+		c.until = time.Now().Add(d)
+	}
+	c.mu.Unlock()
+	select {
+	case c.bright <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// brightness returns the override level and its end while one is active.
+func (c *Control) brightness() (level byte, until time.Time, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.until.IsZero() || !time.Now().Before(c.until) {
+		return 0, time.Time{}, false
+	}
+	return c.level, c.until, true
+}
+
+// Status returns what the clock is showing.
+func (c *Control) Status() Status {
+	level, until, override := c.brightness()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.status
+	switch {
+	case !c.deadline.IsZero():
+		left := config.Duration(max(time.Until(c.deadline), 0))
+		s.Remaining = &left
+	case c.left > 0:
+		left := config.Duration(c.left)
+		s.Remaining = &left
+	}
+	if override {
+		s.Override, s.OverrideUntil = &level, &until
+	}
+	if c.segs != nil {
+		for _, seg := range c.segs {
+			s.Frame += fmt.Sprintf("%04x", seg)
+		}
+	} else {
+		s.Frame = hex.EncodeToString(c.frame)
+	}
+	return s
+}
+
+// setActive records the active widget. deadline is when its time runs out,
+// zero when paused (left holds the time still to run) or unlimited.
+func (c *Control) setActive(index int, name string, deadline time.Time, left time.Duration, paused bool, pinUntil time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status.Widget, c.status.Index, c.status.Paused = name, index, paused
+	c.status.PinnedTo = nil
+	if !pinUntil.IsZero() {
+		c.status.PinnedTo = &pinUntil
+	}
+	c.deadline, c.left = deadline, left
+}
+
+func (c *Control) setBrightness(level byte) {
+	c.mu.Lock()
+	c.status.Brightness = level
+	c.mu.Unlock()
+}
+
+// resolve finds the widget named by ref, an ID or a rotation index.
+func resolve(ref string, ids []string) (int, error) {
+	for i, id := range ids {
+		if id == ref {
+			return i, nil
+		}
+	}
+	if i, err := strconv.Atoi(ref); err == nil && i >= 0 && i < len(ids) {
+		return i, nil
+	}
+	return 0, fmt.Errorf("unknown widget %q", ref)
+}
+
+// recordFrames wraps disp so the last frame written is kept for Status.
+func recordFrames(disp display.Display, c *Control) display.Display {
+	switch d := disp.(type) {
+	case display.PixelDisplay:
+		return &pixelRecorder{PixelDisplay: d, ctrl: c}
+	case display.SegmentDisplay:
+		return &segmentRecorder{SegmentDisplay: d, ctrl: c}
+	}
+	return disp
+}
+
+type pixelRecorder struct {
+	display.PixelDisplay
+	ctrl *Control
+}
+
+func (p *pixelRecorder) SetBrightness(level byte) {
+	p.ctrl.setBrightness(level)
+	p.PixelDisplay.SetBrightness(level)
+}
+
+func (p *pixelRecorder) WriteFramebuffer(buf []byte) {
+	p.ctrl.mu.Lock()
+	p.ctrl.frame = append(p.ctrl.frame[:0], buf...)
+	p.ctrl.mu.Unlock()
+	p.PixelDisplay.WriteFramebuffer(buf)
+}
+
+type segmentRecorder struct {
+	display.SegmentDisplay
+	ctrl *Control
+}
+
+func (s *segmentRecorder) SetBrightness(level byte) {
+	s.ctrl.setBrightness(level)
+	s.SegmentDisplay.SetBrightness(level)
+}
+
+func (s *segmentRecorder) WriteSegments(segs []uint16, colon bool) {
+	s.ctrl.mu.Lock()
+	s.ctrl.segs = append(s.ctrl.segs[:0:0], segs...)
+	s.ctrl.status.Colon = colon
+	s.ctrl.mu.Unlock()
+	s.SegmentDisplay.WriteSegments(segs, colon)
+}
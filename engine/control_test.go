@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+)
+
+// runControlled starts an engine rotating static messages, which hold the
+// display until their duration ends, and returns its control.
+func runControlled(t *testing.T, widgets ...config.WidgetConfig) *Control {
+	t.Helper()
+	cfg := &config.Config{Brightness: brightnessCfg(), Widgets: widgets}
+	e := New(&testutil.SpyDisplay{}, cfg, nil)
+	e.nowFunc = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx) //nolint:errcheck
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	waitStatus(t, e.control, func(s Status) bool { return s.Widget != "" })
+	return e.control
+}
+
+func waitStatus(t *testing.T, c *Control, ok func(Status) bool) Status {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		s := c.Status()
+		if ok(s) {
+			return s
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("status %+v did not match", s)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestControl_NextPrevPin(t *testing.T) {
+	ctrl := runControlled(t,
+		config.WidgetConfig{Type: "message", Enabled: true, Text: "A", ID: "a"},
+		config.WidgetConfig{Type: "message", Enabled: true, Text: "B"},
+	)
+	ctx := context.Background()
+
+	if err := ctrl.Next(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s := ctrl.Status(); s.Widget != "message" || s.Index != 1 {
+		t.Errorf("after next: %+v, want widget 1", s)
+	}
+	if err := ctrl.Prev(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s := ctrl.Status(); s.Widget != "a" || s.Index != 0 {
+		t.Errorf("after prev: %+v, want widget a", s)
+	}
+
+	if err := ctrl.Pin(ctx, "1", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	s := ctrl.Status()
+	if s.Index != 1 || s.PinnedTo == nil || s.Remaining == nil || s.Remaining.Unwrap() > time.Minute {
+		t.Errorf("after pin: %+v, want widget 1 pinned for a minute", s)
+	}
+	if err := ctrl.Resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s := ctrl.Status(); s.Index != 0 || s.PinnedTo != nil {
+		t.Errorf("after resume: %+v, want widget 0 unpinned", s)
+	}
+
+	if err := ctrl.Pin(ctx, "nope", time.Minute); err == nil {
+		t.Error("pinning an unknown widget should fail")
+	}
+}
+
+func TestControl_PauseResume(t *testing.T) {
+	ctrl := runControlled(t,
+		config.WidgetConfig{Type: "message", Enabled: true, Text: "A", Duration: config.Duration(100 * time.Millisecond)},
+		config.WidgetConfig{Type: "message", Enabled: true, Text: "B", ID: "b"},
+	)
+	ctx := context.Background()
+
+	if err := ctrl.Pause(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s := ctrl.Status()
+	if !s.Paused || s.Remaining == nil {
+		t.Fatalf("after pause: %+v, want paused with time remaining", s)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if s := ctrl.Status(); s.Index != 0 {
+		t.Errorf("paused widget rotated away: %+v", s)
+	}
+
+	if err := ctrl.Resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, ctrl, func(s Status) bool { return s.Widget == "b" && !s.Paused })
+}
+
+func TestControl_ShowAndFrame(t *testing.T) {
+	ctrl := runControlled(t, config.WidgetConfig{Type: "message", Enabled: true, Text: "A"})
+	before := waitStatus(t, ctrl, func(s Status) bool { return s.Frame != "" }).Frame
+
+	if err := ctrl.Show(context.Background(), "Hi", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, ctrl, func(s Status) bool { return s.Frame != before })
+	// The rotation resumes once the message ends.
+	waitStatus(t, ctrl, func(s Status) bool { return s.Frame == before })
+}
+
+func TestControl_BrightnessOverride(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	e := New(spy, &config.Config{Brightness: brightnessCfg()}, nil)
+	e.nowFunc = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) } // day → high
+
+	if err := e.control.SetBrightness(16, time.Minute); err == nil {
+		t.Error("level 16 should be rejected")
+	}
+	e.control.SetBrightness(3, time.Minute) //nolint:errcheck
+	e.updateBrightness()
+	release := e.holdMaxBrightness() // alerts still win
+	release()
+	e.control.SetBrightness(0, 0) //nolint:errcheck
+	e.updateBrightness()
+
+	want := []byte{3, 15, 3, 15}
+	if string(spy.Brightness) != string(want) {
+		t.Errorf("brightness = %v, want %v", spy.Brightness, want)
+	}
+}
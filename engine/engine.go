@@ -4,14 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/nathan-osman/go-sunrise"
 	"github.com/swilcox/led-kurokku-go/config"
 	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/gtfs"
 	"github.com/swilcox/led-kurokku-go/internal/cronutil"
+	"github.com/swilcox/led-kurokku-go/mpd"
 	"github.com/swilcox/led-kurokku-go/redis"
 	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/sysinfo"
 	"github.com/swilcox/led-kurokku-go/widget"
 	"github.com/swilcox/led-kurokku-go/widget/animation"
 	"github.com/swilcox/led-kurokku-go/widget/segment"
@@ -21,9 +26,10 @@ import (
 // Using an interface allows engine tests to inject a mock without a real Redis server.
 type redisStore interface {
 	FetchAlerts(ctx context.Context) ([]config.AlertConfig, error)
+	AlertIDs(ctx context.Context) ([]string, error)
 	DeleteAlert(ctx context.Context, id string) error
 	FetchMessageText(ctx context.Context, key string) (string, bool, error)
-	SubscribeAlerts(ctx context.Context) (<-chan struct{}, error)
+	SubscribeAlerts(ctx context.Context) (<-chan redis.AlertEvent, error)
 }
 
 // Engine manages the widget cycling loop.
@@ -31,7 +37,16 @@ type Engine struct {
 	disp    display.Display
 	cfg     *config.Config
 	rds     redisStore
+	acker   *Acker
+	control *Control
+	policy  Policy
+	errs    *ErrorLog
+	probe   widget.Pinger // pinged by the network widget when rds is nil
+	alerts  *AlertList    // in-process alerts, used when Redis is absent
+	texts   *TextStore    // in-process dynamic text, used when Redis is absent
 	nowFunc func() time.Time
+	// brightHolds counts alerts holding the display at full brightness.
+	brightHolds atomic.Int32
 }
 
 func (e *Engine) now() time.Time {
@@ -43,78 +58,333 @@ func (e *Engine) now() time.Time {
 
 // New creates a new engine with the given display, config, and optional Redis client.
 func New(disp display.Display, cfg *config.Config, rds *redis.Client) *Engine {
-	e := &Engine{disp: disp, cfg: cfg}
+	e := &Engine{disp: disp, cfg: cfg, acker: NewAcker(rds), control: NewControl()}
 	if rds != nil {
 		e.rds = rds
 	}
 	return e
 }
 
+// SetAcker replaces the engine's acker, letting acks recorded through the
+// HTTP API survive engine restarts on config reload.
+func (e *Engine) SetAcker(a *Acker) {
+	e.acker = a
+}
+
+// SetControl replaces the engine's control, letting the HTTP API reach
+// whichever engine is running across config reloads.
+func (e *Engine) SetControl(c *Control) {
+	e.control = c
+}
+
+// SetRedisProbe sets the client the network widget and splash ping when the
+// engine runs without Redis, so a configured but unreachable server is
+// reported as down rather than left out.
+func (e *Engine) SetRedisProbe(p widget.Pinger) {
+	e.probe = p
+}
+
+// SetAlertList sets the in-process alert list shown by the alert widgets
+// when Redis is absent, e.g. alerts received by the Alertmanager webhook.
+func (e *Engine) SetAlertList(l *AlertList) {
+	e.alerts = l
+}
+
+// alertFetcher returns the store the alert widgets read: Redis, else the
+// in-process alert list, else nil for the configured alerts only.
+func (e *Engine) alertFetcher() widget.AlertFetcher {
+	if e.rds != nil {
+		return e.rds
+	}
+	if e.alerts != nil {
+		return e.alerts
+	}
+	return nil
+}
+
+// SetTextStore sets the in-process store of dynamic text read by message,
+// template and value widgets when Redis is absent, e.g. texts pushed through
+// the webhook.
+func (e *Engine) SetTextStore(t *TextStore) {
+	e.texts = t
+}
+
+// textFetcher returns the store dynamic text is read from: Redis, else the
+// in-process text store, else nil.
+func (e *Engine) textFetcher() widget.MessageTextFetcher {
+	if e.rds != nil {
+		return e.rds
+	}
+	if e.texts != nil {
+		return e.texts
+	}
+	return nil
+}
+
 // Run starts the widget cycling loop. It blocks until ctx is cancelled.
 func (e *Engine) Run(ctx context.Context) error {
-	widgets, durations, crons := e.buildWidgets()
+	widgets, durations, crons, ids := e.buildWidgets()
 	if len(widgets) == 0 {
 		return fmt.Errorf("no enabled widgets configured")
 	}
 
+	// Keep the last frame for the control API's status, and show the ack
+	// indicator while alerts wait for an acknowledgement.
+	e.disp = withAckIndicator(recordFrames(e.disp, e.control), e.acker)
+	e.acker.Apply(e.configAlerts())
+
 	// Start brightness control goroutine
 	go e.brightnessLoop(ctx)
 
-	// Subscribe for alert interrupts if Redis is available.
-	var alertCh <-chan struct{}
+	if e.rds != nil {
+		go e.ackLoop(ctx)
+	}
+
+	// Subscribe for alert interrupts if Redis is available. Only alerts
+	// created from now on interrupt.
+	var alertCh <-chan redis.AlertEvent
+	var known map[string]bool
 	if e.rds != nil {
 		var err error
 		alertCh, err = e.rds.SubscribeAlerts(ctx)
 		if err != nil {
-			log.Printf("redis alert subscribe failed, interrupts disabled: %v", err)
+			e.errs.Printf("redis alert subscribe failed, interrupts disabled: %v", err)
+		}
+		known = e.knownAlerts(ctx)
+	}
+
+	// Widgets that can interrupt the rotation (e.g. MPD on track change).
+	widgetCh := make(chan int, 1)
+	for i, w := range widgets {
+		if ir, ok := w.(widget.Interrupter); ok {
+			go forwardInterrupts(ctx, ir.Interrupts(ctx), i, widgetCh)
 		}
 	}
 
+	ctrl := e.control
+	i, dir := len(widgets)-1, 1 // the first step lands on widget 0
+	var pinUntil time.Time      // zero unless a widget is pinned
+	var ack chan error          // answers a command once its widget shows
 	for {
-		for i, w := range widgets {
-			if ctx.Err() != nil {
-				return nil
+		if ctx.Err() != nil {
+			return nil
+		}
+		i = (i + dir + len(widgets)) % len(widgets)
+		// A pinned widget shows regardless of its cron schedule.
+		if pinUntil.IsZero() && crons[i] != "" && !cronutil.MatchesNow(crons[i], e.now()) {
+			continue
+		}
+		dir = 1
+		w := widgets[i]
+		log.Printf("widget: %s", w.Name())
+		// An interrupted or paused widget resumes for the rest of its duration.
+		remaining := durations[i]
+		if !pinUntil.IsZero() {
+			remaining = time.Until(pinUntil)
+		}
+		limited := remaining > 0
+		paused := false
+		jump := -1
+		for {
+			started := time.Now()
+			var timer *time.Timer
+			var timeout <-chan time.Time
+			arm := func() {
+				started = time.Now()
+				var deadline time.Time
+				if limited && !paused {
+					timer = time.NewTimer(remaining)
+					timeout = timer.C
+					deadline = started.Add(remaining)
+				}
+				ctrl.setActive(i, ids[i], deadline, remaining, paused, pinUntil)
+				if ack != nil {
+					ack <- nil
+					ack = nil
+				}
 			}
-			if crons[i] != "" && !cronutil.MatchesNow(crons[i], e.now()) {
-				continue
+			disarm := func() {
+				if timer != nil {
+					timer.Stop()
+				}
+				timeout = nil
 			}
-			log.Printf("widget: %s", w.Name())
-			var wctx context.Context
-			var cancel context.CancelFunc
-			if durations[i] > 0 {
-				wctx, cancel = context.WithTimeout(ctx, durations[i])
-			} else {
-				wctx, cancel = context.WithCancel(ctx)
+			// elapse charges the time shown so far against remaining.
+			elapse := func() {
+				if limited && !paused {
+					remaining -= time.Since(started)
+				}
 			}
+			arm()
 
-			// Run widget in a goroutine so we can select on interrupt.
+			// Run widget in a goroutine so we can select on interrupts and
+			// control commands.
+			wctx, cancel := context.WithCancel(ctx)
 			done := make(chan struct{})
 			go func() {
 				w.Run(wctx, e.disp)
 				close(done)
 			}()
 
-			select {
-			case <-done:
-				cancel()
-			case <-alertCh:
-				// Alert interrupt: cancel current widget and show alerts.
-				cancel()
-				<-done // wait for widget goroutine to finish
-				e.runInterruptAlerts(ctx)
-			case <-ctx.Done():
-				cancel()
-				<-done
-				return nil
+			var interrupt func()
+			ended := false
+		wait:
+			for {
+				select {
+				case <-done:
+					ended = true
+					break wait
+				case <-timeout:
+					pinUntil = time.Time{}
+					break wait
+				case ev := <-alertCh:
+					if !e.shouldInterrupt(ctx, known, ev) {
+						continue
+					}
+					// Alert interrupt: cancel current widget and show alerts.
+					interrupt = func() { e.runInterruptAlerts(ctx) }
+					break wait
+				case j := <-widgetCh:
+					// A widget already on screen handles its own updates, and
+					// cron-restricted widgets may only interrupt when scheduled.
+					if j == i || (crons[j] != "" && !cronutil.MatchesNow(crons[j], e.now())) {
+						continue
+					}
+					interrupt = func() {
+						log.Printf("widget interrupt: %s", widgets[j].Name())
+						e.runWidget(ctx, widgets[j], durations[j])
+					}
+					break wait
+				case cmd := <-ctrl.cmds:
+					var err error
+					switch cmd.kind {
+					case cmdNext, cmdPrev:
+						if cmd.kind == cmdPrev {
+							dir = -1
+						}
+						pinUntil = time.Time{}
+						ack = cmd.reply
+						break wait
+					case cmdPause:
+						// A pause holds the widget until resumed, ending any pin.
+						if !paused {
+							disarm()
+							elapse()
+							paused, pinUntil = true, time.Time{}
+							ctrl.setActive(i, ids[i], time.Time{}, remaining, paused, pinUntil)
+						}
+					case cmdResume:
+						if !pinUntil.IsZero() {
+							pinUntil = time.Time{}
+							ack = cmd.reply
+							break wait
+						}
+						if paused {
+							paused = false
+							arm()
+						}
+					case cmdPin:
+						var j int
+						if j, err = resolve(cmd.widget, ids); err != nil {
+							break
+						}
+						pinUntil = time.Now().Add(cmd.d)
+						if j != i {
+							jump = j
+							ack = cmd.reply
+							break wait
+						}
+						disarm()
+						remaining, limited, paused = cmd.d, true, false
+						arm()
+					case cmdShow:
+						text, d := cmd.text, cmd.d
+						interrupt = func() {
+							log.Printf("control message: %s", text)
+							e.showMessage(ctx, text, d)
+						}
+						cmd.reply <- nil
+						break wait
+					}
+					cmd.reply <- err
+				case <-ctx.Done():
+					break wait
+				}
 			}
-
+			disarm()
+			cancel()
+			<-done // wait for widget goroutine to finish
 			if ctx.Err() != nil {
 				return nil
 			}
+
+			advance := false
+			switch {
+			case interrupt != nil:
+				elapse()
+				interrupt()
+				if ctx.Err() != nil {
+					return nil
+				}
+			case ended && (paused || !pinUntil.IsZero()):
+				// A paused or pinned widget that ends early runs again; the
+				// sleep keeps one that ends at once from spinning.
+				elapse()
+				if time.Since(started) < time.Second {
+					widget.SleepOrCancel(ctx, time.Second)
+				}
+			default:
+				// Done, timed out, skipped or pinned to another widget.
+				advance = true
+			}
+			if !pinUntil.IsZero() {
+				if remaining = time.Until(pinUntil); remaining <= 0 {
+					pinUntil = time.Time{}
+					advance = true
+				}
+			} else if limited && !paused && remaining <= 0 {
+				advance = true
+			}
+			if advance {
+				if jump >= 0 {
+					i, dir = jump, 0
+				}
+				break
+			}
+			log.Printf("widget resume: %s", w.Name())
+		}
+	}
+}
+
+// forwardInterrupts relays signals from a widget's interrupt channel as its
+// index, dropping signals while one is already pending.
+func forwardInterrupts(ctx context.Context, ch <-chan struct{}, i int, out chan<- int) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+			select {
+			case out <- i:
+			default:
+			}
 		}
 	}
 }
 
+// runWidget runs w to completion or until d elapses (no limit when d is 0).
+func (e *Engine) runWidget(ctx context.Context, w widget.Widget, d time.Duration) {
+	if d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	w.Run(ctx, e.disp)
+}
+
 // runInterruptAlerts fetches alerts from Redis and displays them.
 func (e *Engine) runInterruptAlerts(ctx context.Context) {
 	if e.rds == nil {
@@ -123,36 +393,45 @@ func (e *Engine) runInterruptAlerts(ctx context.Context) {
 
 	alerts, err := e.rds.FetchAlerts(ctx)
 	if err != nil {
-		log.Printf("redis interrupt fetch failed: %v", err)
+		e.errs.Printf("redis interrupt fetch failed: %v", err)
 		return
 	}
 	if len(alerts) == 0 {
 		return
 	}
 
-	timeout := time.Duration(len(alerts)) * 10 * time.Second
-	alertCtx, cancel := context.WithTimeout(ctx, timeout)
+	alertCtx, cancel := context.WithTimeout(ctx, interruptTimeout(alerts))
 	defer cancel()
 
 	if e.cfg.Display.IsSegment() {
 		a := &segment.Alert{
-			Alerts:      alerts,
-			ScrollSpeed: 300 * time.Millisecond,
-			Encoder:     e.segmentEncoder(),
+			Alerts:         alerts,
+			ScrollSpeed:    300 * time.Millisecond,
+			Encoder:        e.segmentEncoder(),
+			Acks:           e.acker,
+			Schedules:      e.cfg.AlertSchedules,
+			Profiles:       e.cfg.SeverityProfiles,
+			HoldBrightness: e.holdMaxBrightness,
+			OnDisplay:      e.onAlertDisplay(),
 			OnDelete: func(ctx context.Context, id string) {
 				if err := e.rds.DeleteAlert(ctx, id); err != nil {
-					log.Printf("redis alert delete %s: %v", id, err)
+					e.errs.Printf("redis alert delete %s: %v", id, err)
 				}
 			},
 		}
 		a.Run(alertCtx, e.disp)
 	} else {
 		a := &widget.Alert{
-			Alerts:      alerts,
-			ScrollSpeed: 50 * time.Millisecond,
+			Alerts:         alerts,
+			ScrollSpeed:    50 * time.Millisecond,
+			Acks:           e.acker,
+			Schedules:      e.cfg.AlertSchedules,
+			Profiles:       e.cfg.SeverityProfiles,
+			HoldBrightness: e.holdMaxBrightness,
+			OnDisplay:      e.onAlertDisplay(),
 			OnDelete: func(ctx context.Context, id string) {
 				if err := e.rds.DeleteAlert(ctx, id); err != nil {
-					log.Printf("redis alert delete %s: %v", id, err)
+					e.errs.Printf("redis alert delete %s: %v", id, err)
 				}
 			},
 		}
@@ -160,6 +439,175 @@ func (e *Engine) runInterruptAlerts(ctx context.Context) {
 	}
 }
 
+// showMessage shows text for d, scrolling it again until d elapses.
+func (e *Engine) showMessage(ctx context.Context, text string, d time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	var w widget.Widget = &widget.Message{Text: text}
+	if e.cfg.Display.IsSegment() {
+		w = &segment.Message{Text: text, Encoder: e.segmentEncoder()}
+	}
+	for ctx.Err() == nil {
+		w.Run(ctx, e.disp)
+	}
+}
+
+// RunSplash shows the network status for cfg.NetworkSplash, if set. It is
+// meant to be called once at startup so a headless clock reveals its address.
+func (e *Engine) RunSplash(ctx context.Context) {
+	d := e.cfg.NetworkSplash.Unwrap()
+	if d <= 0 {
+		return
+	}
+	splashCtx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	e.networkWidget(config.WidgetConfig{}).Run(splashCtx, e.disp)
+}
+
+func (e *Engine) networkWidget(wc config.WidgetConfig) widget.Widget {
+	probe := widget.NetworkProbe{
+		Reader:     sysinfo.Reader{ProcRoot: wc.ProcRoot, SysRoot: wc.SysRoot},
+		Interfaces: wc.Interfaces,
+		IPv6:       wc.IPv6,
+	}
+	// Only the real Redis client can be pinged; test doubles are skipped.
+	if p, ok := e.rds.(widget.Pinger); ok {
+		probe.Pinger = p
+	} else if e.probe != nil {
+		probe.Pinger = e.probe
+	}
+	if e.cfg.Display.IsSegment() {
+		return &segment.Network{
+			Probe:       probe,
+			Interval:    wc.Interval.Unwrap(),
+			ScrollSpeed: wc.ScrollSpeed.Unwrap(),
+			Encoder:     e.segmentEncoder(),
+		}
+	}
+	return &widget.Network{
+		Probe:       probe,
+		ScrollSpeed: wc.ScrollSpeed.Unwrap(),
+	}
+}
+
+// headlineSets hands out per-widget seen-headline sets.
+type headlineSets interface {
+	Headlines(widget string) *redis.Headlines
+}
+
+// feedWidget builds a feed widget; index is its position in the config,
+// which keys its seen headlines when it has no ID.
+func (e *Engine) feedWidget(wc config.WidgetConfig, index int) widget.Widget {
+	feeds := wc.Feeds
+	if wc.URL != "" {
+		feeds = append(feeds, config.FeedConfig{URL: wc.URL, Interval: wc.Interval})
+	}
+	sources := make([]*widget.FeedSource, len(feeds))
+	for i, fc := range feeds {
+		sources[i] = &widget.FeedSource{URL: fc.URL, Refresh: fc.Interval.Unwrap(), NowFunc: e.nowFunc}
+	}
+	// Seen headlines persist in Redis when available; otherwise in memory.
+	var seen widget.HeadlineStore
+	if hs, ok := e.rds.(headlineSets); ok {
+		id := wc.ID
+		if id == "" {
+			id = fmt.Sprintf("feed%d", index)
+		}
+		seen = hs.Headlines(id)
+	}
+	if e.cfg.Display.IsSegment() {
+		return &segment.Feed{
+			Sources:      sources,
+			Count:        wc.Count,
+			Seen:         seen,
+			FallbackText: wc.Text,
+			ScrollSpeed:  wc.ScrollSpeed.Unwrap(),
+			Encoder:      e.segmentEncoder(),
+		}
+	}
+	return &widget.Feed{
+		Sources:      sources,
+		Count:        wc.Count,
+		Seen:         seen,
+		FallbackText: wc.Text,
+		ScrollSpeed:  wc.ScrollSpeed.Unwrap(),
+	}
+}
+
+func (e *Engine) transitWidget(wc config.WidgetConfig) widget.Widget {
+	sched := &widget.TransitSchedule{
+		Path:   wc.Path,
+		Filter: gtfs.Filter{Stops: wc.StopIDs, Routes: wc.Routes},
+	}
+	// The feed's agency timezone applies unless one is configured explicitly.
+	if wc.Timezone != "" {
+		sched.Location = e.timezone(wc.Timezone)
+	}
+	var rt *widget.RealtimeSource
+	if wc.Realtime != "" {
+		rt = &widget.RealtimeSource{Source: wc.Realtime, Refresh: wc.Interval.Unwrap(), NowFunc: e.nowFunc}
+	}
+	if e.cfg.Display.IsSegment() {
+		return &segment.Transit{
+			Schedule:     sched,
+			Realtime:     rt,
+			Count:        wc.Count,
+			LookAhead:    wc.LookAhead.Unwrap(),
+			FallbackText: wc.Text,
+			ScrollSpeed:  wc.ScrollSpeed.Unwrap(),
+			NowFunc:      e.nowFunc,
+			Encoder:      e.segmentEncoder(),
+		}
+	}
+	return &widget.Transit{
+		Schedule:     sched,
+		Realtime:     rt,
+		Count:        wc.Count,
+		LookAhead:    wc.LookAhead.Unwrap(),
+		FallbackText: wc.Text,
+		ScrollSpeed:  wc.ScrollSpeed.Unwrap(),
+		NowFunc:      e.nowFunc,
+	}
+}
+
+// valueSource picks a numeric source from a widget config: a Redis key
+// (dynamic_source, read from the in-process text store without Redis), an HTTP JSON document (url and
+// json_path), or a file (path). It logs why and returns nil when none is
+// configured or the file is not allowed by the policy.
+func (e *Engine) valueSource(wc config.WidgetConfig) widget.ValueSource {
+	switch {
+	case wc.DynamicSource != "" && e.textFetcher() != nil:
+		return &widget.RedisValue{Fetcher: e.textFetcher(), Key: wc.DynamicSource}
+	case wc.URL != "":
+		return &widget.JSONValue{URL: wc.URL, Path: wc.JSONPath}
+	case wc.Path != "":
+		if !e.policy.allowFile(wc.Path) {
+			log.Printf("gauge %s: path not allowed for a config from Redis; see -file-root", wc.Path)
+			return nil
+		}
+		return &widget.FileValue{Path: wc.Path}
+	}
+	log.Printf("gauge: one of dynamic_source, url or path is required")
+	return nil
+}
+
+// timezone resolves name, falling back to the configured location's
+// timezone and then to the local zone.
+func (e *Engine) timezone(name string) *time.Location {
+	if name == "" && e.cfg.Location != nil {
+		name = e.cfg.Location.Timezone
+	}
+	if name == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("invalid timezone %q: %v, using local time", name, err)
+		return time.Local
+	}
+	return loc
+}
+
 func (e *Engine) segmentEncoder() segfont.Encoder {
 	switch e.cfg.Display.Type {
 	case config.DisplayTM1637, config.DisplayTerminalSeg7:
@@ -169,13 +617,15 @@ func (e *Engine) segmentEncoder() segfont.Encoder {
 	}
 }
 
-func (e *Engine) buildWidgets() ([]widget.Widget, []time.Duration, []string) {
+// buildWidgets returns the enabled widgets with their durations, cron
+// expressions and IDs (the configured id, else the type).
+func (e *Engine) buildWidgets() ([]widget.Widget, []time.Duration, []string, []string) {
 	var widgets []widget.Widget
 	var durations []time.Duration
-	var crons []string
+	var crons, ids []string
 	isSeg := e.cfg.Display.IsSegment()
 
-	for _, wc := range e.cfg.Widgets {
+	for i, wc := range e.cfg.Widgets {
 		if !wc.Enabled {
 			continue
 		}
@@ -199,9 +649,9 @@ func (e *Engine) buildWidgets() ([]widget.Widget, []time.Duration, []string) {
 				repeats = *wc.Repeats
 			}
 			if isSeg {
-				if e.rds != nil && wc.DynamicSource != "" {
+				if fetcher := e.textFetcher(); fetcher != nil && wc.DynamicSource != "" {
 					w = &segment.RedisMessage{
-						Fetcher:      e.rds,
+						Fetcher:      fetcher,
 						Key:          wc.DynamicSource,
 						FallbackText: wc.Text,
 						ScrollSpeed:  wc.ScrollSpeed.Unwrap(),
@@ -219,9 +669,9 @@ func (e *Engine) buildWidgets() ([]widget.Widget, []time.Duration, []string) {
 					}
 				}
 			} else {
-				if e.rds != nil && wc.DynamicSource != "" {
+				if fetcher := e.textFetcher(); fetcher != nil && wc.DynamicSource != "" {
 					w = &widget.RedisMessage{
-						Fetcher:      e.rds,
+						Fetcher:      fetcher,
 						Key:          wc.DynamicSource,
 						FallbackText: wc.Text,
 						ScrollSpeed:  wc.ScrollSpeed.Unwrap(),
@@ -240,36 +690,58 @@ func (e *Engine) buildWidgets() ([]widget.Widget, []time.Duration, []string) {
 
 		case "alert":
 			if isSeg {
-				if e.rds != nil {
+				if fetcher := e.alertFetcher(); fetcher != nil {
 					w = &segment.RedisAlert{
-						Fetcher:     e.rds,
-						Fallback:    wc.Alerts,
-						ScrollSpeed: wc.ScrollSpeed.Unwrap(),
-						Encoder:     e.segmentEncoder(),
+						Fetcher:        fetcher,
+						Fallback:       wc.Alerts,
+						ScrollSpeed:    wc.ScrollSpeed.Unwrap(),
+						Encoder:        e.segmentEncoder(),
+						Acks:           e.acker,
+						Schedules:      e.cfg.AlertSchedules,
+						Profiles:       e.cfg.SeverityProfiles,
+						HoldBrightness: e.holdMaxBrightness,
+						OnDisplay:      e.onAlertDisplay(),
 					}
 				} else {
 					w = &segment.Alert{
-						Alerts:      wc.Alerts,
-						ScrollSpeed: wc.ScrollSpeed.Unwrap(),
-						Encoder:     e.segmentEncoder(),
+						Alerts:         wc.Alerts,
+						ScrollSpeed:    wc.ScrollSpeed.Unwrap(),
+						Encoder:        e.segmentEncoder(),
+						Acks:           e.acker,
+						Schedules:      e.cfg.AlertSchedules,
+						Profiles:       e.cfg.SeverityProfiles,
+						HoldBrightness: e.holdMaxBrightness,
 					}
 				}
 			} else {
-				if e.rds != nil {
+				if fetcher := e.alertFetcher(); fetcher != nil {
 					w = &widget.RedisAlert{
-						Fetcher:     e.rds,
-						Fallback:    wc.Alerts,
-						ScrollSpeed: wc.ScrollSpeed.Unwrap(),
+						Fetcher:        fetcher,
+						Fallback:       wc.Alerts,
+						ScrollSpeed:    wc.ScrollSpeed.Unwrap(),
+						Acks:           e.acker,
+						Schedules:      e.cfg.AlertSchedules,
+						Profiles:       e.cfg.SeverityProfiles,
+						HoldBrightness: e.holdMaxBrightness,
+						OnDisplay:      e.onAlertDisplay(),
 					}
 				} else {
 					w = &widget.Alert{
-						Alerts:      wc.Alerts,
-						ScrollSpeed: wc.ScrollSpeed.Unwrap(),
+						Alerts:         wc.Alerts,
+						ScrollSpeed:    wc.ScrollSpeed.Unwrap(),
+						Acks:           e.acker,
+						Schedules:      e.cfg.AlertSchedules,
+						Profiles:       e.cfg.SeverityProfiles,
+						HoldBrightness: e.holdMaxBrightness,
 					}
 				}
 			}
 
 		case "animation":
+			params, err := widget.ParseAnimParams(wc.Params)
+			if err != nil {
+				log.Printf("animation %s: %v, using defaults", wc.AnimationType, err)
+			}
 			if isSeg {
 				if wc.AnimationType == "frames" || wc.AnimationType == "" {
 					w = &segment.FrameAnimation{
@@ -277,7 +749,7 @@ func (e *Engine) buildWidgets() ([]widget.Widget, []time.Duration, []string) {
 						FrameDuration: wc.FrameDuration.Unwrap(),
 					}
 				} else if factory, ok := segment.Registry[wc.AnimationType]; ok {
-					w = factory()
+					w = factory(params, e.segmentEncoder())
 				} else {
 					log.Printf("unknown segment animation type: %s", wc.AnimationType)
 					continue
@@ -289,13 +761,304 @@ func (e *Engine) buildWidgets() ([]widget.Widget, []time.Duration, []string) {
 						FrameDuration: wc.FrameDuration.Unwrap(),
 					}
 				} else if factory, ok := animation.Registry[wc.AnimationType]; ok {
-					w = factory()
+					w = factory(params)
 				} else {
 					log.Printf("unknown animation type: %s", wc.AnimationType)
 					continue
 				}
 			}
 
+		case "prometheus":
+			query := widget.PromQuery{
+				URL:        wc.URL,
+				Query:      wc.Query,
+				Label:      wc.Label,
+				LabelValue: wc.LabelValue,
+			}
+			var threshold *widget.Threshold
+			if wc.Threshold != nil {
+				threshold = &widget.Threshold{Value: *wc.Threshold, Below: wc.ThresholdBelow}
+			}
+			if isSeg {
+				w = &segment.Prometheus{
+					Query:             query,
+					Prefix:            wc.Prefix,
+					Unit:              wc.Unit,
+					Format:            wc.Format,
+					Scale:             wc.Scale,
+					Threshold:         threshold,
+					AlertStyle:        wc.AlertStyle,
+					FallbackText:      wc.Text,
+					ScrollSpeed:       wc.ScrollSpeed.Unwrap(),
+					RestoreBrightness: e.updateBrightness,
+					Encoder:           e.segmentEncoder(),
+				}
+			} else {
+				w = &widget.Prometheus{
+					Query:             query,
+					Prefix:            wc.Prefix,
+					Unit:              wc.Unit,
+					Format:            wc.Format,
+					Scale:             wc.Scale,
+					Threshold:         threshold,
+					AlertStyle:        wc.AlertStyle,
+					FallbackText:      wc.Text,
+					ScrollSpeed:       wc.ScrollSpeed.Unwrap(),
+					RestoreBrightness: e.updateBrightness,
+				}
+			}
+
+		case "gauge":
+			src := e.valueSource(wc)
+			if src == nil {
+				continue
+			}
+			max := 100.0
+			if wc.Max != nil {
+				max = *wc.Max
+			}
+			if max <= wc.Min {
+				log.Printf("gauge: max (%g) must be greater than min (%g)", max, wc.Min)
+				continue
+			}
+			var threshold *widget.Threshold
+			if wc.Threshold != nil {
+				threshold = &widget.Threshold{Value: *wc.Threshold, Below: wc.ThresholdBelow}
+			}
+			if isSeg {
+				w = &segment.Gauge{
+					Source:            src,
+					Min:               wc.Min,
+					Max:               wc.Max,
+					Style:             wc.Style,
+					Prefix:            wc.Prefix,
+					Format:            wc.Format,
+					Threshold:         threshold,
+					AlertStyle:        wc.AlertStyle,
+					Interval:          wc.Interval.Unwrap(),
+					RestoreBrightness: e.updateBrightness,
+					Encoder:           e.segmentEncoder(),
+				}
+			} else {
+				w = &widget.Gauge{
+					Source:            src,
+					Min:               wc.Min,
+					Max:               wc.Max,
+					Style:             wc.Style,
+					Prefix:            wc.Prefix,
+					Format:            wc.Format,
+					Threshold:         threshold,
+					AlertStyle:        wc.AlertStyle,
+					Interval:          wc.Interval.Unwrap(),
+					RestoreBrightness: e.updateBrightness,
+				}
+			}
+
+		case "chart":
+			fetcher, ok := e.rds.(widget.SeriesFetcher)
+			if !ok || wc.DynamicSource == "" {
+				log.Printf("chart: redis and dynamic_source are required")
+				continue
+			}
+			if isSeg {
+				w = &segment.Chart{
+					Fetcher:     fetcher,
+					Key:         wc.DynamicSource,
+					Samples:     wc.Samples,
+					Prefix:      wc.Prefix,
+					Unit:        wc.Unit,
+					Format:      wc.Format,
+					Interval:    wc.Interval.Unwrap(),
+					ScrollSpeed: wc.ScrollSpeed.Unwrap(),
+					Encoder:     e.segmentEncoder(),
+				}
+			} else {
+				c := &widget.Chart{
+					Fetcher:   fetcher,
+					Key:       wc.DynamicSource,
+					Samples:   wc.Samples,
+					Style:     wc.Style,
+					ShowValue: wc.ShowValue,
+					Prefix:    wc.Prefix,
+					Unit:      wc.Unit,
+					Format:    wc.Format,
+					Interval:  wc.Interval.Unwrap(),
+				}
+				// An unset min or max auto-scales that end to the samples.
+				if wc.Min != 0 {
+					lo := wc.Min
+					c.Min = &lo
+				}
+				c.Max = wc.Max
+				w = c
+			}
+
+		case "sysinfo":
+			reader := sysinfo.Reader{ProcRoot: wc.ProcRoot, SysRoot: wc.SysRoot}
+			if isSeg {
+				w = &segment.SysInfo{
+					Reader:      reader,
+					Metrics:     wc.Metrics,
+					DiskPath:    wc.DiskPath,
+					Interval:    wc.Interval.Unwrap(),
+					ScrollSpeed: wc.ScrollSpeed.Unwrap(),
+					Encoder:     e.segmentEncoder(),
+				}
+			} else {
+				w = &widget.SysInfo{
+					Reader:      reader,
+					Metrics:     wc.Metrics,
+					DiskPath:    wc.DiskPath,
+					Interval:    wc.Interval.Unwrap(),
+					ScrollSpeed: wc.ScrollSpeed.Unwrap(),
+				}
+			}
+
+		case "template":
+			repeats := 1
+			if wc.Repeats != nil {
+				repeats = *wc.Repeats
+			}
+			fetcher := e.textFetcher()
+			if isSeg {
+				w = &segment.Template{
+					Text:         wc.Text,
+					Fetcher:      fetcher,
+					Location:     e.cfg.Location,
+					EnvAllowed:   e.policy.allowEnv,
+					ScrollSpeed:  wc.ScrollSpeed.Unwrap(),
+					Repeats:      repeats,
+					SleepBetween: wc.SleepBetween.Unwrap(),
+					NowFunc:      e.nowFunc,
+					Encoder:      e.segmentEncoder(),
+				}
+			} else {
+				w = &widget.Template{
+					Text:         wc.Text,
+					Fetcher:      fetcher,
+					Location:     e.cfg.Location,
+					EnvAllowed:   e.policy.allowEnv,
+					ScrollSpeed:  wc.ScrollSpeed.Unwrap(),
+					Repeats:      repeats,
+					SleepBetween: wc.SleepBetween.Unwrap(),
+					NowFunc:      e.nowFunc,
+				}
+			}
+
+		case "exec":
+			if !e.policy.allowExec(wc.Command, wc.Args) {
+				log.Printf("exec widget %s: command line not in the local allow-list (-exec-allow), skipping", wc.Command)
+				continue
+			}
+			runner := &widget.CommandRunner{
+				Command:     wc.Command,
+				Args:        wc.Args,
+				Timeout:     wc.Timeout.Unwrap(),
+				Interval:    wc.Interval.Unwrap(),
+				WholeOutput: wc.Output == "all",
+				EnvAllow:    e.policy.execEnv(wc.EnvAllow),
+			}
+			if isSeg {
+				w = &segment.Exec{
+					Runner:            runner,
+					FallbackText:      wc.Text,
+					AlertStyle:        wc.AlertStyle,
+					ScrollSpeed:       wc.ScrollSpeed.Unwrap(),
+					RestoreBrightness: e.updateBrightness,
+					Encoder:           e.segmentEncoder(),
+				}
+			} else {
+				w = &widget.Exec{
+					Runner:            runner,
+					FallbackText:      wc.Text,
+					AlertStyle:        wc.AlertStyle,
+					ScrollSpeed:       wc.ScrollSpeed.Unwrap(),
+					RestoreBrightness: e.updateBrightness,
+				}
+			}
+
+		case "file":
+			if !e.policy.allowFile(wc.Path) {
+				log.Printf("file widget %s: path not allowed for a config from Redis; see -file-root", wc.Path)
+				continue
+			}
+			watcher, err := widget.NewLineWatcher(wc.Path, wc.Pattern, wc.Transform)
+			if err != nil {
+				log.Printf("file widget %s: %v", wc.Path, err)
+				continue
+			}
+			if isSeg {
+				w = &segment.FileTail{
+					Watcher:      watcher,
+					FallbackText: wc.Text,
+					Interval:     wc.Interval.Unwrap(),
+					ScrollSpeed:  wc.ScrollSpeed.Unwrap(),
+					Encoder:      e.segmentEncoder(),
+				}
+			} else {
+				w = &widget.FileTail{
+					Watcher:      watcher,
+					FallbackText: wc.Text,
+					Interval:     wc.Interval.Unwrap(),
+					ScrollSpeed:  wc.ScrollSpeed.Unwrap(),
+				}
+			}
+
+		case "calendar":
+			source := wc.Path
+			if wc.URL != "" {
+				source = wc.URL
+			}
+			if !e.policy.allowSource(source) {
+				log.Printf("calendar %s: path not allowed for a config from Redis; see -file-root", source)
+				continue
+			}
+			cal := &widget.CalendarSource{
+				Source:   source,
+				Location: e.timezone(wc.Timezone),
+				Refresh:  wc.Interval.Unwrap(),
+				NowFunc:  e.nowFunc,
+			}
+			if isSeg {
+				w = &segment.Calendar{
+					Source:      cal,
+					LookAhead:   wc.LookAhead.Unwrap(),
+					ShowCurrent: wc.ShowCurrent,
+					ScrollSpeed: wc.ScrollSpeed.Unwrap(),
+					NowFunc:     e.nowFunc,
+					Encoder:     e.segmentEncoder(),
+				}
+			} else {
+				w = &widget.Calendar{
+					Source:      cal,
+					LookAhead:   wc.LookAhead.Unwrap(),
+					ShowCurrent: wc.ShowCurrent,
+					ScrollSpeed: wc.ScrollSpeed.Unwrap(),
+					NowFunc:     e.nowFunc,
+				}
+			}
+
+		case "feed":
+			w = e.feedWidget(wc, i)
+
+		case "transit":
+			if !e.policy.allowFile(wc.Path) || (wc.Realtime != "" && !e.policy.allowSource(wc.Realtime)) {
+				log.Printf("transit %s: path not allowed for a config from Redis; see -file-root", wc.Path)
+				continue
+			}
+			w = e.transitWidget(wc)
+
+		case "mpd":
+			client := &mpd.Client{Addr: wc.Addr, Password: wc.Password, Timeout: wc.Timeout.Unwrap()}
+			if isSeg {
+				w = &segment.NowPlaying{Client: client, ScrollSpeed: wc.ScrollSpeed.Unwrap(), Encoder: e.segmentEncoder()}
+			} else {
+				w = &widget.NowPlaying{Client: client, ScrollSpeed: wc.ScrollSpeed.Unwrap()}
+			}
+
+		case "network":
+			w = e.networkWidget(wc)
+
 		default:
 			log.Printf("unknown widget type: %s", wc.Type)
 			continue
@@ -304,12 +1067,26 @@ func (e *Engine) buildWidgets() ([]widget.Widget, []time.Duration, []string) {
 		widgets = append(widgets, w)
 		durations = append(durations, wc.Duration.Unwrap())
 		crons = append(crons, wc.Cron)
+		id := wc.ID
+		if id == "" {
+			id = wc.Type
+		}
+		ids = append(ids, id)
 	}
-	return widgets, durations, crons
+	return widgets, durations, crons, ids
 }
 
 func (e *Engine) brightnessLoop(ctx context.Context) {
-	e.updateBrightness()
+	// expiry fires when a brightness override set through the control ends.
+	var expiry <-chan time.Time
+	update := func() {
+		e.updateBrightness()
+		expiry = nil
+		if _, until, ok := e.control.brightness(); ok {
+			expiry = time.After(time.Until(until))
+		}
+	}
+	update()
 	ticker := time.NewTicker(60 * time.Second)
 	defer ticker.Stop()
 
@@ -318,12 +1095,39 @@ func (e *Engine) brightnessLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			e.updateBrightness()
+			update()
+		case <-e.control.bright:
+			update()
+		case <-expiry:
+			update()
 		}
 	}
 }
 
+// holdMaxBrightness sets full brightness and keeps brightnessLoop from
+// lowering it until every returned release func has been called.
+func (e *Engine) holdMaxBrightness() (release func()) {
+	e.brightHolds.Add(1)
+	e.disp.SetBrightness(15)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if e.brightHolds.Add(-1) == 0 {
+				e.updateBrightness()
+			}
+		})
+	}
+}
+
 func (e *Engine) updateBrightness() {
+	if e.brightHolds.Load() > 0 {
+		e.disp.SetBrightness(15)
+		return
+	}
+	if level, _, ok := e.control.brightness(); ok {
+		e.disp.SetBrightness(level)
+		return
+	}
 	bc := e.cfg.Brightness
 	now := e.now()
 	if bc.UseLocation {
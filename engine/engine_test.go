@@ -2,21 +2,94 @@ package engine
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/swilcox/led-kurokku-go/config"
 	"github.com/swilcox/led-kurokku-go/display/testutil"
+	mpdtest "github.com/swilcox/led-kurokku-go/mpd/testutil"
+	"github.com/swilcox/led-kurokku-go/redis"
+	"github.com/swilcox/led-kurokku-go/widget"
 )
 
 // mockRedis implements redisStore for testing without a real Redis server.
 type mockRedis struct {
+	mu     sync.Mutex
 	alerts []config.AlertConfig
 	err    error
+	events chan redis.AlertEvent // nil: no events
+	logged []string              // alert log as "event id"
+	expiry map[string]bool       // alerts with an expiry copy, as kept by SetAlert
 }
 
+func (m *mockRedis) LogAlert(_ context.Context, event string, a config.AlertConfig, _ string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logged = append(m.logged, event+" "+a.ID)
+}
+
+// SetAlert mirrors redis.Client: new alerts are logged, and those with an
+// expires_at get an expiry copy.
+func (m *mockRedis) SetAlert(_ context.Context, a config.AlertConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.expiry == nil {
+		m.expiry = map[string]bool{}
+	}
+	m.expiry[a.ID] = a.ExpiresAt != nil
+	for i := range m.alerts {
+		if m.alerts[i].ID == a.ID {
+			m.alerts[i] = a
+			return nil
+		}
+	}
+	m.alerts = append(m.alerts, a)
+	m.logged = append(m.logged, redis.AlertCreated+" "+a.ID)
+	return nil
+}
+
+// LogAlertExpired mirrors the GETDEL guard of redis.Client: only the first
+// caller takes the expiry copy and logs.
+func (m *mockRedis) LogAlertExpired(_ context.Context, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.expiry[id] {
+		return
+	}
+	delete(m.expiry, id)
+	m.logged = append(m.logged, redis.AlertExpired+" "+id)
+}
+
+// FetchAlerts mirrors redis.Client: alerts outside their time window are
+// left out.
 func (m *mockRedis) FetchAlerts(_ context.Context) ([]config.AlertConfig, error) {
-	return m.alerts, m.err
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var alerts []config.AlertConfig
+	for _, a := range m.alerts {
+		if a.Live(time.Now()) {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts, m.err
+}
+
+// AlertIDs returns the IDs of all alerts, live or not, like the key scan.
+func (m *mockRedis) AlertIDs(_ context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []string
+	for _, a := range m.alerts {
+		ids = append(ids, a.ID)
+	}
+	return ids, m.err
+}
+
+func (m *mockRedis) setAlerts(alerts ...config.AlertConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alerts = alerts
 }
 
 func (m *mockRedis) DeleteAlert(_ context.Context, _ string) error { return nil }
@@ -25,8 +98,8 @@ func (m *mockRedis) FetchMessageText(_ context.Context, _ string) (string, bool,
 	return "", false, nil
 }
 
-func (m *mockRedis) SubscribeAlerts(_ context.Context) (<-chan struct{}, error) {
-	return make(chan struct{}), nil
+func (m *mockRedis) SubscribeAlerts(_ context.Context) (<-chan redis.AlertEvent, error) {
+	return m.events, nil
 }
 
 func brightnessCfg() config.BrightnessConfig {
@@ -289,3 +362,142 @@ func TestEngine_Run_RunsWidgetWithMatchingCron(t *testing.T) {
 		t.Error("expected frames for matching cron widget, got none")
 	}
 }
+
+func TestEngine_Run_MPDInterruptsRotation(t *testing.T) {
+	srv := mpdtest.NewServer(t)
+	spy := &testutil.SpyDisplay{}
+	cfg := &config.Config{
+		Brightness: brightnessCfg(),
+		Widgets: []config.WidgetConfig{
+			// A static message with no duration holds the display indefinitely.
+			{Type: "message", Enabled: true, Text: "Hi"},
+			{Type: "mpd", Enabled: true, Addr: srv.Addr, ScrollSpeed: config.Duration(time.Millisecond)},
+		},
+	}
+
+	e := New(spy, cfg, nil)
+	e.nowFunc = func() time.Time {
+		return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		srv.Play("1", "Artist", "Title")
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	e.Run(ctx) //nolint:errcheck
+
+	if len(spy.Frames) < 2 {
+		t.Errorf("expected the mpd widget to interrupt the message, got %d frames", len(spy.Frames))
+	}
+}
+
+func TestHoldMaxBrightness_OverridesBrightnessLoop(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	cfg := &config.Config{Brightness: brightnessCfg()}
+	e := New(spy, cfg, nil)
+	e.nowFunc = func() time.Time {
+		return time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC) // night → low
+	}
+
+	release := e.holdMaxBrightness()
+	e.updateBrightness() // a brightnessLoop tick during the alert
+	release()
+	release() // releasing twice is harmless
+
+	want := []byte{15, 15, 1}
+	if len(spy.Brightness) != len(want) {
+		t.Fatalf("brightness = %v, want %v", spy.Brightness, want)
+	}
+	for i := range want {
+		if spy.Brightness[i] != want[i] {
+			t.Errorf("brightness = %v, want %v", spy.Brightness, want)
+			break
+		}
+	}
+}
+
+func TestBuildWidgets_GaugeRange(t *testing.T) {
+	zero := 0.0
+	tests := []struct {
+		name string
+		min  float64
+		max  *float64
+		want int
+	}{
+		{"default max", 0, nil, 1},
+		{"explicit zero max", -10, &zero, 1},
+		{"max equals min", 0, &zero, 0},
+		{"min above default max", 150, nil, 0},
+	}
+	for _, tt := range tests {
+		cfg := &config.Config{Widgets: []config.WidgetConfig{
+			{Type: "gauge", Enabled: true, Path: "/tmp/level", Min: tt.min, Max: tt.max},
+		}}
+		e := New(&testutil.SpyDisplay{}, cfg, nil)
+		e.SetPolicy(Policy{Trusted: true})
+		if widgets, _, _, _ := e.buildWidgets(); len(widgets) != tt.want {
+			t.Errorf("%s: %d gauges built, want %d", tt.name, len(widgets), tt.want)
+		}
+	}
+}
+
+// seriesRedis adds time series to mockRedis for chart widgets.
+type seriesRedis struct{ *mockRedis }
+
+func (seriesRedis) FetchSeries(context.Context, string, int) ([]float64, error) { return nil, nil }
+
+func TestBuildWidgets_ChartScale(t *testing.T) {
+	ptr := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name     string
+		min      float64
+		max      *float64
+		lo, hi   float64
+		autoLow  bool
+		autoHigh bool
+	}{
+		{"auto", 0, nil, 0, 0, true, true},
+		{"min only", 20, nil, 20, 0, false, true},
+		{"max only", 0, ptr(50), 0, 50, true, false},
+		{"both", -10, ptr(10), -10, 10, false, false},
+	}
+	for _, tt := range tests {
+		cfg := &config.Config{Widgets: []config.WidgetConfig{
+			{Type: "chart", Enabled: true, DynamicSource: "temps", Min: tt.min, Max: tt.max},
+		}}
+		e := New(&testutil.SpyDisplay{}, cfg, nil)
+		e.rds = seriesRedis{&mockRedis{}}
+		widgets, _, _, _ := e.buildWidgets()
+		if len(widgets) != 1 {
+			t.Fatalf("%s: %d charts built, want 1", tt.name, len(widgets))
+		}
+		c := widgets[0].(*widget.Chart)
+		if (c.Min == nil) != tt.autoLow || (c.Min != nil && *c.Min != tt.lo) {
+			t.Errorf("%s: Min = %v, want auto %v or %g", tt.name, c.Min, tt.autoLow, tt.lo)
+		}
+		if (c.Max == nil) != tt.autoHigh || (c.Max != nil && *c.Max != tt.hi) {
+			t.Errorf("%s: Max = %v, want auto %v or %g", tt.name, c.Max, tt.autoHigh, tt.hi)
+		}
+	}
+}
+
+type downPinger struct{}
+
+func (downPinger) Ping(context.Context) error { return context.DeadlineExceeded }
+
+func TestNetworkWidget_RedisProbeWithoutRedis(t *testing.T) {
+	e := New(&testutil.SpyDisplay{}, &config.Config{}, nil)
+	if w := e.networkWidget(config.WidgetConfig{}).(*widget.Network); w.Probe.Pinger != nil {
+		t.Error("pinger set without a configured Redis")
+	}
+	e.SetRedisProbe(downPinger{})
+	w := e.networkWidget(config.WidgetConfig{}).(*widget.Network)
+	if w.Probe.Pinger == nil {
+		t.Fatal("configured but unreachable Redis not probed")
+	}
+	if st := w.Probe.Status(context.Background()); st.Reachable == nil || *st.Reachable {
+		t.Errorf("Reachable = %v, want false", st.Reachable)
+	}
+}
@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrorLog logs failures and keeps the last one for the heartbeat. Failures
+// are reported to it where they happen instead of being picked out of the
+// log output. A nil *ErrorLog only logs.
+type ErrorLog struct {
+	mu   sync.Mutex
+	last string
+	at   time.Time
+}
+
+// Printf logs a failure like log.Printf and records it as the last error.
+func (l *ErrorLog) Printf(format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
+	log.Output(2, msg) //nolint:errcheck
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.last, l.at = msg, time.Now()
+	l.mu.Unlock()
+}
+
+// Last returns the last failure and when it was recorded, or "" and the
+// zero time when there was none.
+func (l *ErrorLog) Last() (string, time.Time) {
+	if l == nil {
+		return "", time.Time{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.last, l.at
+}
+
+// SetErrorLog sets where the engine reports failures, such as Redis errors.
+func (e *Engine) SetErrorLog(l *ErrorLog) {
+	e.errs = l
+}
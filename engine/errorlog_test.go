@@ -0,0 +1,21 @@
+package engine
+
+import "testing"
+
+func TestErrorLog_KeepsLastFailure(t *testing.T) {
+	l := &ErrorLog{}
+	if msg, at := l.Last(); msg != "" || !at.IsZero() {
+		t.Errorf("empty log: Last() = %q, %v", msg, at)
+	}
+	l.Printf("redis alert fetch failed: %v", "timeout")
+	l.Printf("redis ack %s: %v", "a", "refused")
+	if msg, at := l.Last(); msg != "redis ack a: refused" || at.IsZero() {
+		t.Errorf("Last() = %q, %v", msg, at)
+	}
+
+	var none *ErrorLog
+	none.Printf("only logged")
+	if msg, _ := none.Last(); msg != "" {
+		t.Errorf("nil log: Last() = %q", msg)
+	}
+}
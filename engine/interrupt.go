@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/redis"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// knownAlerts returns the IDs of the alerts already in Redis, which must
+// not interrupt when they are rewritten (acks, snoozes). Snoozed and
+// not-yet-started alerts count too, although they are not shown yet.
+func (e *Engine) knownAlerts(ctx context.Context) map[string]bool {
+	known := map[string]bool{}
+	if e.rds == nil {
+		return known
+	}
+	ids, err := e.rds.AlertIDs(ctx)
+	if err != nil {
+		e.errs.Printf("redis alert scan failed: %v", err)
+	}
+	for _, id := range ids {
+		known[id] = true
+	}
+	return known
+}
+
+// shouldInterrupt reports whether ev announces a newly created alert that
+// may interrupt the rotation. known tracks the alerts seen so far. Alerts
+// that Redis expired are passed to the alert log, which records each expiry
+// once across all clocks.
+func (e *Engine) shouldInterrupt(ctx context.Context, known map[string]bool, ev redis.AlertEvent) bool {
+	if ev.Removed {
+		if ev.Expired {
+			e.logExpired(ctx, ev.ID)
+		}
+		delete(known, ev.ID)
+		return false
+	}
+	if known[ev.ID] {
+		return false
+	}
+	known[ev.ID] = true
+
+	alerts, err := e.rds.FetchAlerts(ctx)
+	if err != nil {
+		e.errs.Printf("redis interrupt fetch failed: %v", err)
+		return false
+	}
+	for _, a := range alerts {
+		if a.ID == ev.ID {
+			return e.interruptAllowed(a)
+		}
+	}
+	// Not live yet (starts_at, snooze_until) or already gone.
+	return false
+}
+
+// interruptAllowed applies the configured priority threshold and quiet hours.
+func (e *Engine) interruptAllowed(a config.AlertConfig) bool {
+	ic := e.cfg.Interrupts
+	if ic.MaxPriority != nil && a.Priority > *ic.MaxPriority {
+		return false
+	}
+	if a.Severity != config.SeverityCritical && e.quietHours(e.now()) {
+		log.Printf("alert %s: interrupt suppressed during quiet hours", a.ID)
+		return false
+	}
+	return true
+}
+
+// quietHours reports whether now falls within the configured quiet hours.
+func (e *Engine) quietHours(now time.Time) bool {
+	ic := e.cfg.Interrupts
+	if ic.QuietStart == "" || ic.QuietEnd == "" {
+		return false
+	}
+	start, err1 := time.Parse("15:04", ic.QuietStart)
+	end, err2 := time.Parse("15:04", ic.QuietEnd)
+	if err1 != nil || err2 != nil {
+		log.Printf("interrupts: invalid quiet hours %q-%q", ic.QuietStart, ic.QuietEnd)
+		return false
+	}
+	nowMinutes := now.Hour()*60 + now.Minute()
+	startMinutes := start.Hour()*60 + start.Minute()
+	endMinutes := end.Hour()*60 + end.Minute()
+	if startMinutes <= endMinutes {
+		return nowMinutes >= startMinutes && nowMinutes < endMinutes
+	}
+	// Spans midnight, e.g. 22:00-07:00.
+	return nowMinutes >= startMinutes || nowMinutes < endMinutes
+}
+
+// interruptTimeout bounds an interrupt by the display durations of the
+// alerts as grouped for display, including group summaries.
+func interruptTimeout(alerts []config.AlertConfig) time.Duration {
+	order := make([]int, len(alerts))
+	for i := range order {
+		order[i] = i
+	}
+	var total time.Duration
+	for _, item := range widget.GroupAlerts(alerts, order) {
+		d := item.Alert.DisplayDuration.Unwrap()
+		if d == 0 {
+			d = 5 * time.Second // the alert widgets' default
+		}
+		total += d
+	}
+	return total
+}
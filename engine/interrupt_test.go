@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/redis"
+)
+
+func TestShouldInterrupt_OnlyNewAlerts(t *testing.T) {
+	m := &mockRedis{}
+	m.setAlerts(config.AlertConfig{ID: "old", Priority: 1}, config.AlertConfig{ID: "new", Priority: 1})
+	e := New(&testutil.SpyDisplay{}, &config.Config{}, nil)
+	e.rds = m
+	known := map[string]bool{"old": true}
+	ctx := context.Background()
+
+	if e.shouldInterrupt(ctx, known, redis.AlertEvent{ID: "old"}) {
+		t.Error("rewriting a known alert (e.g. an ack) should not interrupt")
+	}
+	if !e.shouldInterrupt(ctx, known, redis.AlertEvent{ID: "new"}) {
+		t.Error("a new alert should interrupt")
+	}
+	if e.shouldInterrupt(ctx, known, redis.AlertEvent{ID: "new"}) {
+		t.Error("an alert should interrupt only once")
+	}
+	if e.shouldInterrupt(ctx, known, redis.AlertEvent{ID: "new", Removed: true}) {
+		t.Error("deletes should not interrupt")
+	}
+	if !e.shouldInterrupt(ctx, known, redis.AlertEvent{ID: "new"}) {
+		t.Error("a recreated alert should interrupt again")
+	}
+}
+
+func TestKnownAlerts_IncludesAlertsNotLive(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+	m := &mockRedis{}
+	m.setAlerts(
+		config.AlertConfig{ID: "live", Priority: 1},
+		config.AlertConfig{ID: "snoozed", Priority: 1, SnoozeUntil: &later},
+		config.AlertConfig{ID: "scheduled", Priority: 1, StartsAt: &later},
+	)
+	e := New(&testutil.SpyDisplay{}, &config.Config{}, nil)
+	e.rds = m
+	ctx := context.Background()
+	known := e.knownAlerts(ctx)
+
+	// Rewrites such as an ack or a new snooze_until arrive as events once
+	// the alerts are live.
+	m.setAlerts(config.AlertConfig{ID: "snoozed", Priority: 1}, config.AlertConfig{ID: "scheduled", Priority: 1})
+	for _, id := range []string{"live", "snoozed", "scheduled"} {
+		if e.shouldInterrupt(ctx, known, redis.AlertEvent{ID: id}) {
+			t.Errorf("rewriting existing alert %s should not interrupt", id)
+		}
+	}
+}
+
+func TestInterruptAllowed(t *testing.T) {
+	maxPriority := 3
+	cfg := &config.Config{Interrupts: config.InterruptConfig{
+		MaxPriority: &maxPriority,
+		QuietStart:  "22:00",
+		QuietEnd:    "07:00",
+	}}
+	e := New(&testutil.SpyDisplay{}, cfg, nil)
+
+	tests := []struct {
+		name  string
+		hour  int
+		alert config.AlertConfig
+		want  bool
+	}{
+		{"urgent by day", 12, config.AlertConfig{Priority: 1}, true},
+		{"at threshold", 12, config.AlertConfig{Priority: 3}, true},
+		{"below threshold", 12, config.AlertConfig{Priority: 5}, false},
+		{"quiet hours", 23, config.AlertConfig{Priority: 1, Severity: config.SeverityWarn}, false},
+		{"quiet hours after midnight", 6, config.AlertConfig{Priority: 1}, false},
+		{"critical in quiet hours", 23, config.AlertConfig{Priority: 1, Severity: config.SeverityCritical}, true},
+		{"quiet hours over", 7, config.AlertConfig{Priority: 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e.nowFunc = func() time.Time { return time.Date(2024, 1, 1, tt.hour, 0, 0, 0, time.UTC) }
+			if got := e.interruptAllowed(tt.alert); got != tt.want {
+				t.Errorf("interruptAllowed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterruptTimeout_FollowsDisplayDuration(t *testing.T) {
+	got := interruptTimeout([]config.AlertConfig{
+		{DisplayDuration: config.Duration(2 * time.Second)},
+		{}, // default 5s
+	})
+	if got != 7*time.Second {
+		t.Errorf("interruptTimeout = %v, want 7s", got)
+	}
+
+	// Duplicates show once; a group adds its summary line.
+	got = interruptTimeout([]config.AlertConfig{
+		{Message: "A", Group: "g", DisplayDuration: config.Duration(time.Second)},
+		{Message: "A", Group: "g", DisplayDuration: config.Duration(time.Second)},
+		{Message: "B", Group: "g", DisplayDuration: config.Duration(time.Second)},
+	})
+	if got != 3*time.Second {
+		t.Errorf("grouped interruptTimeout = %v, want 3s", got)
+	}
+}
+
+func TestEngine_Run_AlertInterruptResumesWidget(t *testing.T) {
+	m := &mockRedis{events: make(chan redis.AlertEvent, 1)}
+	spy := &testutil.SpyDisplay{}
+	cfg := &config.Config{
+		Brightness: brightnessCfg(),
+		Widgets: []config.WidgetConfig{
+			// A static message with no duration holds the display indefinitely.
+			{Type: "message", Enabled: true, Text: "Hi"},
+			{Type: "message", Enabled: true, Text: "Yo"},
+		},
+	}
+	e := New(spy, cfg, nil)
+	e.rds = m
+	e.nowFunc = func() time.Time {
+		return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		m.setAlerts(config.AlertConfig{ID: "a", Message: "!", Priority: 1, DisplayDuration: config.Duration(50 * time.Millisecond)})
+		m.events <- redis.AlertEvent{ID: "a"}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	e.Run(ctx) //nolint:errcheck
+
+	if len(spy.Frames) != 3 {
+		t.Fatalf("expected message, alert, message frames, got %d", len(spy.Frames))
+	}
+	if !bytes.Equal(spy.Frames[0], spy.Frames[2]) || bytes.Equal(spy.Frames[0], spy.Frames[1]) {
+		t.Error("expected the interrupted message to resume after the alert")
+	}
+}
@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Policy limits what widgets may do on the clock host. Anyone who can write
+// kurokku:config in Redis controls the widget config, so unless the config
+// came from the local file, exec widgets may only run the command lines
+// listed here, templates and exec widgets may only read the environment
+// variables listed here and file widgets may only read below FileRoot.
+type Policy struct {
+	Trusted   bool     // the config was loaded from the local file
+	ExecAllow []string // command lines, with args, exec widgets may run from an untrusted config
+	EnvAllow  []string // environment variables an untrusted config may read
+	FileRoot  string   // directory file widgets may read from an untrusted config
+}
+
+// SetPolicy sets what the configured widgets may do. The zero Policy, the
+// default, allows no exec or file widgets.
+func (e *Engine) SetPolicy(p Policy) {
+	e.policy = p
+}
+
+// allowExec reports whether an exec widget may run command with args. An
+// untrusted config must match an ExecAllow entry word for word, args
+// included, so it cannot point an allowed program at other files.
+func (p Policy) allowExec(command string, args []string) bool {
+	if p.Trusted {
+		return true
+	}
+	line := append([]string{command}, args...)
+	for _, allowed := range p.ExecAllow {
+		if slices.Equal(strings.Fields(allowed), line) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowEnv reports whether widgets may read environment variable name.
+func (p Policy) allowEnv(name string) bool {
+	return p.Trusted || slices.Contains(p.EnvAllow, name)
+}
+
+// execEnv returns the names in envAllow an exec widget may pass to its
+// command. An untrusted config only gets those also in EnvAllow.
+func (p Policy) execEnv(envAllow []string) []string {
+	if p.Trusted {
+		return envAllow
+	}
+	var names []string
+	for _, name := range envAllow {
+		if p.allowEnv(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// allowSource reports whether a widget may load source, an http(s) URL or
+// a local path. Local paths go through allowFile.
+func (p Policy) allowSource(source string) bool {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return true
+	}
+	return p.allowFile(source)
+}
+
+// allowFile reports whether a file widget may read path. Symlinks are
+// resolved so a link under FileRoot cannot point outside it.
+func (p Policy) allowFile(path string) bool {
+	if p.Trusted {
+		return true
+	}
+	if p.FileRoot == "" || !filepath.IsAbs(path) {
+		return false
+	}
+	root, err := filepath.EvalSymlinks(p.FileRoot)
+	if err != nil {
+		return false
+	}
+	// The file may not exist yet; resolve its directory instead.
+	dir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return false
+	}
+	target := filepath.Join(dir, filepath.Base(path))
+	if _, err := os.Lstat(target); err == nil {
+		// Exists: follow it, refusing links that dangle for now.
+		if target, err = filepath.EvalSymlinks(target); err != nil {
+			return false
+		}
+	}
+	rel, err := filepath.Rel(root, target)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+func TestPolicy_ExecWidgets(t *testing.T) {
+	cfg := &config.Config{Widgets: []config.WidgetConfig{
+		{Type: "exec", Enabled: true, Command: "uptime"},
+		{Type: "exec", Enabled: true, Command: "rm", Args: []string{"-rf", "/"}},
+		{Type: "exec", Enabled: true, Command: "cat", Args: []string{"/etc/shadow"}},
+	}}
+	tests := []struct {
+		name   string
+		policy Policy
+		want   int
+	}{
+		{"untrusted", Policy{}, 0},
+		{"allow-listed", Policy{ExecAllow: []string{"uptime"}}, 1},
+		{"args must match", Policy{ExecAllow: []string{"uptime", "rm", "cat /etc/motd"}}, 1},
+		{"full command line", Policy{ExecAllow: []string{"cat  /etc/shadow"}}, 1},
+		{"trusted", Policy{Trusted: true}, 3},
+	}
+	for _, tt := range tests {
+		e := New(&testutil.SpyDisplay{}, cfg, nil)
+		e.SetPolicy(tt.policy)
+		if widgets, _, _, _ := e.buildWidgets(); len(widgets) != tt.want {
+			t.Errorf("%s: %d exec widgets built, want %d", tt.name, len(widgets), tt.want)
+		}
+	}
+}
+
+func TestPolicy_ExecEnv(t *testing.T) {
+	cfg := &config.Config{Widgets: []config.WidgetConfig{
+		{Type: "exec", Enabled: true, Command: "uptime", EnvAllow: []string{"SITE_NAME", "KUROKKU_PUSH_TOKEN"}},
+	}}
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []string
+	}{
+		{"untrusted", Policy{ExecAllow: []string{"uptime"}}, nil},
+		{"allow-listed", Policy{ExecAllow: []string{"uptime"}, EnvAllow: []string{"SITE_NAME"}}, []string{"SITE_NAME"}},
+		{"trusted", Policy{Trusted: true}, []string{"SITE_NAME", "KUROKKU_PUSH_TOKEN"}},
+	}
+	for _, tt := range tests {
+		e := New(&testutil.SpyDisplay{}, cfg, nil)
+		e.SetPolicy(tt.policy)
+		widgets, _, _, _ := e.buildWidgets()
+		if len(widgets) != 1 {
+			t.Fatalf("%s: %d widgets built, want 1", tt.name, len(widgets))
+		}
+		if got := widgets[0].(*widget.Exec).Runner.EnvAllow; !slices.Equal(got, tt.want) {
+			t.Errorf("%s: EnvAllow = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPolicy_FileWidgets(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(filepath.Join(outside, "secret"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		policy Policy
+		path   string
+		want   bool
+	}{
+		{"untrusted without root", Policy{}, filepath.Join(root, "status"), false},
+		{"under root", Policy{FileRoot: root}, filepath.Join(root, "status"), true},
+		{"outside root", Policy{FileRoot: root}, filepath.Join(outside, "secret"), false},
+		{"dot-dot", Policy{FileRoot: root}, filepath.Join(root, "..", filepath.Base(outside), "secret"), false},
+		{"symlink out", Policy{FileRoot: root}, filepath.Join(root, "link"), false},
+		{"relative", Policy{FileRoot: root}, "status", false},
+		{"trusted", Policy{Trusted: true}, "/etc/passwd", true},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.allowFile(tt.path); got != tt.want {
+			t.Errorf("%s: allowFile(%s) = %v, want %v", tt.name, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPolicy_FileSources(t *testing.T) {
+	root := t.TempDir()
+	inside := filepath.Join(root, "status")
+	cfg := &config.Config{Widgets: []config.WidgetConfig{
+		{Type: "gauge", Enabled: true, Path: "/dev/zero"},
+		{Type: "gauge", Enabled: true, Path: inside},
+		{Type: "calendar", Enabled: true, Path: "/etc/shadow"},
+		{Type: "calendar", Enabled: true, URL: "https://example.com/cal.ics"},
+		{Type: "transit", Enabled: true, Path: filepath.Join(root, "gtfs.zip"), Realtime: "/etc/shadow"},
+		{Type: "transit", Enabled: true, Path: filepath.Join(root, "gtfs.zip"), Realtime: "https://example.com/rt"},
+	}}
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []string
+	}{
+		{"untrusted", Policy{}, []string{"calendar"}},
+		{"under root", Policy{FileRoot: root}, []string{"gauge", "calendar", "transit"}},
+		{"trusted", Policy{Trusted: true}, []string{"gauge", "gauge", "calendar", "calendar", "transit", "transit"}},
+	}
+	for _, tt := range tests {
+		e := New(&testutil.SpyDisplay{}, cfg, nil)
+		e.SetPolicy(tt.policy)
+		widgets, _, _, _ := e.buildWidgets()
+		var got []string
+		for _, w := range widgets {
+			got = append(got, w.Name())
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: built %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPolicy_TemplateEnv(t *testing.T) {
+	t.Setenv("KUROKKU_TEST_SITE", "home")
+	cfg := &config.Config{Widgets: []config.WidgetConfig{
+		{Type: "template", Enabled: true, Text: `{{ env "KUROKKU_TEST_SITE" }}`},
+	}}
+	tests := []struct {
+		name   string
+		policy Policy
+		want   string
+	}{
+		{"untrusted", Policy{}, "--"},
+		{"allow-listed", Policy{EnvAllow: []string{"KUROKKU_TEST_SITE"}}, "home"},
+		{"trusted", Policy{Trusted: true}, "home"},
+	}
+	for _, tt := range tests {
+		e := New(&testutil.SpyDisplay{}, cfg, nil)
+		e.SetPolicy(tt.policy)
+		widgets, _, _, _ := e.buildWidgets()
+		if got := widgets[0].(*widget.Template).Eval(context.Background()); got != tt.want {
+			t.Errorf("%s: template = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type textValue struct {
+	value   string
+	expires time.Time // zero: never
+}
+
+// TextStore is an in-process store of dynamic text values used in place of
+// Redis when it is unavailable. It implements widget.MessageTextFetcher and
+// outlives engine restarts on reload.
+type TextStore struct {
+	nowFunc func() time.Time
+
+	mu     sync.Mutex
+	values map[string]textValue
+}
+
+// NewTextStore creates an empty text store.
+func NewTextStore() *TextStore {
+	return &TextStore{values: map[string]textValue{}}
+}
+
+func (t *TextStore) now() time.Time {
+	if t.nowFunc != nil {
+		return t.nowFunc()
+	}
+	return time.Now()
+}
+
+// SetMessageText stores value at key, expiring it after ttl when ttl > 0.
+func (t *TextStore) SetMessageText(_ context.Context, key, value string, ttl time.Duration) error {
+	v := textValue{value: value}
+	if ttl > 0 {
+		v.expires = t.now().Add(ttl)
+	}
+	t.mu.Lock()
+	t.values[key] = v
+	t.mu.Unlock()
+	return nil
+}
+
+// FetchMessageText returns the value at key. Returns ("", false, nil) if
+// the key does not exist or has expired.
+func (t *TextStore) FetchMessageText(_ context.Context, key string) (string, bool, error) {
+	now := t.now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	v, ok := t.values[key]
+	if !ok {
+		return "", false, nil
+	}
+	if !v.expires.IsZero() && !now.Before(v.expires) {
+		delete(t.values, key)
+		return "", false, nil
+	}
+	return v.value, true, nil
+}
@@ -0,0 +1,28 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTextStore_Expires(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ts := NewTextStore()
+	ts.nowFunc = func() time.Time { return now }
+	ctx := context.Background()
+
+	ts.SetMessageText(ctx, "kurokku:temp", "72F", time.Minute) //nolint:errcheck
+	ts.SetMessageText(ctx, "kurokku:motd", "hi", 0)            //nolint:errcheck
+	if v, ok, _ := ts.FetchMessageText(ctx, "kurokku:temp"); !ok || v != "72F" {
+		t.Errorf("temp = %q, %v; want 72F", v, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := ts.FetchMessageText(ctx, "kurokku:temp"); ok {
+		t.Error("temp should have expired")
+	}
+	if v, ok, _ := ts.FetchMessageText(ctx, "kurokku:motd"); !ok || v != "hi" {
+		t.Errorf("motd = %q, %v; want hi without ttl", v, ok)
+	}
+}
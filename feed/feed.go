@@ -0,0 +1,186 @@
+package feed
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Item is a single headline from an RSS or Atom feed.
+type Item struct {
+	ID        string // guid / id, falling back to link, then title
+	Title     string
+	Link      string
+	Published time.Time // zero when the feed gives no parseable date
+}
+
+type document struct {
+	XMLName xml.Name
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items   []rssItem   `xml:"item"` // RSS 1.0 (RDF) puts items beside the channel
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title   string `xml:"title"`
+	Link    string `xml:"link"`
+	GUID    string `xml:"guid"`
+	PubDate string `xml:"pubDate"`
+	Date    string `xml:"date"` // dc:date
+}
+
+type atomEntry struct {
+	Title     string `xml:"title"`
+	ID        string `xml:"id"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Links     []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+}
+
+// Parse reads an RSS 2.0, RSS 1.0 (RDF) or Atom document. Items are returned
+// in document order; items without a title are dropped.
+func Parse(r io.Reader) ([]Item, error) {
+	dec := xml.NewDecoder(bufio.NewReader(r))
+	dec.CharsetReader = charsetReader
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+
+	var doc document
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parsing feed: %w", err)
+	}
+
+	var items []Item
+	switch doc.XMLName.Local {
+	case "rss", "RDF":
+		for _, ri := range append(doc.Channel.Items, doc.Items...) {
+			date := ri.PubDate
+			if date == "" {
+				date = ri.Date
+			}
+			items = append(items, newItem(firstNonEmpty(ri.GUID, ri.Link), ri.Title, ri.Link, date))
+		}
+	case "feed":
+		for _, e := range doc.Entries {
+			var link string
+			for _, l := range e.Links {
+				if l.Rel == "" || l.Rel == "alternate" {
+					link = l.Href
+					break
+				}
+			}
+			items = append(items, newItem(firstNonEmpty(e.ID, link), e.Title, link, firstNonEmpty(e.Published, e.Updated)))
+		}
+	default:
+		return nil, fmt.Errorf("unrecognised feed root element <%s>", doc.XMLName.Local)
+	}
+
+	out := items[:0]
+	for _, it := range items {
+		if it.Title != "" {
+			out = append(out, it)
+		}
+	}
+	return out, nil
+}
+
+func newItem(id, title, link, date string) Item {
+	title = cleanTitle(title)
+	link = strings.TrimSpace(link)
+	return Item{
+		ID:        firstNonEmpty(strings.TrimSpace(id), link, title),
+		Title:     title,
+		Link:      link,
+		Published: parseDate(date),
+	}
+}
+
+var tagRe = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+
+// cleanTitle strips markup left in escaped HTML titles and collapses whitespace.
+func cleanTitle(s string) string {
+	s = tagRe.ReplaceAllString(html.UnescapeString(s), "")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func parseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// charsetReader handles the single-byte encodings still common on older
+// feeds; anything else is rejected by the decoder.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "latin1", "latin-1", "windows-1252", "cp1252":
+		return &latin1Reader{r: bufio.NewReader(input)}, nil
+	}
+	return nil, fmt.Errorf("unsupported feed charset %q", charset)
+}
+
+// latin1Reader converts ISO-8859-1 bytes to UTF-8.
+type latin1Reader struct {
+	r   io.ByteReader
+	buf []byte
+}
+
+func (l *latin1Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(l.buf) > 0 {
+			c := copy(p[n:], l.buf)
+			l.buf = l.buf[c:]
+			n += c
+			continue
+		}
+		b, err := l.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		l.buf = utf8.AppendRune(l.buf[:0], rune(b))
+	}
+	return n, nil
+}
@@ -0,0 +1,95 @@
+package feed_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/feed"
+)
+
+func TestParse_RSS(t *testing.T) {
+	data := `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>News</title>
+<item><title>First &amp; foremost</title><link>https://example.com/1</link>
+<guid>id-1</guid><pubDate>Mon, 05 Jan 2026 10:00:00 +0000</pubDate></item>
+<item><title>&lt;b&gt;Bold&lt;/b&gt;   claim</title><link>https://example.com/2</link>
+<pubDate>Tue, 6 Jan 2026 08:30:00 GMT</pubDate></item>
+<item><link>https://example.com/untitled</link></item>
+</channel></rss>`
+	items, err := feed.Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 titled items, got %d", len(items))
+	}
+	if items[0].Title != "First & foremost" || items[0].ID != "id-1" {
+		t.Errorf("item 0 = %+v", items[0])
+	}
+	if !items[0].Published.Equal(time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("item 0 published = %v", items[0].Published)
+	}
+	if items[1].Title != "Bold claim" {
+		t.Errorf("expected markup stripped, got %q", items[1].Title)
+	}
+	if items[1].ID != "https://example.com/2" {
+		t.Errorf("expected link as fallback ID, got %q", items[1].ID)
+	}
+	if items[1].Published.IsZero() {
+		t.Error("expected single-digit day RFC1123 date to parse")
+	}
+}
+
+func TestParse_Atom(t *testing.T) {
+	data := `<feed xmlns="http://www.w3.org/2005/Atom"><title>Blog</title>
+<entry><title type="text">Release 2.0</title><id>urn:uuid:1</id>
+<link rel="self" href="https://example.com/self"/><link href="https://example.com/r2"/>
+<updated>2026-01-05T12:00:00Z</updated></entry>
+</feed>`
+	items, err := feed.Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(items))
+	}
+	it := items[0]
+	if it.Title != "Release 2.0" || it.ID != "urn:uuid:1" || it.Link != "https://example.com/r2" {
+		t.Errorf("entry = %+v", it)
+	}
+	if it.Published.IsZero() {
+		t.Error("expected updated to be used when published is absent")
+	}
+}
+
+func TestParse_RDF(t *testing.T) {
+	data := `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel><title>Old</title></channel>
+<item><title>Legacy headline</title><link>https://example.com/l</link><dc:date>2026-01-05T09:00:00Z</dc:date></item>
+</rdf:RDF>`
+	items, err := feed.Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Title != "Legacy headline" || items[0].Published.IsZero() {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+func TestParse_Latin1(t *testing.T) {
+	data := "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><rss><channel><item><title>Caf\xe9</title></item></channel></rss>"
+	items, err := feed.Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Title != "Café" {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+func TestParse_Unrecognised(t *testing.T) {
+	if _, err := feed.Parse(strings.NewReader("<html><body/></html>")); err == nil {
+		t.Error("expected error for non-feed document")
+	}
+}
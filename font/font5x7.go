@@ -102,6 +102,7 @@ var Font5x7 = map[rune]Glyph{
 	'}':  {0x00, 0x41, 0x36, 0x08, 0x00},
 	'~':  {0x10, 0x08, 0x08, 0x10, 0x10},
 	'°':  {0x00, 0x02, 0x05, 0x02, 0x00},
+	'⚠':  {0x78, 0x7E, 0x53, 0x7E, 0x78}, // warning triangle
 }
 
 // RenderText renders a string into a column-based framebuffer.
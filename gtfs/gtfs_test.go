@@ -0,0 +1,195 @@
+package gtfs_test
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/gtfs"
+)
+
+var feedFiles = map[string]string{
+	"agency.txt": "agency_id,agency_name,agency_url,agency_timezone\nA,Metro,https://example.com,UTC\n",
+	"routes.txt": "route_id,route_short_name,route_type\nr12,12,3\nr4,4,3\nrX,,3\n",
+	"trips.txt": "\ufeffroute_id,service_id,trip_id,trip_headsign\n" +
+		"r12,WK,t1,Downtown\nr12,WK,t2,Downtown\nr4,WK,t3,Airport\nr12,WK,late,Downtown\nrX,SAT,t5,Beach\nr4,HOL,t6,Airport\n",
+	"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" +
+		"t1,08:00:00,08:00:00,S0,1\nt1,08:05:00,08:05:00,S1,2\n" +
+		"t2,08:17:00,08:17:00,S1,5\n" +
+		"t3,08:10:00,08:10:00,S1,3\n" +
+		"late,25:10:00,25:10:00,S1,9\n" +
+		"t5,09:00:00,09:00:00,S1,1\n" +
+		"t6,08:30:00,08:30:00,S1,1\n",
+	// WK: weekdays in January 2026, except Fri 9th; HOL only on Sat 10th.
+	"calendar.txt": "service_id,monday,tuesday,wednesday,thursday,friday,saturday,sunday,start_date,end_date\n" +
+		"WK,1,1,1,1,1,0,0,20260101,20260131\nSAT,0,0,0,0,0,1,0,20260101,20260131\n",
+	"calendar_dates.txt": "service_id,date,exception_type\nWK,20260109,2\nHOL,20260110,1\n",
+}
+
+func writeFeed(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "gtfs.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body)) //nolint:errcheck
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	return path
+}
+
+func summary(deps []gtfs.Departure) string {
+	var parts []string
+	for _, d := range deps {
+		parts = append(parts, d.Route+"@"+d.Time.Format("Mon 15:04"))
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestNext_WeekdayService(t *testing.T) {
+	s, err := gtfs.Load(writeFeed(t, feedFiles), gtfs.Filter{Stops: []string{"S1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 6, 8, 0, 0, 0, time.UTC) // Tuesday
+	got := summary(s.Next(now, 4, nil))
+	want := "12@Tue 08:05 4@Tue 08:10 12@Tue 08:17 12@Wed 01:10"
+	if got != want {
+		t.Errorf("Next:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestNext_AfterMidnightTripFromPreviousServiceDay(t *testing.T) {
+	s, err := gtfs.Load(writeFeed(t, feedFiles), gtfs.Filter{Stops: []string{"S1"}, Routes: []string{"12"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 7, 1, 0, 0, 0, time.UTC) // Wednesday 01:00
+	deps := s.Next(now, 1, nil)
+	if len(deps) != 1 || deps[0].TripID != "late" || deps[0].Headsign != "Downtown" {
+		t.Errorf("expected Tuesday's 25:10 trip, got %+v", deps)
+	}
+}
+
+func TestNext_CalendarDates(t *testing.T) {
+	s, err := gtfs.Load(writeFeed(t, feedFiles), gtfs.Filter{Stops: []string{"S1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Friday 9th: weekday service removed; Thursday's 25:10 trip still runs.
+	fri := time.Date(2026, 1, 9, 7, 0, 0, 0, time.UTC)
+	if got := summary(s.Next(fri, 0, nil)); strings.Contains(got, "Fri") {
+		t.Errorf("expected no Friday departures, got %s", got)
+	}
+	// Saturday 10th: SAT by calendar plus the added HOL service.
+	sat := time.Date(2026, 1, 10, 7, 0, 0, 0, time.UTC)
+	if got, want := summary(s.Next(sat, 2, nil)), "4@Sat 08:30 rX@Sat 09:00"; got != want {
+		t.Errorf("Saturday: got %s, want %s", got, want)
+	}
+}
+
+func TestNext_RouteFilterByID(t *testing.T) {
+	s, err := gtfs.Load(writeFeed(t, feedFiles), gtfs.Filter{Stops: []string{"S1"}, Routes: []string{"r4"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range s.Next(time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC), 0, nil) {
+		if d.Route != "4" {
+			t.Errorf("unexpected route %q", d.Route)
+		}
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	if _, err := gtfs.Load(writeFeed(t, feedFiles), gtfs.Filter{}); err == nil {
+		t.Error("expected error without stops")
+	}
+	files := map[string]string{"trips.txt": feedFiles["trips.txt"]}
+	if _, err := gtfs.Load(writeFeed(t, files), gtfs.Filter{Stops: []string{"S1"}}); err == nil {
+		t.Error("expected error for missing stop_times.txt")
+	}
+}
+
+// Minimal protobuf encoding for building GTFS-realtime fixtures.
+
+func pbVarint(num int, v uint64) []byte {
+	b := binary.AppendUvarint(nil, uint64(num)<<3)
+	return binary.AppendUvarint(b, v)
+}
+
+func pbBytes(num int, data []byte) []byte {
+	b := binary.AppendUvarint(nil, uint64(num)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func cat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func tripUpdate(tripID string, fields ...[]byte) []byte {
+	trip := pbBytes(1, pbBytes(1, []byte(tripID)))
+	return pbBytes(2, cat(pbBytes(1, []byte("e-"+tripID)), pbBytes(3, cat(append([][]byte{trip}, fields...)...))))
+}
+
+func stopUpdate(seq int, fields ...[]byte) []byte {
+	return pbBytes(2, cat(append([][]byte{pbVarint(1, uint64(seq))}, fields...)...))
+}
+
+func TestNext_RealtimeAdjustments(t *testing.T) {
+	s, err := gtfs.Load(writeFeed(t, feedFiles), gtfs.Filter{Stops: []string{"S1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delay := func(secs int32) []byte { return pbBytes(3, pbVarint(1, uint64(int64(secs)))) }
+	msg := cat(
+		pbBytes(1, cat(pbBytes(1, []byte("2.0")), pbVarint(3, 1767686400))),
+		// t1: 4 minutes late at stop seq 1, carried forward to S1 (seq 2).
+		tripUpdate("t1", stopUpdate(1, delay(240))),
+		// t3: canceled.
+		pbBytes(2, pbBytes(3, pbBytes(1, cat(pbBytes(1, []byte("t3")), pbVarint(4, 3))))),
+		// t2: one minute early at its S1 stop.
+		tripUpdate("t2", stopUpdate(5, delay(-60))),
+	)
+	rt, err := gtfs.ParseRealtime(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt.Timestamp.Unix() != 1767686400 {
+		t.Errorf("Timestamp = %v", rt.Timestamp)
+	}
+
+	now := time.Date(2026, 1, 6, 8, 0, 0, 0, time.UTC)
+	deps := s.Next(now, 2, rt)
+	if got, want := summary(deps), "12@Tue 08:09 12@Tue 08:16"; got != want {
+		t.Errorf("with realtime: got %s, want %s", got, want)
+	}
+	if deps[0].Delay != 4*time.Minute || deps[1].Delay != -time.Minute {
+		t.Errorf("delays = %v, %v", deps[0].Delay, deps[1].Delay)
+	}
+}
+
+func TestParseRealtime_Truncated(t *testing.T) {
+	msg := pbBytes(2, []byte("xx"))
+	if _, err := gtfs.ParseRealtime(msg[:len(msg)-1]); err == nil {
+		t.Error("expected error for truncated message")
+	}
+}
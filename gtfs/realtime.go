@@ -0,0 +1,246 @@
+package gtfs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Realtime holds the trip updates from a GTFS-realtime FeedMessage. Only the
+// fields needed to adjust departures are decoded: trip cancellations, stop
+// skips, and per-stop delays or absolute times.
+type Realtime struct {
+	Timestamp time.Time
+	trips     map[string]*tripUpdate
+}
+
+type tripUpdate struct {
+	canceled bool
+	delay    *int32 // trip-level delay in seconds
+	stops    []stopUpdate
+}
+
+type stopUpdate struct {
+	seq     int32 // 0 when absent
+	stopID  string
+	delay   *int32
+	time    int64 // absolute departure/arrival time, 0 when absent
+	skipped bool
+}
+
+// GTFS-realtime enum values.
+const (
+	tripCanceled = 3
+	stopSkipped  = 1
+)
+
+// ParseRealtime decodes a GTFS-realtime FeedMessage.
+func ParseRealtime(b []byte) (*Realtime, error) {
+	rt := &Realtime{trips: make(map[string]*tripUpdate)}
+	err := eachField(b, func(num int, wt wireType, v uint64, data []byte) error {
+		switch {
+		case num == 1 && wt == wireBytes: // header
+			return eachField(data, func(num int, wt wireType, v uint64, _ []byte) error {
+				if num == 3 && wt == wireVarint { // timestamp
+					rt.Timestamp = time.Unix(int64(v), 0)
+				}
+				return nil
+			})
+		case num == 2 && wt == wireBytes: // entity
+			return eachField(data, func(num int, wt wireType, _ uint64, data []byte) error {
+				if num == 3 && wt == wireBytes { // trip_update
+					return rt.parseTripUpdate(data)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parsing gtfs-realtime feed: %w", err)
+	}
+	return rt, nil
+}
+
+func (rt *Realtime) parseTripUpdate(b []byte) error {
+	var tripID string
+	tu := &tripUpdate{}
+	err := eachField(b, func(num int, wt wireType, v uint64, data []byte) error {
+		switch {
+		case num == 1 && wt == wireBytes: // trip descriptor
+			return eachField(data, func(num int, wt wireType, v uint64, data []byte) error {
+				switch {
+				case num == 1 && wt == wireBytes:
+					tripID = string(data)
+				case num == 4 && wt == wireVarint:
+					tu.canceled = v == tripCanceled
+				}
+				return nil
+			})
+		case num == 2 && wt == wireBytes: // stop_time_update
+			su, err := parseStopUpdate(data)
+			if err != nil {
+				return err
+			}
+			tu.stops = append(tu.stops, su)
+		case num == 5 && wt == wireVarint: // delay
+			d := int32(v)
+			tu.delay = &d
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if tripID != "" {
+		sort.SliceStable(tu.stops, func(i, j int) bool { return tu.stops[i].seq < tu.stops[j].seq })
+		rt.trips[tripID] = tu
+	}
+	return nil
+}
+
+func parseStopUpdate(b []byte) (stopUpdate, error) {
+	var su stopUpdate
+	var arrival, departure stopUpdate
+	err := eachField(b, func(num int, wt wireType, v uint64, data []byte) error {
+		switch {
+		case num == 1 && wt == wireVarint:
+			su.seq = int32(v)
+		case num == 4 && wt == wireBytes:
+			su.stopID = string(data)
+		case num == 2 && wt == wireBytes:
+			return parseStopTimeEvent(data, &arrival)
+		case num == 3 && wt == wireBytes:
+			return parseStopTimeEvent(data, &departure)
+		case num == 5 && wt == wireVarint:
+			su.skipped = v == stopSkipped
+		}
+		return nil
+	})
+	// Departure information wins over arrival, as the widget shows departures.
+	su.delay, su.time = arrival.delay, arrival.time
+	if departure.delay != nil || departure.time != 0 {
+		su.delay, su.time = departure.delay, departure.time
+	}
+	return su, err
+}
+
+func parseStopTimeEvent(b []byte, su *stopUpdate) error {
+	return eachField(b, func(num int, wt wireType, v uint64, _ []byte) error {
+		switch {
+		case num == 1 && wt == wireVarint:
+			d := int32(v)
+			su.delay = &d
+		case num == 2 && wt == wireVarint:
+			su.time = int64(v)
+		}
+		return nil
+	})
+}
+
+// adjust returns the realtime departure for a scheduled stop, or false when
+// the trip is canceled or the stop skipped. Following the GTFS-realtime
+// rules, a delay carries forward from the closest preceding stop update.
+func (rt *Realtime) adjust(tripID, stopID string, seq int32, scheduled time.Time) (time.Time, bool) {
+	tu := rt.trips[tripID]
+	if tu == nil {
+		return scheduled, true
+	}
+	if tu.canceled {
+		return time.Time{}, false
+	}
+	var match *stopUpdate
+	for i := range tu.stops {
+		su := &tu.stops[i]
+		if (su.seq != 0 && su.seq == seq) || (su.seq == 0 && su.stopID == stopID) {
+			match = su
+			break
+		}
+		if su.seq != 0 && su.seq < seq && su.delay != nil {
+			match = su // carried forward unless an exact match follows
+		}
+	}
+	if match == nil {
+		if tu.delay != nil {
+			return scheduled.Add(time.Duration(*tu.delay) * time.Second), true
+		}
+		return scheduled, true
+	}
+	if match.seq == seq || match.stopID == stopID {
+		if match.skipped {
+			return time.Time{}, false
+		}
+		if match.time != 0 {
+			return time.Unix(match.time, 0).In(scheduled.Location()), true
+		}
+	}
+	if match.delay != nil {
+		return scheduled.Add(time.Duration(*match.delay) * time.Second), true
+	}
+	return scheduled, true
+}
+
+// Protocol buffer wire format, just enough to walk GTFS-realtime messages.
+
+type wireType int
+
+const (
+	wireVarint  wireType = 0
+	wireFixed64 wireType = 1
+	wireBytes   wireType = 2
+	wireFixed32 wireType = 5
+)
+
+var errTruncated = errors.New("truncated protobuf message")
+
+// eachField calls fn for every field in a serialized message. For varints v
+// holds the value; for length-delimited fields data holds the payload.
+// Fixed-width fields are skipped.
+func eachField(b []byte, fn func(num int, wt wireType, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errTruncated
+		}
+		b = b[n:]
+		num, wt := int(key>>3), wireType(key&7)
+
+		var v uint64
+		var data []byte
+		switch wt {
+		case wireVarint:
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return errTruncated
+			}
+			b = b[n:]
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return errTruncated
+			}
+			data = b[n : n+int(l)]
+			b = b[n+int(l):]
+		case wireFixed64:
+			if len(b) < 8 {
+				return errTruncated
+			}
+			b = b[8:]
+			continue
+		case wireFixed32:
+			if len(b) < 4 {
+				return errTruncated
+			}
+			b = b[4:]
+			continue
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d", wt)
+		}
+		if err := fn(num, wt, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
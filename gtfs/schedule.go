@@ -0,0 +1,364 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter selects the part of a feed to keep in memory. Stops is required;
+// an empty Routes keeps every route serving those stops. Routes match either
+// route_id or route_short_name.
+type Filter struct {
+	Stops  []string
+	Routes []string
+}
+
+// Schedule is the subset of a static GTFS feed needed to answer "when is the
+// next departure from these stops". Only stop_times rows for the filtered
+// stops and routes are retained, so memory use scales with the number of
+// departures shown rather than with the size of the feed.
+type Schedule struct {
+	Location *time.Location // from agency.txt, default UTC
+
+	trips      []trip
+	departures []departure // sorted by time of day
+	services   map[string]*service
+}
+
+type trip struct {
+	id        string
+	route     string // short name, falling back to route_id
+	headsign  string
+	serviceID string
+}
+
+type departure struct {
+	trip int32 // index into Schedule.trips
+	secs int32 // seconds after "noon minus 12h" of the service day
+	seq  int32
+	stop string
+}
+
+type service struct {
+	weekdays   [7]bool // indexed by time.Weekday
+	start, end int     // YYYYMMDD, inclusive; 0 when calendar.txt has no row
+	added      map[int]bool
+	removed    map[int]bool
+}
+
+// Load reads the GTFS zip at path, keeping only what f selects.
+func Load(path string, f Filter) (*Schedule, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening gtfs feed: %w", err)
+	}
+	defer zr.Close()
+	return load(&zr.Reader, f)
+}
+
+func load(zr *zip.Reader, f Filter) (*Schedule, error) {
+	if len(f.Stops) == 0 {
+		return nil, errors.New("gtfs: no stops configured")
+	}
+	s := &Schedule{Location: time.UTC, services: make(map[string]*service)}
+
+	if err := eachRow(zr, "agency.txt", false, func(row map[string]string) error {
+		if tz := row["agency_timezone"]; tz != "" && s.Location == time.UTC {
+			if loc, err := time.LoadLocation(tz); err == nil {
+				s.Location = loc
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Pass 1: stop_times for the configured stops only. Trip IDs are interned
+	// so each is stored once however many stops it serves.
+	stops := make(map[string]string, len(f.Stops)) // interned stop IDs
+	for _, id := range f.Stops {
+		stops[id] = id
+	}
+	tripIndex := make(map[string]int32)
+	if err := eachRow(zr, "stop_times.txt", true, func(row map[string]string) error {
+		stop, ok := stops[row["stop_id"]]
+		if !ok {
+			return nil
+		}
+		t := row["departure_time"]
+		if t == "" {
+			t = row["arrival_time"]
+		}
+		secs, err := parseGTFSTime(t)
+		if err != nil {
+			return nil // untimed stop; interpolation is not supported
+		}
+		seq, _ := strconv.Atoi(row["stop_sequence"])
+		id := row["trip_id"]
+		idx, ok := tripIndex[id]
+		if !ok {
+			// Clone so the map does not pin the CSV reader's line buffer.
+			id = strings.Clone(id)
+			idx = int32(len(s.trips))
+			tripIndex[id] = idx
+			s.trips = append(s.trips, trip{id: id})
+		}
+		s.departures = append(s.departures, departure{trip: idx, secs: secs, seq: int32(seq), stop: stop})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Pass 2: trips, for the trips seen above.
+	routeOf := make(map[int32]string)
+	if err := eachRow(zr, "trips.txt", true, func(row map[string]string) error {
+		idx, ok := tripIndex[row["trip_id"]]
+		if !ok {
+			return nil
+		}
+		t := &s.trips[idx]
+		t.serviceID = strings.Clone(row["service_id"])
+		t.headsign = strings.Clone(row["trip_headsign"])
+		routeOf[idx] = strings.Clone(row["route_id"])
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Pass 3: route names, and the route filter.
+	names := make(map[string]string)
+	if err := eachRow(zr, "routes.txt", false, func(row map[string]string) error {
+		name := row["route_short_name"]
+		if name == "" {
+			name = row["route_id"]
+		}
+		names[strings.Clone(row["route_id"])] = strings.Clone(name)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(f.Routes))
+	for _, r := range f.Routes {
+		wanted[r] = true
+	}
+	keep := make([]bool, len(s.trips))
+	for idx, routeID := range routeOf {
+		name := names[routeID]
+		if name == "" {
+			name = routeID
+		}
+		s.trips[idx].route = name
+		keep[idx] = len(wanted) == 0 || wanted[routeID] || wanted[name]
+	}
+	kept := s.departures[:0]
+	usedServices := make(map[string]bool)
+	for _, d := range s.departures {
+		if keep[d.trip] {
+			kept = append(kept, d)
+			usedServices[s.trips[d.trip].serviceID] = true
+		}
+	}
+	// Copy so the backing array of discarded departures can be freed.
+	s.departures = append([]departure(nil), kept...)
+	sort.Slice(s.departures, func(i, j int) bool { return s.departures[i].secs < s.departures[j].secs })
+
+	// Pass 4: service calendars for the services in use.
+	svc := func(id string) *service {
+		sv := s.services[id]
+		if sv == nil {
+			sv = &service{added: map[int]bool{}, removed: map[int]bool{}}
+			s.services[id] = sv
+		}
+		return sv
+	}
+	days := [7]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+	if err := eachRow(zr, "calendar.txt", false, func(row map[string]string) error {
+		if !usedServices[row["service_id"]] {
+			return nil
+		}
+		sv := svc(row["service_id"])
+		for i, day := range days {
+			sv.weekdays[i] = row[day] == "1"
+		}
+		sv.start, _ = strconv.Atoi(row["start_date"])
+		sv.end, _ = strconv.Atoi(row["end_date"])
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := eachRow(zr, "calendar_dates.txt", false, func(row map[string]string) error {
+		if !usedServices[row["service_id"]] {
+			return nil
+		}
+		date, err := strconv.Atoi(row["date"])
+		if err != nil {
+			return nil
+		}
+		sv := svc(row["service_id"])
+		switch row["exception_type"] {
+		case "1":
+			sv.added[date] = true
+		case "2":
+			sv.removed[date] = true
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Departure is a scheduled (and possibly realtime-adjusted) departure.
+type Departure struct {
+	TripID   string
+	Route    string
+	Headsign string
+	StopID   string
+	Time     time.Time // scheduled time plus Delay
+	Delay    time.Duration
+}
+
+// Next returns up to n departures at or after now, soonest first. rt may be
+// nil; otherwise its delays are applied and canceled trips or skipped stops
+// are left out.
+func (s *Schedule) Next(now time.Time, n int, rt *Realtime) []Departure {
+	now = now.In(s.Location)
+	var out []Departure
+	// Service days yesterday..tomorrow cover trips running past midnight
+	// (times >= 24:00:00) as well as the early hours of the next day.
+	for offset := -1; offset <= 1; offset++ {
+		day := now.AddDate(0, 0, offset)
+		base := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, s.Location).Add(-12 * time.Hour)
+		date := day.Year()*10000 + int(day.Month())*100 + day.Day()
+		weekday := day.Weekday()
+
+		for _, d := range s.departures {
+			t := &s.trips[d.trip]
+			if !s.active(t.serviceID, date, weekday) {
+				continue
+			}
+			dep := Departure{
+				TripID:   t.id,
+				Route:    t.route,
+				Headsign: t.headsign,
+				StopID:   d.stop,
+				Time:     base.Add(time.Duration(d.secs) * time.Second),
+			}
+			if rt != nil {
+				adj, ok := rt.adjust(t.id, d.stop, d.seq, dep.Time)
+				if !ok {
+					continue
+				}
+				dep.Delay = adj.Sub(dep.Time)
+				dep.Time = adj
+			}
+			if !dep.Time.Before(now) {
+				out = append(out, dep)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+func (s *Schedule) active(serviceID string, date int, weekday time.Weekday) bool {
+	sv := s.services[serviceID]
+	if sv == nil {
+		return false
+	}
+	if sv.removed[date] {
+		return false
+	}
+	if sv.added[date] {
+		return true
+	}
+	return sv.start != 0 && date >= sv.start && date <= sv.end && sv.weekdays[weekday]
+}
+
+// parseGTFSTime parses "H:MM:SS" (hours may exceed 23) into seconds.
+func parseGTFSTime(s string) (int32, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid gtfs time %q", s)
+	}
+	var total int
+	for _, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, fmt.Errorf("invalid gtfs time %q", s)
+		}
+		total = total*60 + v
+	}
+	return int32(total), nil
+}
+
+// eachRow streams the rows of a CSV file in the zip, passing each as a map
+// from column name to value. The map is reused between calls.
+func eachRow(zr *zip.Reader, name string, required bool, fn func(map[string]string) error) error {
+	var zf *zip.File
+	for _, f := range zr.File {
+		// Some producers nest the files in a directory.
+		if f.Name == name || strings.HasSuffix(f.Name, "/"+name) {
+			zf = f
+			break
+		}
+	}
+	if zf == nil {
+		if required {
+			return fmt.Errorf("gtfs: missing %s", name)
+		}
+		return nil
+	}
+	rc, err := zf.Open()
+	if err != nil {
+		return fmt.Errorf("gtfs %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	r := csv.NewReader(rc)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.ReuseRecord = true
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("gtfs %s: %w", name, err)
+	}
+	cols := make([]string, len(header))
+	for i, h := range header {
+		cols[i] = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+	}
+
+	row := make(map[string]string, len(cols))
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("gtfs %s: %w", name, err)
+		}
+		for i, c := range cols {
+			if i < len(rec) {
+				row[c] = strings.TrimSpace(rec[i])
+			} else {
+				row[c] = ""
+			}
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event is a VEVENT. Start and End are absolute instants; for all-day events
+// they are midnight in the calendar's default location.
+type Event struct {
+	UID      string
+	Summary  string
+	Start    time.Time
+	End      time.Time
+	AllDay   bool
+	RRule    *RRule
+	ExDates  []time.Time
+	RecurID  time.Time // RECURRENCE-ID: this event overrides one occurrence of UID
+	Canceled bool
+}
+
+// Parse reads VEVENTs from an iCalendar stream. Times without a TZID or UTC
+// designator (floating times and all-day dates) are interpreted in loc.
+func Parse(r io.Reader, loc *time.Location) ([]Event, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var ev *Event
+	depth := 0 // nesting inside VEVENT (VALARM etc.)
+	for n, line := range lines {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			ev = &Event{}
+			depth = 0
+			continue
+		case ev == nil:
+			continue
+		case name == "BEGIN":
+			depth++
+			continue
+		case name == "END" && value == "VEVENT":
+			if ev.Start.IsZero() {
+				return nil, fmt.Errorf("line %d: VEVENT %q without DTSTART", n+1, ev.UID)
+			}
+			if ev.End.IsZero() {
+				ev.End = ev.Start
+				if ev.AllDay {
+					ev.End = ev.Start.AddDate(0, 0, 1)
+				}
+			}
+			events = append(events, *ev)
+			ev = nil
+			continue
+		case name == "END":
+			depth--
+			continue
+		case depth > 0:
+			continue
+		}
+
+		switch name {
+		case "UID":
+			ev.UID = value
+		case "SUMMARY":
+			ev.Summary = unescape(value)
+		case "STATUS":
+			ev.Canceled = strings.EqualFold(value, "CANCELLED")
+		case "DTSTART":
+			t, allDay, err := parseTime(value, params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: DTSTART: %w", n+1, err)
+			}
+			ev.Start, ev.AllDay = t, allDay
+		case "DTEND":
+			t, _, err := parseTime(value, params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: DTEND: %w", n+1, err)
+			}
+			ev.End = t
+		case "DURATION":
+			d, err := parseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: DURATION: %w", n+1, err)
+			}
+			if !ev.Start.IsZero() {
+				ev.End = ev.Start.Add(d)
+			}
+		case "RRULE":
+			rr, err := ParseRRule(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			ev.RRule = rr
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				t, _, err := parseTime(v, params, loc)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: EXDATE: %w", n+1, err)
+				}
+				ev.ExDates = append(ev.ExDates, t)
+			}
+		case "RECURRENCE-ID":
+			t, _, err := parseTime(value, params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: RECURRENCE-ID: %w", n+1, err)
+			}
+			ev.RecurID = t
+		}
+	}
+	return events, nil
+}
+
+// unfold joins continuation lines (RFC 5545 §3.1) and drops blank lines.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// splitProperty splits "NAME;P1=a;P2=b:value" into its parts. Parameter
+// values may be quoted and contain ':' or ';'.
+func splitProperty(line string) (string, map[string]string, string) {
+	inQuote := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuote = !inQuote
+		} else if c == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func unescape(s string) string {
+	r := strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return r.Replace(s)
+}
+
+// parseTime parses DATE or DATE-TIME values. It reports whether the value was
+// a date (all-day).
+func parseTime(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseDuration parses RFC 5545 durations such as "PT15M", "P1DT2H" or "P1W".
+func parseDuration(s string) (time.Duration, error) {
+	orig := s
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	num := 0
+	digits := false
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			num = num*10 + int(c-'0')
+			digits = true
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+		if !digits {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		unit := map[rune]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+		if inTime {
+			unit = map[rune]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+		}
+		u, ok := unit[c]
+		if !ok {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		d += time.Duration(num) * u
+		num, digits = 0, false
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
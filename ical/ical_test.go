@@ -0,0 +1,198 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/ical"
+)
+
+const sample = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+UID:standup
+SUMMARY:Stand\, up
+DTSTART;TZID=America/Chicago:20260105T093000
+DTEND;TZID=America/Chicago:20260105T094500
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR
+EXDATE;TZID=America/Chicago:20260107T093000
+BEGIN:VALARM
+TRIGGER:-PT5M
+SUMMARY:ignored
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+RECURRENCE-ID;TZID=America/Chicago:20260109T093000
+SUMMARY:Late standup
+DTSTART;TZID=America/Chicago:20260109T110000
+DURATION:PT15M
+END:VEVENT
+BEGIN:VEVENT
+UID:holiday
+SUMMARY:Holiday
+DTSTART;VALUE=DATE:20260119
+END:VEVENT
+BEGIN:VEVENT
+UID:long
+SUMMARY:A very long
+  folded title
+DTSTART:20260106T150000Z
+DTEND:20260106T160000Z
+END:VEVENT
+END:VCALENDAR
+`
+
+func chicago(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	return loc
+}
+
+func TestParse(t *testing.T) {
+	loc := chicago(t)
+	events, err := ical.Parse(strings.NewReader(sample), loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("expected 4 events, got %d", len(events))
+	}
+	ev := events[0]
+	if ev.Summary != "Stand, up" {
+		t.Errorf("Summary = %q, want unescaped %q", ev.Summary, "Stand, up")
+	}
+	if want := time.Date(2026, 1, 5, 9, 30, 0, 0, loc); !ev.Start.Equal(want) {
+		t.Errorf("Start = %v, want %v", ev.Start, want)
+	}
+	if ev.End.Sub(ev.Start) != 15*time.Minute {
+		t.Errorf("duration = %v, want 15m", ev.End.Sub(ev.Start))
+	}
+	if ev.RRule == nil || ev.RRule.Freq != ical.Weekly || len(ev.RRule.ByDay) != 3 {
+		t.Errorf("RRule = %+v", ev.RRule)
+	}
+	if events[1].End.Sub(events[1].Start) != 15*time.Minute {
+		t.Error("expected DURATION to set End")
+	}
+	if !events[2].AllDay || events[2].End.Sub(events[2].Start) != 24*time.Hour {
+		t.Errorf("expected all-day event lasting one day, got %+v", events[2])
+	}
+	if events[3].Summary != "A very long folded title" {
+		t.Errorf("unfolded Summary = %q", events[3].Summary)
+	}
+}
+
+func TestParse_MissingDTSTART(t *testing.T) {
+	data := "BEGIN:VEVENT\nUID:x\nSUMMARY:x\nEND:VEVENT\n"
+	if _, err := ical.Parse(strings.NewReader(data), time.UTC); err == nil {
+		t.Error("expected error for VEVENT without DTSTART")
+	}
+}
+
+func TestOccurrences_RecurrenceWithExdateAndOverride(t *testing.T) {
+	loc := chicago(t)
+	events, err := ical.Parse(strings.NewReader(sample), loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, loc)
+	occs := ical.Occurrences(events, from, from.AddDate(0, 0, 7))
+
+	var got []string
+	for _, o := range occs {
+		got = append(got, o.Start.In(loc).Format("Mon 15:04 ")+o.Summary)
+	}
+	want := []string{
+		"Mon 09:30 Stand, up",
+		"Tue 09:00 A very long folded title",
+		// Wednesday excluded by EXDATE.
+		"Fri 11:00 Late standup", // RECURRENCE-ID override
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("occurrences:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestRRule_PreservesWallClockAcrossDST(t *testing.T) {
+	loc := chicago(t)
+	rr, err := ical.ParseRRule("FREQ=DAILY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 7, 9, 0, 0, 0, loc) // DST begins 2026-03-08
+	for _, o := range rr.Expand(start, start, start.AddDate(0, 0, 3)) {
+		if o.Hour() != 9 {
+			t.Errorf("occurrence %v not at 09:00 local", o)
+		}
+	}
+}
+
+func TestRRule_DateUntilInEventZone(t *testing.T) {
+	loc := chicago(t)
+	rr, err := ical.ParseRRule("FREQ=DAILY;UNTIL=20261031")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 20:00 in Chicago is already the next day in UTC.
+	start := time.Date(2026, 10, 29, 20, 0, 0, 0, loc)
+	var got []string
+	for _, o := range rr.Expand(start, start, start.AddDate(0, 0, 7)) {
+		got = append(got, o.Format("2006-01-02"))
+	}
+	if want := "2026-10-29,2026-10-30,2026-10-31"; strings.Join(got, ",") != want {
+		t.Errorf("got %v, want %s", got, want)
+	}
+}
+
+func TestRRule_Expand(t *testing.T) {
+	tests := []struct {
+		rule  string
+		start time.Time
+		want  []string
+	}{
+		{"FREQ=DAILY;INTERVAL=2;COUNT=3", time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC),
+			[]string{"2026-01-01", "2026-01-03", "2026-01-05"}},
+		{"FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20260115T000000Z", time.Date(2026, 1, 6, 8, 0, 0, 0, time.UTC),
+			[]string{"2026-01-06", "2026-01-08", "2026-01-13"}},
+		{"FREQ=MONTHLY;BYDAY=-1FR;COUNT=3", time.Date(2026, 1, 30, 8, 0, 0, 0, time.UTC),
+			[]string{"2026-01-30", "2026-02-27", "2026-03-27"}},
+		{"FREQ=MONTHLY;BYMONTHDAY=31;COUNT=3", time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC),
+			[]string{"2026-01-31", "2026-03-31", "2026-05-31"}},
+		{"FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;COUNT=2", time.Date(2026, 11, 26, 8, 0, 0, 0, time.UTC),
+			[]string{"2026-11-26", "2027-11-25"}},
+		// BYDAY without BYMONTH spans the whole year; 20MO is the 20th Monday.
+		{"FREQ=YEARLY;BYDAY=20MO;COUNT=2", time.Date(2026, 5, 18, 8, 0, 0, 0, time.UTC),
+			[]string{"2026-05-18", "2027-05-17"}},
+		{"FREQ=YEARLY;BYDAY=-1FR;COUNT=2", time.Date(2026, 12, 25, 8, 0, 0, 0, time.UTC),
+			[]string{"2026-12-25", "2027-12-31"}},
+		// A date-only UNTIL includes that day.
+		{"FREQ=DAILY;UNTIL=20260103", time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC),
+			[]string{"2026-01-01", "2026-01-02", "2026-01-03"}},
+	}
+	for _, tt := range tests {
+		rr, err := ical.ParseRRule(tt.rule)
+		if err != nil {
+			t.Errorf("%s: %v", tt.rule, err)
+			continue
+		}
+		var got []string
+		for _, o := range rr.Expand(tt.start, tt.start, tt.start.AddDate(3, 0, 0)) {
+			got = append(got, o.Format("2006-01-02"))
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestParseRRule_Invalid(t *testing.T) {
+	for _, rule := range []string{"", "FREQ=SECONDLY", "FREQ=DAILY;INTERVAL=0", "FREQ=WEEKLY;BYDAY=XX"} {
+		if _, err := ical.ParseRRule(rule); err == nil {
+			t.Errorf("ParseRRule(%q): expected error", rule)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package ical
+
+import (
+	"sort"
+	"time"
+)
+
+// Occurrence is a single instance of an event.
+type Occurrence struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+	AllDay  bool
+}
+
+// Occurrences expands events (including recurrences, EXDATEs and
+// RECURRENCE-ID overrides) into the instances that overlap [from, to),
+// sorted by start time. Cancelled events are omitted.
+func Occurrences(events []Event, from, to time.Time) []Occurrence {
+	// Overridden instances are excluded from their master's expansion.
+	overridden := make(map[string]map[int64]bool)
+	for _, ev := range events {
+		if !ev.RecurID.IsZero() {
+			if overridden[ev.UID] == nil {
+				overridden[ev.UID] = make(map[int64]bool)
+			}
+			overridden[ev.UID][ev.RecurID.Unix()] = true
+		}
+	}
+
+	var out []Occurrence
+	for _, ev := range events {
+		if ev.Canceled {
+			continue
+		}
+		length := ev.End.Sub(ev.Start)
+		starts := []time.Time{ev.Start}
+		if ev.RRule != nil && ev.RecurID.IsZero() {
+			// Start the window early enough to catch occurrences still in progress.
+			starts = ev.RRule.Expand(ev.Start, from.Add(-length), to)
+		}
+
+		excluded := make(map[int64]bool, len(ev.ExDates))
+		for _, ex := range ev.ExDates {
+			excluded[ex.Unix()] = true
+		}
+		for _, start := range starts {
+			if excluded[start.Unix()] {
+				continue
+			}
+			if ev.RecurID.IsZero() && overridden[ev.UID][start.Unix()] {
+				continue
+			}
+			end := start.Add(length)
+			switch {
+			case !start.Before(to):
+				continue
+			case length > 0 && !end.After(from):
+				continue
+			case length == 0 && start.Before(from):
+				continue
+			}
+			out = append(out, Occurrence{Summary: ev.Summary, Start: start, End: end, AllDay: ev.AllDay})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
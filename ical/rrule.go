@@ -0,0 +1,333 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is an RRULE FREQ value.
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry such as "MO", "2TU" or "-1FR". N is zero when
+// no ordinal is given.
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// RRule is the subset of RFC 5545 recurrence rules supported by Expand:
+// FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, COUNT, UNTIL, BYDAY,
+// BYMONTHDAY, BYMONTH and WKST.
+type RRule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	UntilDate  bool // UNTIL was a date: the whole day in dtstart's zone is included
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// ParseRRule parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+func ParseRRule(s string) (*RRule, error) {
+	rr := &RRule{Interval: 1, WeekStart: time.Monday}
+	for _, part := range strings.Split(s, ";") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		var err error
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			rr.Freq = Frequency(strings.ToUpper(v))
+			switch rr.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				return nil, fmt.Errorf("RRULE: unsupported FREQ %q", v)
+			}
+		case "INTERVAL":
+			rr.Interval, err = strconv.Atoi(v)
+			if err == nil && rr.Interval < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "COUNT":
+			rr.Count, err = strconv.Atoi(v)
+		case "UNTIL":
+			rr.Until, rr.UntilDate, err = parseTime(v, nil, time.UTC)
+		case "WKST":
+			day, ok := weekdays[strings.ToUpper(v)]
+			if !ok {
+				err = fmt.Errorf("unknown weekday")
+			}
+			rr.WeekStart = day
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				d = strings.ToUpper(strings.TrimSpace(d))
+				if len(d) < 2 {
+					return nil, fmt.Errorf("RRULE: invalid BYDAY %q", d)
+				}
+				day, ok := weekdays[d[len(d)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("RRULE: invalid BYDAY %q", d)
+				}
+				wn := WeekdayNum{Day: day}
+				if num := d[:len(d)-2]; num != "" {
+					if wn.N, err = strconv.Atoi(num); err != nil {
+						return nil, fmt.Errorf("RRULE: invalid BYDAY %q", d)
+					}
+				}
+				rr.ByDay = append(rr.ByDay, wn)
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(v, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("RRULE: invalid BYMONTHDAY %q", d)
+				}
+				rr.ByMonthDay = append(rr.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, m := range strings.Split(v, ",") {
+				n, err := strconv.Atoi(m)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("RRULE: invalid BYMONTH %q", m)
+				}
+				rr.ByMonth = append(rr.ByMonth, time.Month(n))
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("RRULE: invalid %s %q: %w", k, v, err)
+		}
+	}
+	if rr.Freq == "" {
+		return nil, fmt.Errorf("RRULE: missing FREQ")
+	}
+	return rr, nil
+}
+
+// maxPeriods bounds how many FREQ periods Expand walks, so a rule far in the
+// past with a small interval can't spin indefinitely.
+const maxPeriods = 100000
+
+// Expand returns the start times of occurrences of a rule anchored at dtstart
+// that fall in [from, to). Wall-clock time of day is preserved in dtstart's
+// location across DST changes. dtstart itself is always the first occurrence.
+func (rr *RRule) Expand(dtstart, from, to time.Time) []time.Time {
+	loc := dtstart.Location()
+	until := rr.Until
+	if rr.UntilDate {
+		uy, um, ud := rr.Until.Date()
+		until = time.Date(uy, um, ud+1, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
+	}
+
+	var out []time.Time
+	count := 0
+	emit := func(t time.Time) bool {
+		if t.Before(dtstart) {
+			return true
+		}
+		if !until.IsZero() && t.After(until) {
+			return false
+		}
+		if rr.Count > 0 && count >= rr.Count {
+			return false
+		}
+		count++
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			out = append(out, t)
+		}
+		return true
+	}
+
+	y, m, d := dtstart.Date()
+	hh, mm, ss := dtstart.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hh, mm, ss, dtstart.Nanosecond(), loc)
+	}
+
+	for period := 0; period < maxPeriods; period++ {
+		var candidates []time.Time
+		switch rr.Freq {
+		case Daily:
+			t := at(y, m, d+period*rr.Interval)
+			if rr.matchesMonth(t.Month()) && rr.matchesWeekday(t.Weekday()) && rr.matchesMonthDay(t) {
+				candidates = append(candidates, t)
+			}
+		case Weekly:
+			// Start of the week containing dtstart, per WKST.
+			offset := (int(dtstart.Weekday()) - int(rr.WeekStart) + 7) % 7
+			weekStart := at(y, m, d-offset+period*7*rr.Interval)
+			days := rr.ByDay
+			if len(days) == 0 {
+				days = []WeekdayNum{{Day: dtstart.Weekday()}}
+			}
+			for _, wd := range days {
+				delta := (int(wd.Day) - int(rr.WeekStart) + 7) % 7
+				t := weekStart.AddDate(0, 0, delta)
+				t = at(t.Year(), t.Month(), t.Day())
+				if rr.matchesMonth(t.Month()) {
+					candidates = append(candidates, t)
+				}
+			}
+		case Monthly:
+			first := time.Date(y, m+time.Month(period*rr.Interval), 1, 0, 0, 0, 0, loc)
+			if rr.matchesMonth(first.Month()) {
+				candidates = rr.monthDays(first.Year(), first.Month(), d, at)
+			}
+		case Yearly:
+			year := y + period*rr.Interval
+			if len(rr.ByMonth) == 0 && len(rr.ByMonthDay) == 0 && len(rr.ByDay) > 0 {
+				candidates = rr.yearDays(year, at)
+				break
+			}
+			months := rr.ByMonth
+			if len(months) == 0 {
+				months = []time.Month{m}
+			}
+			for _, mon := range months {
+				candidates = append(candidates, rr.monthDays(year, mon, d, at)...)
+			}
+		}
+
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+		for _, t := range candidates {
+			if !emit(t) {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// monthDays returns the occurrence days in the given month according to
+// BYMONTHDAY and BYDAY, defaulting to dtstart's day of month.
+func (rr *RRule) monthDays(year int, month time.Month, defaultDay int, at func(int, time.Month, int) time.Time) []time.Time {
+	daysIn := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	var out []time.Time
+
+	if len(rr.ByMonthDay) > 0 {
+		for _, md := range rr.ByMonthDay {
+			day := md
+			if md < 0 {
+				day = daysIn + md + 1
+			}
+			if day >= 1 && day <= daysIn {
+				t := at(year, month, day)
+				if rr.matchesWeekday(t.Weekday()) {
+					out = append(out, t)
+				}
+			}
+		}
+		return out
+	}
+
+	if len(rr.ByDay) > 0 {
+		for _, wd := range rr.ByDay {
+			var matches []int
+			for day := 1; day <= daysIn; day++ {
+				if time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() == wd.Day {
+					matches = append(matches, day)
+				}
+			}
+			switch {
+			case wd.N == 0:
+				for _, day := range matches {
+					out = append(out, at(year, month, day))
+				}
+			case wd.N > 0 && wd.N <= len(matches):
+				out = append(out, at(year, month, matches[wd.N-1]))
+			case wd.N < 0 && -wd.N <= len(matches):
+				out = append(out, at(year, month, matches[len(matches)+wd.N]))
+			}
+		}
+		return out
+	}
+
+	if defaultDay <= daysIn {
+		out = append(out, at(year, month, defaultDay))
+	}
+	return out
+}
+
+// yearDays returns the BYDAY occurrences in year for a YEARLY rule without
+// BYMONTH or BYMONTHDAY. An ordinal such as "20MO" counts within the year.
+func (rr *RRule) yearDays(year int, at func(int, time.Month, int) time.Time) []time.Time {
+	daysIn := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	var out []time.Time
+	for _, wd := range rr.ByDay {
+		var matches []int
+		for yd := 1; yd <= daysIn; yd++ {
+			if time.Date(year, 1, yd, 0, 0, 0, 0, time.UTC).Weekday() == wd.Day {
+				matches = append(matches, yd)
+			}
+		}
+		switch {
+		case wd.N == 0:
+			for _, yd := range matches {
+				out = append(out, at(year, time.January, yd))
+			}
+		case wd.N > 0 && wd.N <= len(matches):
+			out = append(out, at(year, time.January, matches[wd.N-1]))
+		case wd.N < 0 && -wd.N <= len(matches):
+			out = append(out, at(year, time.January, matches[len(matches)+wd.N]))
+		}
+	}
+	return out
+}
+
+func (rr *RRule) matchesMonth(m time.Month) bool {
+	if len(rr.ByMonth) == 0 {
+		return true
+	}
+	for _, bm := range rr.ByMonth {
+		if bm == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (rr *RRule) matchesWeekday(d time.Weekday) bool {
+	if len(rr.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range rr.ByDay {
+		if wd.Day == d {
+			return true
+		}
+	}
+	return false
+}
+
+func (rr *RRule) matchesMonthDay(t time.Time) bool {
+	if len(rr.ByMonthDay) == 0 {
+		return true
+	}
+	daysIn := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, md := range rr.ByMonthDay {
+		if md == t.Day() || (md < 0 && daysIn+md+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
@@ -324,6 +324,80 @@ func (s *Server) handleWidgetRemove(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// --- Alert log handlers ---
+
+// alertLogScan is how many of the newest alert log entries are filtered.
+const alertLogScan = 2000
+
+func (s *Server) handleAlertLog(w http.ResponseWriter, r *http.Request) {
+	inst := s.store.Get(r.PathValue("id"))
+	if inst == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	q := r.URL.Query()
+	filter := AlertLogFilter{
+		Event:    q.Get("event"),
+		Instance: q.Get("instance"),
+		Query:    strings.TrimSpace(q.Get("q")),
+	}
+	entries, err := FetchAlertLog(inst.Host, inst.Port, alertLogScan)
+
+	var matched []AlertLogEntry
+	seen := map[string]bool{}
+	var instances []string
+	for _, e := range entries {
+		if e.Instance != "" && !seen[e.Instance] {
+			seen[e.Instance] = true
+			instances = append(instances, e.Instance)
+		}
+		if filter.Match(e) {
+			matched = append(matched, e)
+		}
+	}
+
+	data := map[string]interface{}{
+		"Instance":  inst,
+		"Entries":   matched,
+		"Filter":    filter,
+		"Events":    []string{"created", "displayed", "acked", "deleted", "expired"},
+		"Instances": instances,
+		"Error":     "",
+	}
+	if err != nil {
+		data["Error"] = fmt.Sprintf("Failed to fetch alert log: %v", err)
+	}
+
+	if err := renderPage(w, "templates/alert_log.html", data); err != nil {
+		log.Printf("render alert_log: %v", err)
+	}
+}
+
+// --- Clock handlers ---
+
+func (s *Server) handleClocks(w http.ResponseWriter, r *http.Request) {
+	inst := s.store.Get(r.PathValue("id"))
+	if inst == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	clocks, err := FetchHeartbeats(inst.Host, inst.Port)
+	data := map[string]interface{}{
+		"Instance": inst,
+		"Clocks":   clocks,
+		"Error":    "",
+	}
+	if err != nil {
+		data["Error"] = fmt.Sprintf("Failed to fetch heartbeats: %v", err)
+	}
+
+	if err := renderPage(w, "templates/clocks.html", data); err != nil {
+		log.Printf("render clocks: %v", err)
+	}
+}
+
 // --- Helpers ---
 
 func renderFormError(w http.ResponseWriter, id, name, host string, port int, errMsg string) {
@@ -4,13 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/swilcox/led-kurokku-go/config"
 )
 
-const configKey = "kurokku:config"
+const (
+	configKey          = "kurokku:config"
+	alertLogKey        = "kurokku:alert-log"
+	heartbeatKeyPrefix = "kurokku:heartbeat:"
+)
 
 // dialRedis creates an ad-hoc Redis client for the given host and port.
 func dialRedis(host string, port int) *redis.Client {
@@ -84,3 +91,168 @@ func SaveConfigJSON(host string, port int, jsonStr string) error {
 	defer cancel()
 	return rdb.Set(ctx, configKey, jsonStr, 0).Err()
 }
+
+// AlertLogEntry is one alert lifecycle event from the kurokku:alert-log stream.
+type AlertLogEntry struct {
+	Time     time.Time
+	Event    string // created, displayed, acked, deleted or expired
+	Alert    string
+	Instance string
+	Message  string
+	Severity string
+	Detail   string // e.g. the ack source
+}
+
+// AlertLogFilter selects alert log entries. Empty fields match everything;
+// Query matches the alert ID or message, ignoring case.
+type AlertLogFilter struct {
+	Event    string
+	Instance string
+	Query    string
+}
+
+// Match reports whether e passes the filter.
+func (f AlertLogFilter) Match(e AlertLogEntry) bool {
+	if f.Event != "" && e.Event != f.Event {
+		return false
+	}
+	if f.Instance != "" && e.Instance != f.Instance {
+		return false
+	}
+	if f.Query != "" {
+		q := strings.ToLower(f.Query)
+		return strings.Contains(strings.ToLower(e.Alert), q) ||
+			strings.Contains(strings.ToLower(e.Message), q)
+	}
+	return true
+}
+
+// FetchAlertLog reads the newest count alert log entries, newest first.
+func FetchAlertLog(host string, port int, count int64) ([]AlertLogEntry, error) {
+	rdb := dialRedis(host, port)
+	defer rdb.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msgs, err := rdb.XRevRangeN(ctx, alertLogKey, "+", "-", count).Result()
+	if err != nil {
+		return nil, fmt.Errorf("XREVRANGE %s: %w", alertLogKey, err)
+	}
+	entries := make([]AlertLogEntry, 0, len(msgs))
+	for _, m := range msgs {
+		field := func(name string) string {
+			v, _ := m.Values[name].(string)
+			return v
+		}
+		t, err := time.Parse(time.RFC3339Nano, field("time"))
+		if err != nil {
+			// Fall back to the stream ID's millisecond timestamp.
+			ms, _, _ := strings.Cut(m.ID, "-")
+			n, _ := strconv.ParseInt(ms, 10, 64)
+			t = time.UnixMilli(n)
+		}
+		entries = append(entries, AlertLogEntry{
+			Time:     t.Local(),
+			Event:    field("event"),
+			Alert:    field("alert"),
+			Instance: field("instance"),
+			Message:  field("message"),
+			Severity: field("severity"),
+			Detail:   field("detail"),
+		})
+	}
+	return entries, nil
+}
+
+// Heartbeat is the status a clock last published to its
+// kurokku:heartbeat:<instance> hash.
+type Heartbeat struct {
+	Instance     string
+	Version      string
+	Time         time.Time
+	Started      time.Time
+	Uptime       time.Duration
+	Interval     time.Duration
+	Display      string
+	Widget       string
+	Brightness   string
+	LastError    string
+	LastErrorAt  time.Time
+	RedisLatency string // in ms
+	ConfigHash   string
+	// Set by FetchHeartbeats.
+	Online bool // reported within two intervals
+	Drift  bool // running a config other than the one in Redis
+}
+
+// FetchHeartbeats reads the heartbeats of all clocks using the Redis
+// server, sorted by instance. Clocks that stopped reporting drop out once
+// their heartbeat key expires.
+func FetchHeartbeats(host string, port int) ([]Heartbeat, error) {
+	rdb := dialRedis(host, port)
+	defer rdb.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var keys []string
+	iter := rdb.Scan(ctx, 0, heartbeatKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("SCAN %s*: %w", heartbeatKeyPrefix, err)
+	}
+	sort.Strings(keys)
+
+	// Compare each clock's config against the one stored in Redis.
+	var want string
+	if raw, err := rdb.Get(ctx, configKey).Result(); err == nil {
+		if cfg, err := config.Parse([]byte(raw)); err == nil {
+			want = cfg.Hash()
+		}
+	}
+
+	now := time.Now()
+	heartbeats := make([]Heartbeat, 0, len(keys))
+	for _, key := range keys {
+		f, err := rdb.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, fmt.Errorf("HGETALL %s: %w", key, err)
+		}
+		if len(f) == 0 {
+			continue // expired since the scan
+		}
+		seconds := func(name string) time.Duration {
+			n, _ := strconv.ParseInt(f[name], 10, 64)
+			return time.Duration(n) * time.Second
+		}
+		parseTime := func(name string) time.Time {
+			t, _ := time.Parse(time.RFC3339, f[name])
+			return t.Local()
+		}
+		hb := Heartbeat{
+			Instance:     f["instance"],
+			Version:      f["version"],
+			Time:         parseTime("time"),
+			Started:      parseTime("started"),
+			Uptime:       seconds("uptime_s"),
+			Interval:     seconds("interval_s"),
+			Display:      f["display"],
+			Widget:       f["widget"],
+			Brightness:   f["brightness"],
+			LastError:    f["last_error"],
+			RedisLatency: f["redis_latency_ms"],
+			ConfigHash:   f["config_hash"],
+		}
+		if f["last_error_at"] != "" {
+			hb.LastErrorAt = parseTime("last_error_at")
+		}
+		if hb.Instance == "" {
+			hb.Instance = strings.TrimPrefix(key, heartbeatKeyPrefix)
+		}
+		hb.Online = now.Sub(hb.Time) < 2*hb.Interval
+		hb.Drift = want != "" && hb.ConfigHash != want
+		heartbeats = append(heartbeats, hb)
+	}
+	return heartbeats, nil
+}
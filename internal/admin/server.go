@@ -37,6 +37,9 @@ func (s *Server) routes() {
 	s.mux.HandleFunc("POST /instances/{id}/config/json", s.handleConfigJSONSave)
 	s.mux.HandleFunc("POST /instances/{id}/config/widgets/add", s.handleWidgetAdd)
 	s.mux.HandleFunc("DELETE /instances/{id}/config/widgets/{idx}", s.handleWidgetRemove)
+
+	s.mux.HandleFunc("GET /instances/{id}/alerts/log", s.handleAlertLog)
+	s.mux.HandleFunc("GET /instances/{id}/clocks", s.handleClocks)
 }
 
 // ServeHTTP implements http.Handler.
@@ -60,6 +60,8 @@ func initTemplates() {
 		"templates/config_view.html",
 		"templates/config_edit.html",
 		"templates/config_json.html",
+		"templates/alert_log.html",
+		"templates/clocks.html",
 	}
 
 	// Parse partials once for htmx fragment rendering.
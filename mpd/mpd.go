@@ -0,0 +1,207 @@
+package mpd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+	"time"
+)
+
+// Client talks to an MPD server using its line-based text protocol. Each call
+// opens its own connection, so a Client is safe for concurrent use.
+type Client struct {
+	Addr     string        // host:port, default "localhost:6600"
+	Password string        // sent with the password command when set
+	Timeout  time.Duration // dial and per-command timeout, default 5s
+}
+
+// Song is the subset of currentsong fields used for display.
+type Song struct {
+	Artist string
+	Title  string
+	Name   string // stream name, for radio streams without tags
+	File   string
+}
+
+// Text returns "Artist - Title", falling back to the title, the stream name
+// or the file name when tags are missing.
+func (s Song) Text() string {
+	switch {
+	case s.Artist != "" && s.Title != "":
+		return s.Artist + " - " + s.Title
+	case s.Title != "":
+		return s.Title
+	case s.Name != "":
+		return s.Name
+	case s.File == "":
+		return ""
+	default:
+		return strings.TrimSuffix(path.Base(s.File), path.Ext(s.File))
+	}
+}
+
+// Status is the player state together with the current song.
+type Status struct {
+	State  string // "play", "pause" or "stop"
+	SongID string
+	Song   Song
+}
+
+// Playing reports whether playback is active.
+func (s Status) Playing() bool { return s.State == "play" }
+
+// Error is an ACK response from the server.
+type Error struct {
+	Command string
+	Message string
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("mpd %s: %s", e.Command, e.Message) }
+
+func (c *Client) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return 5 * time.Second
+}
+
+// Status returns the player state and current song.
+func (c *Client) Status(ctx context.Context) (Status, error) {
+	cn, err := c.dial(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	defer cn.Close()
+
+	st, err := cn.command(c.timeout(), "status")
+	if err != nil {
+		return Status{}, err
+	}
+	status := Status{State: st["state"], SongID: st["songid"]}
+	if !status.Playing() && status.State != "pause" {
+		return status, nil
+	}
+	song, err := cn.command(c.timeout(), "currentsong")
+	if err != nil {
+		return Status{}, err
+	}
+	status.Song = Song{Artist: song["Artist"], Title: song["Title"], Name: song["Name"], File: song["file"]}
+	return status, nil
+}
+
+// Idle blocks until one of the given subsystems (all when none are given)
+// changes, returning the names of the changed subsystems. Cancelling ctx
+// aborts the wait.
+func (c *Client) Idle(ctx context.Context, subsystems ...string) ([]string, error) {
+	cn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cn.Close()
+
+	// The connection has no deadline while idling; closing it unblocks the read.
+	stop := context.AfterFunc(ctx, func() { cn.Close() })
+	defer stop()
+
+	cmd := strings.TrimSpace("idle " + strings.Join(subsystems, " "))
+	var changed []string
+	err = cn.exchange(0, cmd, func(key, value string) {
+		if key == "changed" {
+			changed = append(changed, value)
+		}
+	})
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return changed, err
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	addr := c.Addr
+	if addr == "" {
+		addr = "localhost:6600"
+	}
+	d := net.Dialer{Timeout: c.timeout()}
+	nc, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to mpd: %w", err)
+	}
+	cn := &conn{Conn: nc, r: bufio.NewReader(nc)}
+
+	nc.SetDeadline(time.Now().Add(c.timeout()))
+	greeting, err := cn.r.ReadString('\n')
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("reading mpd greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "OK MPD ") {
+		nc.Close()
+		return nil, fmt.Errorf("unexpected mpd greeting %q", strings.TrimSpace(greeting))
+	}
+	if c.Password != "" {
+		if _, err := cn.command(c.timeout(), "password "+quote(c.Password)); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+// command sends cmd and collects the "key: value" response lines.
+func (cn *conn) command(timeout time.Duration, cmd string) (map[string]string, error) {
+	fields := make(map[string]string)
+	err := cn.exchange(timeout, cmd, func(key, value string) {
+		fields[key] = value
+	})
+	return fields, err
+}
+
+// exchange sends cmd and passes each response line to fn until OK or ACK.
+// A zero timeout waits indefinitely.
+func (cn *conn) exchange(timeout time.Duration, cmd string, fn func(key, value string)) error {
+	if timeout > 0 {
+		cn.SetDeadline(time.Now().Add(timeout))
+	} else {
+		cn.SetDeadline(time.Time{})
+	}
+	name, _, _ := strings.Cut(cmd, " ")
+	if _, err := fmt.Fprintf(cn, "%s\n", cmd); err != nil {
+		return fmt.Errorf("mpd %s: %w", name, err)
+	}
+	for {
+		line, err := cn.r.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("mpd %s: %w", name, err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "OK":
+			return nil
+		case strings.HasPrefix(line, "ACK "):
+			// ACK [error@command_listNum] {current_command} message_text
+			msg := line
+			if _, rest, ok := strings.Cut(line, "} "); ok {
+				msg = rest
+			}
+			return &Error{Command: name, Message: msg}
+		}
+		if key, value, ok := strings.Cut(line, ": "); ok {
+			fn(key, value)
+		}
+	}
+}
+
+// quote wraps s in double quotes, escaping as the MPD protocol requires.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,101 @@
+package mpd_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/mpd"
+	"github.com/swilcox/led-kurokku-go/mpd/testutil"
+)
+
+func TestStatus(t *testing.T) {
+	srv := testutil.NewServer(t)
+	c := &mpd.Client{Addr: srv.Addr}
+
+	st, err := c.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Playing() || st.Song.Text() != "" {
+		t.Errorf("stopped status = %+v", st)
+	}
+
+	srv.Play("7", "Boards of Canada", "Roygbiv")
+	st, err = c.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.Playing() || st.SongID != "7" || st.Song.Text() != "Boards of Canada - Roygbiv" {
+		t.Errorf("playing status = %+v", st)
+	}
+}
+
+func TestSong_TextFallbacks(t *testing.T) {
+	tests := []struct {
+		song mpd.Song
+		want string
+	}{
+		{mpd.Song{Title: "Only Title"}, "Only Title"},
+		{mpd.Song{Name: "Radio Paradise"}, "Radio Paradise"},
+		{mpd.Song{File: "albums/x/01 Intro.mp3"}, "01 Intro"},
+	}
+	for _, tt := range tests {
+		if got := tt.song.Text(); got != tt.want {
+			t.Errorf("%+v.Text() = %q, want %q", tt.song, got, tt.want)
+		}
+	}
+}
+
+func TestPassword(t *testing.T) {
+	srv := testutil.NewServer(t)
+	srv.SetPassword("hunter2")
+
+	if _, err := (&mpd.Client{Addr: srv.Addr}).Status(context.Background()); err == nil {
+		t.Error("expected permission error without password")
+	}
+	var ackErr *mpd.Error
+	_, err := (&mpd.Client{Addr: srv.Addr, Password: "wrong"}).Status(context.Background())
+	if !errors.As(err, &ackErr) || ackErr.Command != "password" {
+		t.Errorf("expected password ACK, got %v", err)
+	}
+	if _, err := (&mpd.Client{Addr: srv.Addr, Password: "hunter2"}).Status(context.Background()); err != nil {
+		t.Errorf("with password: %v", err)
+	}
+}
+
+func TestIdle_ReturnsOnChange(t *testing.T) {
+	srv := testutil.NewServer(t)
+	c := &mpd.Client{Addr: srv.Addr}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		srv.Play("1", "A", "B")
+	}()
+	changed, err := c.Idle(context.Background(), "player")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 1 || changed[0] != "player" {
+		t.Errorf("changed = %v, want [player]", changed)
+	}
+}
+
+func TestIdle_Cancelled(t *testing.T) {
+	srv := testutil.NewServer(t)
+	c := &mpd.Client{Addr: srv.Addr}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Idle(ctx, "player"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context error, got %v", err)
+	}
+}
+
+func TestDial_Unreachable(t *testing.T) {
+	c := &mpd.Client{Addr: "127.0.0.1:1", Timeout: time.Second}
+	if _, err := c.Status(context.Background()); err == nil {
+		t.Error("expected connection error")
+	}
+}
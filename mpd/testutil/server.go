@@ -0,0 +1,157 @@
+package testutil
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Server is a minimal fake MPD server supporting status, currentsong,
+// idle/noidle and password.
+type Server struct {
+	Addr string
+
+	mu       sync.Mutex
+	password string
+	state    string
+	songID   string
+	artist   string
+	title    string
+	changed  chan struct{} // closed and replaced on every player change
+	conns    map[net.Conn]bool
+}
+
+// NewServer starts a fake MPD server on a loopback port. It is stopped when
+// the test finishes.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{Addr: ln.Addr().String(), state: "stop", changed: make(chan struct{}), conns: make(map[net.Conn]bool)}
+	var wg sync.WaitGroup
+	t.Cleanup(func() {
+		ln.Close()
+		s.mu.Lock()
+		for c := range s.conns {
+			c.Close()
+		}
+		s.mu.Unlock()
+		wg.Wait()
+	})
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns[c] = true
+			s.mu.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.serve(c)
+			}()
+		}
+	}()
+	return s
+}
+
+// SetPassword makes the server require the password command.
+func (s *Server) SetPassword(p string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.password = p
+}
+
+// Play sets the player to play the given song and wakes idling clients.
+func (s *Server) Play(id, artist, title string) {
+	s.set("play", id, artist, title)
+}
+
+// Stop stops playback and wakes idling clients.
+func (s *Server) Stop() {
+	s.set("stop", "", "", "")
+}
+
+func (s *Server) set(state, id, artist, title string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state, s.songID, s.artist, s.title = state, id, artist, title
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *Server) serve(c net.Conn) {
+	defer func() {
+		c.Close()
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+	}()
+	fmt.Fprint(c, "OK MPD 0.23.5\n")
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(c)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+
+	s.mu.Lock()
+	password := s.password
+	s.mu.Unlock()
+	authed := password == ""
+	for line := range lines {
+		cmd, arg, _ := strings.Cut(line, " ")
+		if !authed && cmd != "password" {
+			fmt.Fprintf(c, "ACK [4@0] {%s} you don't have permission for \"%s\"\n", cmd, cmd)
+			continue
+		}
+		s.mu.Lock()
+		state, id, artist, title, changed := s.state, s.songID, s.artist, s.title, s.changed
+		s.mu.Unlock()
+
+		switch cmd {
+		case "password":
+			if strings.Trim(arg, `"`) != password {
+				fmt.Fprint(c, "ACK [3@0] {password} incorrect password\n")
+				continue
+			}
+			authed = true
+			fmt.Fprint(c, "OK\n")
+		case "status":
+			fmt.Fprintf(c, "volume: 50\nstate: %s\n", state)
+			if id != "" {
+				fmt.Fprintf(c, "songid: %s\n", id)
+			}
+			fmt.Fprint(c, "OK\n")
+		case "currentsong":
+			if id != "" {
+				fmt.Fprintf(c, "file: music/%s.flac\nArtist: %s\nTitle: %s\nId: %s\n", id, artist, title, id)
+			}
+			fmt.Fprint(c, "OK\n")
+		case "idle":
+			select {
+			case <-changed:
+				fmt.Fprint(c, "changed: player\nOK\n")
+			case next, ok := <-lines:
+				if !ok {
+					return
+				}
+				if next == "noidle" {
+					fmt.Fprint(c, "OK\n")
+				}
+			}
+		default:
+			fmt.Fprintf(c, "ACK [5@0] {%s} unknown command \"%s\"\n", cmd, cmd)
+		}
+	}
+}
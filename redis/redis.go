@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/swilcox/led-kurokku-go/config"
@@ -14,14 +17,74 @@ import (
 const (
 	alertKeyPrefix       = "kurokku:alert:"
 	alertKeyspacePattern = "__keyspace@0__:" + alertKeyPrefix + "*"
+	// alertExpiryKeyPrefix + ID holds a copy of an alert written with an
+	// expires_at. The clock whose GETDEL removes it when the alert expires
+	// logs the expiry, so it is logged once however many clocks watch.
+	alertExpiryKeyPrefix = "kurokku:alert-expiry:"
+	alertExpiryGrace     = time.Hour
+	// Setting kurokku:ack:<id> (any value) acknowledges alert <id>.
+	ackKeyPrefix = "kurokku:ack:"
+	// AlertLogKey is the stream recording alert lifecycle events, capped
+	// at about alertLogMaxLen entries.
+	AlertLogKey    = "kurokku:alert-log"
+	alertLogMaxLen = 10000
 
 	configKey             = "kurokku:config"
 	configKeyspacePattern = "__keyspace@0__:" + configKey
+
+	// HeartbeatKeyPrefix + instance ID holds each clock's heartbeat hash.
+	HeartbeatKeyPrefix = "kurokku:heartbeat:"
+
+	// feedSeenKeyPrefix + "<instance>:<widget>" holds the headlines a feed
+	// widget has shown on one clock.
+	feedSeenKeyPrefix = "kurokku:feed:seen:"
+	// feedSeenMax caps each seen-headline set; the oldest entries are dropped.
+	feedSeenMax = 5000
+	feedSeenTTL = 30 * 24 * time.Hour
+)
+
+// Alert lifecycle events recorded in the alert log.
+const (
+	AlertCreated   = "created"
+	AlertDisplayed = "displayed"
+	AlertAcked     = "acked"
+	AlertDeleted   = "deleted"
+	AlertExpired   = "expired"
 )
 
 // Client wraps a Redis connection for kurokku operations.
 type Client struct {
-	rdb *redis.Client
+	rdb      *redis.Client
+	instance string // recorded in the alert log
+}
+
+// SetInstance sets the instance ID recorded with alert log events.
+func (c *Client) SetInstance(id string) {
+	c.instance = id
+}
+
+// LogAlert appends an event for alert a to the alert log. detail adds
+// context such as the ack source. Failures are only logged so the history
+// never gets in the way of showing alerts.
+func (c *Client) LogAlert(ctx context.Context, event string, a config.AlertConfig, detail string) {
+	err := c.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: AlertLogKey,
+		MaxLen: alertLogMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"time":     time.Now().UTC().Format(time.RFC3339Nano),
+			"event":    event,
+			"alert":    a.ID,
+			"instance": c.instance,
+			"message":  a.Message,
+			"priority": a.Priority,
+			"severity": a.Severity,
+			"detail":   detail,
+		},
+	}).Err()
+	if err != nil {
+		log.Printf("alert log %s %s: %v", event, a.ID, err)
+	}
 }
 
 // NewFromEnv creates a Redis client from environment variables.
@@ -61,10 +124,27 @@ func (c *Client) Close() error {
 	return c.rdb.Close()
 }
 
+// AlertIDs returns the IDs of every alert key, including the alerts outside
+// their time window that FetchAlerts leaves out.
+func (c *Client) AlertIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	iter := c.rdb.Scan(ctx, 0, alertKeyPrefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		ids = append(ids, strings.TrimPrefix(iter.Val(), alertKeyPrefix))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("SCAN %s*: %w", alertKeyPrefix, err)
+	}
+	return ids, nil
+}
+
 // FetchAlerts scans for all keys matching kurokku:alert:* and returns their values.
 // Each key stores a JSON AlertConfig. The alert ID is derived from the key suffix.
+// Alerts outside their starts_at/expires_at/snooze_until window are left out,
+// and expired ones are deleted.
 func (c *Client) FetchAlerts(ctx context.Context) ([]config.AlertConfig, error) {
 	var alerts []config.AlertConfig
+	now := time.Now()
 	iter := c.rdb.Scan(ctx, 0, alertKeyPrefix+"*", 0).Iterator()
 	for iter.Next(ctx) {
 		key := iter.Val()
@@ -83,6 +163,20 @@ func (c *Client) FetchAlerts(ctx context.Context) ([]config.AlertConfig, error)
 		if ac.ID == "" {
 			ac.ID = key[len(alertKeyPrefix):]
 		}
+		if ac.Expired(now) {
+			n, err := c.rdb.Del(ctx, key).Result()
+			if err != nil {
+				return nil, fmt.Errorf("DEL %s: %w", key, err)
+			}
+			if n == 1 {
+				c.rdb.Del(ctx, alertExpiryKeyPrefix+ac.ID) //nolint:errcheck // expires by itself
+				c.LogAlert(ctx, AlertExpired, ac, "")
+			}
+			continue
+		}
+		if !ac.Live(now) {
+			continue
+		}
 		alerts = append(alerts, ac)
 	}
 	if err := iter.Err(); err != nil {
@@ -91,9 +185,154 @@ func (c *Client) FetchAlerts(ctx context.Context) ([]config.AlertConfig, error)
 	return alerts, nil
 }
 
-// DeleteAlert removes an alert by deleting its key (kurokku:alert:<id>).
+// DeleteAlert removes an alert by deleting its key (kurokku:alert:<id>),
+// recording the deletion in the alert log.
 func (c *Client) DeleteAlert(ctx context.Context, id string) error {
-	return c.rdb.Del(ctx, alertKeyPrefix+id).Err()
+	raw, err := c.rdb.GetDel(ctx, alertKeyPrefix+id).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.rdb.Del(ctx, alertExpiryKeyPrefix+id) //nolint:errcheck // expires by itself
+	var ac config.AlertConfig
+	json.Unmarshal([]byte(raw), &ac) //nolint:errcheck // the ID alone is logged
+	ac.ID = id
+	c.LogAlert(ctx, AlertDeleted, ac, "")
+	return nil
+}
+
+// SetAlert writes a to kurokku:alert:<id>, expiring the key at expires_at
+// when set. The acknowledgement and snooze of an existing alert with the
+// same ID are kept, so re-sent notifications do not bring it back. A new
+// alert is recorded in the alert log.
+func (c *Client) SetAlert(ctx context.Context, a config.AlertConfig) error {
+	key := alertKeyPrefix + a.ID
+	expiryKey := alertExpiryKeyPrefix + a.ID
+	created := false
+	err := c.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		raw, err := tx.Get(ctx, key).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		created = err == redis.Nil
+		if err == nil {
+			var prev config.AlertConfig
+			if err := json.Unmarshal([]byte(raw), &prev); err == nil {
+				a = a.KeepState(prev)
+			}
+		}
+		b, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		var args redis.SetArgs
+		if a.ExpiresAt != nil {
+			args.ExpireAt = *a.ExpiresAt
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SetArgs(ctx, key, b, args)
+			if a.ExpiresAt != nil {
+				pipe.SetArgs(ctx, expiryKey, b, redis.SetArgs{ExpireAt: a.ExpiresAt.Add(alertExpiryGrace)})
+			} else {
+				pipe.Del(ctx, expiryKey)
+			}
+			return nil
+		})
+		return err
+	}, key)
+	if err != nil {
+		return fmt.Errorf("set %s: %w", key, err)
+	}
+	if created {
+		c.LogAlert(ctx, AlertCreated, a, "")
+	}
+	return nil
+}
+
+// LogAlertExpired records the expiry of alert id, reported by a keyspace
+// event once Redis has removed the key at its expires_at. Every clock gets
+// the event; only the one whose GETDEL takes the alert's expiry copy logs
+// it. Alerts written without SetAlert have no copy and are not logged.
+func (c *Client) LogAlertExpired(ctx context.Context, id string) {
+	raw, err := c.rdb.GetDel(ctx, alertExpiryKeyPrefix+id).Result()
+	if err == redis.Nil {
+		return
+	}
+	if err != nil {
+		log.Printf("alert log %s %s: %v", AlertExpired, id, err)
+		return
+	}
+	var ac config.AlertConfig
+	json.Unmarshal([]byte(raw), &ac) //nolint:errcheck // the ID alone is logged
+	ac.ID = id
+	c.LogAlert(ctx, AlertExpired, ac, "")
+}
+
+// AckAlert records an acknowledgement in the alert's JSON record as acked_at
+// and acked_by, keeping the key's TTL and any fields it does not know about.
+// It reports false if the alert does not exist.
+func (c *Client) AckAlert(ctx context.Context, id, source string, at time.Time) (bool, error) {
+	key := alertKeyPrefix + id
+	found := false
+	var logged config.AlertConfig
+	err := c.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		raw, err := tx.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var rec map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+			return fmt.Errorf("unmarshal alert %s: %w", key, err)
+		}
+		json.Unmarshal([]byte(raw), &logged) //nolint:errcheck // already parsed above
+		logged.ID = id
+		rec["acked_at"], _ = json.Marshal(at.UTC())
+		rec["acked_by"], _ = json.Marshal(source)
+		b, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		found = true
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SetArgs(ctx, key, b, redis.SetArgs{KeepTTL: true})
+			return nil
+		})
+		return err
+	}, key)
+	if err != nil {
+		return false, fmt.Errorf("ack %s: %w", key, err)
+	}
+	if found {
+		c.LogAlert(ctx, AlertAcked, logged, source)
+	}
+	return found, nil
+}
+
+// ConsumeAcks returns the alert IDs acknowledged through kurokku:ack:<id>
+// keys, deleting the keys so each ack is handled once.
+func (c *Client) ConsumeAcks(ctx context.Context) ([]string, error) {
+	var ids []string
+	iter := c.rdb.Scan(ctx, 0, ackKeyPrefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		// Only the client whose DEL removed the key handles the ack.
+		n, err := c.rdb.Del(ctx, key).Result()
+		if err != nil {
+			return ids, fmt.Errorf("DEL %s: %w", key, err)
+		}
+		if n == 1 {
+			ids = append(ids, key[len(ackKeyPrefix):])
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return ids, fmt.Errorf("SCAN %s*: %w", ackKeyPrefix, err)
+	}
+	return ids, nil
 }
 
 // FetchMessageText returns the value of the given Redis key.
@@ -109,6 +348,195 @@ func (c *Client) FetchMessageText(ctx context.Context, key string) (string, bool
 	return val, true, nil
 }
 
+// SetMessageText stores value at key, expiring it after ttl when ttl > 0.
+func (c *Client) SetMessageText(ctx context.Context, key, value string, ttl time.Duration) error {
+	if err := c.rdb.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("SET %s: %w", key, err)
+	}
+	return nil
+}
+
+// ReservedKey reports whether key holds kurokku's own state (config, alerts,
+// acks, alert log, heartbeats, seen headlines) and must not be written as
+// message text.
+func ReservedKey(key string) bool {
+	return key == configKey || key == AlertLogKey ||
+		strings.HasPrefix(key, alertKeyPrefix) || strings.HasPrefix(key, ackKeyPrefix) ||
+		strings.HasPrefix(key, HeartbeatKeyPrefix) || strings.HasPrefix(key, feedSeenKeyPrefix) ||
+		strings.HasPrefix(key, alertExpiryKeyPrefix)
+}
+
+// Heartbeat is a clock's periodic status report.
+type Heartbeat struct {
+	Instance     string
+	Version      string
+	Started      time.Time
+	Interval     time.Duration // time until the next heartbeat
+	Display      string
+	Widget       string
+	Brightness   byte
+	LastError    string
+	LastErrorAt  time.Time // zero when there was none
+	RedisLatency time.Duration
+	ConfigHash   string
+}
+
+// PublishHeartbeat writes hb to HeartbeatKeyPrefix + hb.Instance, expiring
+// it after ttl so the key disappears once the clock stops reporting.
+func (c *Client) PublishHeartbeat(ctx context.Context, hb Heartbeat, ttl time.Duration) error {
+	key := HeartbeatKeyPrefix + hb.Instance
+	now := time.Now()
+	var lastErrorAt string
+	if !hb.LastErrorAt.IsZero() {
+		lastErrorAt = hb.LastErrorAt.UTC().Format(time.RFC3339)
+	}
+	pipe := c.rdb.TxPipeline()
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"instance":         hb.Instance,
+		"version":          hb.Version,
+		"time":             now.UTC().Format(time.RFC3339),
+		"started":          hb.Started.UTC().Format(time.RFC3339),
+		"uptime_s":         int64(now.Sub(hb.Started).Seconds()),
+		"interval_s":       int64(hb.Interval.Seconds()),
+		"display":          hb.Display,
+		"widget":           hb.Widget,
+		"brightness":       hb.Brightness,
+		"last_error":       hb.LastError,
+		"last_error_at":    lastErrorAt,
+		"redis_latency_ms": float64(hb.RedisLatency.Microseconds()) / 1000,
+		"config_hash":      hb.ConfigHash,
+	})
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("HSET %s: %w", key, err)
+	}
+	return nil
+}
+
+// FetchHashField returns the value of field in the hash stored at key.
+// Returns ("", false, nil) if the key or field does not exist.
+func (c *Client) FetchHashField(ctx context.Context, key, field string) (string, bool, error) {
+	val, err := c.rdb.HGet(ctx, key, field).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("HGET %s %s: %w", key, field, err)
+	}
+	return val, true, nil
+}
+
+// Headlines is the sorted set of headlines one feed widget has shown on one
+// clock.
+type Headlines struct {
+	c   *Client
+	key string
+}
+
+// Headlines returns the seen-headline set of the feed widget with the given
+// ID on this instance, so clocks and feeds sharing Redis each keep their own.
+func (c *Client) Headlines(widget string) *Headlines {
+	return &Headlines{c: c, key: feedSeenKeyPrefix + c.instance + ":" + widget}
+}
+
+// SeenHeadlines reports which of the given headline keys are recorded in
+// the set.
+func (h *Headlines) SeenHeadlines(ctx context.Context, keys []string) (map[string]bool, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	scores, err := h.c.rdb.ZMScore(ctx, h.key, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("ZMSCORE %s: %w", h.key, err)
+	}
+	seen := make(map[string]bool, len(keys))
+	for i, score := range scores {
+		// Members are scored with the time they were marked, so 0 means absent.
+		if score != 0 {
+			seen[keys[i]] = true
+		}
+	}
+	return seen, nil
+}
+
+// MarkHeadlinesSeen records headline keys in the set, trimming it to the
+// newest entries and refreshing its TTL.
+func (h *Headlines) MarkHeadlinesSeen(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	now := float64(time.Now().Unix())
+	members := make([]redis.Z, len(keys))
+	for i, k := range keys {
+		members[i] = redis.Z{Score: now, Member: k}
+	}
+	pipe := h.c.rdb.TxPipeline()
+	pipe.ZAdd(ctx, h.key, members...)
+	pipe.ZRemRangeByRank(ctx, h.key, 0, -feedSeenMax-1)
+	pipe.Expire(ctx, h.key, feedSeenTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("ZADD %s: %w", h.key, err)
+	}
+	return nil
+}
+
+// FetchSeries returns the newest n numeric samples stored at key, oldest
+// first. Lists are read from their tail (RPUSH order), sorted sets by score
+// with the value taken from the member ("value" or "<timestamp>:<value>"),
+// and streams from each entry's "value" field (or its only field).
+// Entries that are not numbers are skipped.
+func (c *Client) FetchSeries(ctx context.Context, key string, n int) ([]float64, error) {
+	typ, err := c.rdb.Type(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("TYPE %s: %w", key, err)
+	}
+	var raw []string
+	switch typ {
+	case "none":
+		return nil, nil
+	case "list":
+		raw, err = c.rdb.LRange(ctx, key, int64(-n), -1).Result()
+	case "zset":
+		raw, err = c.rdb.ZRange(ctx, key, int64(-n), -1).Result()
+		for i, m := range raw {
+			if j := strings.LastIndexByte(m, ':'); j >= 0 {
+				raw[i] = m[j+1:]
+			}
+		}
+	case "stream":
+		var msgs []redis.XMessage
+		msgs, err = c.rdb.XRevRangeN(ctx, key, "+", "-", int64(n)).Result()
+		for i := len(msgs) - 1; i >= 0; i-- {
+			raw = append(raw, streamValue(msgs[i].Values))
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported type %s for series", key, typ)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading series %s: %w", key, err)
+	}
+
+	series := make([]float64, 0, len(raw))
+	for _, s := range raw {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			series = append(series, v)
+		}
+	}
+	return series, nil
+}
+
+func streamValue(fields map[string]interface{}) string {
+	if v, ok := fields["value"]; ok {
+		return fmt.Sprint(v)
+	}
+	if len(fields) == 1 {
+		for _, v := range fields {
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
 // FetchConfig fetches the full config JSON stored at kurokku:config.
 // Returns (nil, false, nil) when the key is absent.
 func (c *Client) FetchConfig(ctx context.Context) (*config.Config, bool, error) {
@@ -162,11 +590,18 @@ func (c *Client) SubscribeConfig(ctx context.Context) (<-chan struct{}, error) {
 	return ch, nil
 }
 
+// AlertEvent reports a change to one alert key.
+type AlertEvent struct {
+	ID      string // key suffix after kurokku:alert:
+	Removed bool   // deleted or expired; otherwise the key was set
+	Expired bool   // removed by its TTL or eviction rather than deleted
+}
+
 // SubscribeAlerts enables Redis keyspace notifications and subscribes to
 // key changes on kurokku:alert:* via pattern __keyspace@0__:kurokku:alert:*.
-// Returns a buffered(1) signal channel that receives a value whenever an
-// alert key is set, deleted, or expires.
-func (c *Client) SubscribeAlerts(ctx context.Context) (<-chan struct{}, error) {
+// Returns a buffered channel that receives an event whenever an alert key
+// is set, deleted, or expires; events are dropped while the buffer is full.
+func (c *Client) SubscribeAlerts(ctx context.Context) (<-chan AlertEvent, error) {
 	// Enable keyspace notifications (KEA = Keyspace + Keyevent + All standard events).
 	if err := c.rdb.ConfigSet(ctx, "notify-keyspace-events", "KEA").Err(); err != nil {
 		log.Printf("warning: could not set notify-keyspace-events: %v", err)
@@ -178,7 +613,8 @@ func (c *Client) SubscribeAlerts(ctx context.Context) (<-chan struct{}, error) {
 		return nil, fmt.Errorf("psubscribe %s: %w", alertKeyspacePattern, err)
 	}
 
-	ch := make(chan struct{}, 1)
+	ch := make(chan AlertEvent, 16)
+	prefix := strings.TrimSuffix(alertKeyspacePattern, "*")
 	go func() {
 		defer sub.Close()
 		msgCh := sub.Channel()
@@ -186,13 +622,25 @@ func (c *Client) SubscribeAlerts(ctx context.Context) (<-chan struct{}, error) {
 			select {
 			case <-ctx.Done():
 				return
-			case _, ok := <-msgCh:
+			case msg, ok := <-msgCh:
 				if !ok {
 					return
 				}
-				// Non-blocking send; drop if already signaled.
+				// The payload is the command or event, e.g. set, del, expired.
+				var ev AlertEvent
+				switch msg.Payload {
+				case "set":
+				case "del":
+					ev.Removed = true
+				case "expired", "evicted":
+					ev.Removed, ev.Expired = true, true
+				default:
+					continue
+				}
+				ev.ID = strings.TrimPrefix(msg.Channel, prefix)
+				// Non-blocking send; drop if the engine is behind.
 				select {
-				case ch <- struct{}{}:
+				case ch <- ev:
 				default:
 				}
 			}
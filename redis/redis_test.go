@@ -0,0 +1,34 @@
+package redis
+
+import "testing"
+
+func TestHeadlines_KeyedPerInstanceAndWidget(t *testing.T) {
+	a := &Client{instance: "kitchen"}
+	b := &Client{instance: "office"}
+	keys := map[string]bool{
+		a.Headlines("news").key:  true,
+		a.Headlines("feed3").key: true,
+		b.Headlines("news").key:  true,
+	}
+	if len(keys) != 3 {
+		t.Errorf("seen-headline keys collide: %v", keys)
+	}
+	if got, want := a.Headlines("news").key, "kurokku:feed:seen:kitchen:news"; got != want {
+		t.Errorf("key = %q, want %q", got, want)
+	}
+}
+
+func TestReservedKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"kurokku:config":                 true,
+		"kurokku:alert:a":                true,
+		"kurokku:feed:seen:kitchen:news": true,
+		"kurokku:heartbeat:kitchen":      true,
+		"weather:temp":                   false,
+		"kurokku:message:status":         false,
+	} {
+		if got := ReservedKey(key); got != want {
+			t.Errorf("ReservedKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
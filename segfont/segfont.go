@@ -223,6 +223,12 @@ func Enc14(r rune) uint16 {
 	return Seg14[r]
 }
 
+// Is14 reports whether enc is a 14-segment font, i.e. one that lights
+// segments beyond the seven of a standard digit.
+func Is14(enc Encoder) bool {
+	return enc != nil && enc('8')&^0x7F != 0
+}
+
 // EncodeText encodes a string using the given encoder.
 func EncodeText(enc Encoder, text string) []uint16 {
 	result := make([]uint16, 0, len(text))
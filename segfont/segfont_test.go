@@ -199,3 +199,15 @@ func TestEncodeText_Empty(t *testing.T) {
 		t.Errorf("EncodeText(Enc7, \"\") length = %d, want 0", len(result))
 	}
 }
+
+func TestIs14(t *testing.T) {
+	if Is14(Enc7) {
+		t.Error("Is14(Enc7) = true")
+	}
+	if !Is14(Enc14) {
+		t.Error("Is14(Enc14) = false")
+	}
+	if Is14(nil) {
+		t.Error("Is14(nil) = true")
+	}
+}
@@ -0,0 +1,130 @@
+package sysinfo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interface is a network interface and its unicast addresses.
+type Interface struct {
+	Name  string
+	Addrs []net.IP
+}
+
+// Interfaces returns the up, non-loopback interfaces that have at least one
+// address. When names is non-empty only those interfaces are returned, in
+// that order. IPv6 addresses are included only when ipv6 is true; link-local
+// addresses are always skipped.
+func Interfaces(names []string, ipv6 bool) ([]Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("listing interfaces: %w", err)
+	}
+
+	byName := make(map[string]net.Interface, len(ifaces))
+	var order []string
+	for _, ifc := range ifaces {
+		byName[ifc.Name] = ifc
+		if len(names) == 0 && ifc.Flags&net.FlagUp != 0 && ifc.Flags&net.FlagLoopback == 0 {
+			order = append(order, ifc.Name)
+		}
+	}
+	if len(names) > 0 {
+		order = names
+	}
+
+	var out []Interface
+	for _, name := range order {
+		ifc, ok := byName[name]
+		if !ok {
+			continue
+		}
+		addrs, err := ifc.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("addresses for %s: %w", name, err)
+		}
+		var ips []net.IP
+		for _, a := range addrs {
+			ipn, ok := a.(*net.IPNet)
+			if !ok || ipn.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			if ipn.IP.To4() == nil && !ipv6 {
+				continue
+			}
+			ips = append(ips, ipn.IP)
+		}
+		if len(ips) > 0 {
+			out = append(out, Interface{Name: name, Addrs: ips})
+		}
+	}
+	return out, nil
+}
+
+// Wireless is a row of /proc/net/wireless.
+type Wireless struct {
+	Interface string
+	Quality   float64 // link quality
+	Signal    float64 // signal level, dBm on most drivers
+	SSID      string  // filled in by SSID when available
+}
+
+// Wireless reads link quality and signal level for each wireless interface.
+// A missing /proc/net/wireless (no Wi-Fi hardware) returns no rows and no error.
+func (r Reader) Wireless() ([]Wireless, error) {
+	path := r.proc("net", "wireless")
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var out []Wireless
+	scanner := bufio.NewScanner(f)
+	for line := 0; scanner.Scan(); line++ {
+		if line < 2 {
+			continue // two header lines
+		}
+		name, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) < 3 {
+			continue
+		}
+		quality, err1 := strconv.ParseFloat(strings.TrimSuffix(fields[1], "."), 64)
+		signal, err2 := strconv.ParseFloat(strings.TrimSuffix(fields[2], "."), 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("parsing %s: bad row %q", path, scanner.Text())
+		}
+		out = append(out, Wireless{Interface: strings.TrimSpace(name), Quality: quality, Signal: signal})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return out, nil
+}
+
+// SSID returns the network name the interface is associated with, using
+// iwgetid(8) since /proc/net/wireless does not carry it. Returns "" when the
+// tool is unavailable or the interface is not associated.
+func SSID(ctx context.Context, iface string) string {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "iwgetid", "-r", iface).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,14 @@
+//go:build linux || darwin
+
+package sysinfo
+
+import "syscall"
+
+func statfs(path string) (Disk, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Disk{}, err
+	}
+	bsize := uint64(st.Bsize)
+	return Disk{Total: uint64(st.Blocks) * bsize, Free: uint64(st.Bavail) * bsize}, nil
+}
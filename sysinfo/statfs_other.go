@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package sysinfo
+
+import "errors"
+
+func statfs(path string) (Disk, error) {
+	return Disk{}, errors.New("statfs not supported on this platform")
+}
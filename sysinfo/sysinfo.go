@@ -0,0 +1,168 @@
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reader reads host statistics from the Linux /proc and /sys filesystems.
+// The roots are configurable so tests can point at fixture directories.
+type Reader struct {
+	ProcRoot string // default "/proc"
+	SysRoot  string // default "/sys"
+	// ThermalZone selects /sys/class/thermal/<zone>/temp. Default "thermal_zone0".
+	ThermalZone string
+}
+
+func (r Reader) proc(elem ...string) string {
+	root := r.ProcRoot
+	if root == "" {
+		root = "/proc"
+	}
+	return filepath.Join(append([]string{root}, elem...)...)
+}
+
+func (r Reader) sys(elem ...string) string {
+	root := r.SysRoot
+	if root == "" {
+		root = "/sys"
+	}
+	return filepath.Join(append([]string{root}, elem...)...)
+}
+
+// CPUTemp returns the CPU temperature in degrees Celsius.
+func (r Reader) CPUTemp() (float64, error) {
+	zone := r.ThermalZone
+	if zone == "" {
+		zone = "thermal_zone0"
+	}
+	path := r.sys("class", "thermal", zone, "temp")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading %s: %w", path, err)
+	}
+	milli, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return milli / 1000, nil
+}
+
+// LoadAvg returns the 1, 5 and 15 minute load averages.
+func (r Reader) LoadAvg() ([3]float64, error) {
+	var load [3]float64
+	path := r.proc("loadavg")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return load, fmt.Errorf("reading %s: %w", path, err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return load, fmt.Errorf("parsing %s: expected 3 fields, got %d", path, len(fields))
+	}
+	for i := range load {
+		if load[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return load, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+	return load, nil
+}
+
+// Memory holds memory totals in bytes.
+type Memory struct {
+	Total     uint64
+	Available uint64
+}
+
+// UsedPercent returns the percentage of memory in use.
+func (m Memory) UsedPercent() float64 {
+	if m.Total == 0 {
+		return 0
+	}
+	return 100 * float64(m.Total-m.Available) / float64(m.Total)
+}
+
+// Memory reads MemTotal and MemAvailable from /proc/meminfo.
+func (r Reader) Memory() (Memory, error) {
+	var m Memory
+	path := r.proc("meminfo")
+	f, err := os.Open(path)
+	if err != nil {
+		return m, fmt.Errorf("reading %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			m.Total = kb * 1024
+		case "MemAvailable:":
+			m.Available = kb * 1024
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return m, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if m.Total == 0 {
+		return m, fmt.Errorf("parsing %s: MemTotal not found", path)
+	}
+	return m, nil
+}
+
+// Uptime returns the system uptime from /proc/uptime.
+func (r Reader) Uptime() (time.Duration, error) {
+	path := r.proc("uptime")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading %s: %w", path, err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("parsing %s: empty", path)
+	}
+	secs, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return time.Duration(secs * float64(time.Second)), nil
+}
+
+// Disk holds filesystem totals in bytes.
+type Disk struct {
+	Total uint64
+	Free  uint64 // available to unprivileged users
+}
+
+// UsedPercent returns the percentage of the filesystem in use.
+func (d Disk) UsedPercent() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+	return 100 * float64(d.Total-d.Free) / float64(d.Total)
+}
+
+// Disk returns usage for the filesystem containing path (statfs).
+func (r Reader) Disk(path string) (Disk, error) {
+	if path == "" {
+		path = "/"
+	}
+	d, err := statfs(path)
+	if err != nil {
+		return d, fmt.Errorf("statfs %s: %w", path, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,117 @@
+package sysinfo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/sysinfo"
+)
+
+// fixture writes files (relative path → contents) under a temp root.
+func fixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestReader_CPUTemp(t *testing.T) {
+	root := fixture(t, map[string]string{"class/thermal/thermal_zone0/temp": "52123\n"})
+	temp, err := sysinfo.Reader{SysRoot: root}.CPUTemp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if temp != 52.123 {
+		t.Errorf("CPUTemp = %v, want 52.123", temp)
+	}
+}
+
+func TestReader_LoadAvg(t *testing.T) {
+	root := fixture(t, map[string]string{"loadavg": "1.24 0.98 0.50 2/345 6789\n"})
+	load, err := sysinfo.Reader{ProcRoot: root}.LoadAvg()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if load != [3]float64{1.24, 0.98, 0.50} {
+		t.Errorf("LoadAvg = %v", load)
+	}
+}
+
+func TestReader_Memory(t *testing.T) {
+	root := fixture(t, map[string]string{"meminfo": "MemTotal:        1000 kB\nMemFree:          100 kB\nMemAvailable:     250 kB\n"})
+	m, err := sysinfo.Reader{ProcRoot: root}.Memory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Total != 1000*1024 || m.Available != 250*1024 {
+		t.Errorf("Memory = %+v", m)
+	}
+	if m.UsedPercent() != 75 {
+		t.Errorf("UsedPercent = %v, want 75", m.UsedPercent())
+	}
+}
+
+func TestReader_Uptime(t *testing.T) {
+	root := fixture(t, map[string]string{"uptime": "90061.50 12345.00\n"})
+	up, err := sysinfo.Reader{ProcRoot: root}.Uptime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if up != 90061*time.Second+500*time.Millisecond {
+		t.Errorf("Uptime = %v", up)
+	}
+}
+
+func TestReader_MissingFile(t *testing.T) {
+	r := sysinfo.Reader{ProcRoot: t.TempDir(), SysRoot: t.TempDir()}
+	if _, err := r.CPUTemp(); err == nil {
+		t.Error("expected error for missing thermal file")
+	}
+	if _, err := r.LoadAvg(); err == nil {
+		t.Error("expected error for missing loadavg")
+	}
+}
+
+func TestReader_Disk(t *testing.T) {
+	d, err := sysinfo.Reader{}.Disk(t.TempDir())
+	if err != nil {
+		t.Skipf("statfs unavailable: %v", err)
+	}
+	if d.Total == 0 || d.Free > d.Total {
+		t.Errorf("Disk = %+v", d)
+	}
+}
+
+func TestReader_Wireless(t *testing.T) {
+	root := fixture(t, map[string]string{"net/wireless": `Inter-| sta-|   Quality        |   Discarded packets               | Missed | WE
+ face | tus | link level noise |  nwid  crypt   frag  retry   misc | beacon | 22
+ wlan0: 0000   70.  -40.  -256        0      0      0      0      0        0
+`})
+	wl, err := sysinfo.Reader{ProcRoot: root}.Wireless()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wl) != 1 {
+		t.Fatalf("expected 1 wireless interface, got %d", len(wl))
+	}
+	if wl[0].Interface != "wlan0" || wl[0].Quality != 70 || wl[0].Signal != -40 {
+		t.Errorf("Wireless = %+v", wl[0])
+	}
+}
+
+func TestReader_Wireless_NoFile(t *testing.T) {
+	wl, err := sysinfo.Reader{ProcRoot: t.TempDir()}.Wireless()
+	if err != nil || wl != nil {
+		t.Errorf("expected (nil, nil) without /proc/net/wireless, got (%v, %v)", wl, err)
+	}
+}
@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxAuthBody limits the body read to check the signature of a request
+// passed through Auth.Require.
+const maxAuthBody = 1 << 20
+
+// Auth authenticates requests by a bearer token or an HMAC-SHA256 signature
+// of the body in SignatureHeader. The zero Auth refuses every request.
+type Auth struct {
+	Token  string // bearer token; empty disables bearer auth
+	Secret string // HMAC key for SignatureHeader; empty disables signatures
+}
+
+// Enabled reports whether any request can be authorized.
+func (a Auth) Enabled() bool {
+	return a.Token != "" || a.Secret != ""
+}
+
+// Authorized checks the bearer token or the signature of body, the request
+// body of r.
+func (a Auth) Authorized(r *http.Request, body []byte) bool {
+	if a.Token != "" {
+		if tok, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok &&
+			subtle.ConstantTimeCompare([]byte(tok), []byte(a.Token)) == 1 {
+			return true
+		}
+	}
+	if a.Secret != "" {
+		if sig, ok := strings.CutPrefix(r.Header.Get(SignatureHeader), "sha256="); ok {
+			got, err := hex.DecodeString(sig)
+			return err == nil && hmac.Equal(got, Sign([]byte(a.Secret), body))
+		}
+	}
+	return false
+}
+
+// Require wraps next so only authorized requests reach it. The body is read
+// for the signature check and handed on to next unchanged.
+func (a Auth) Require(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAuthBody))
+		if err != nil {
+			http.Error(w, "reading body: "+err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if !a.Authorized(r, body) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket allowing a burst of perMinute requests, refilled
+// at perMinute requests per minute.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter creates a limiter allowing perMinute requests per minute.
+func NewLimiter(perMinute int) *Limiter {
+	n := float64(perMinute)
+	return &Limiter{rate: n / 60, burst: n, tokens: n}
+}
+
+// Allow reports whether a request at now is within the limit.
+func (l *Limiter) Allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/redis"
+)
+
+// Push types.
+const (
+	TypeMessage = "message" // one-off message, deleted after display
+	TypeAlert   = "alert"   // alert entry
+	TypeText    = "text"    // dynamic text value read via dynamic_source
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the body, as "sha256=<hex>".
+const SignatureHeader = "X-Kurokku-Signature"
+
+// TextKeyPrefix is required of text keys, keeping pushes within kurokku's
+// namespace.
+const TextKeyPrefix = "kurokku:"
+
+// maxBody limits the size of a push.
+const maxBody = 64 << 10
+
+// Request is the JSON body of a push.
+type Request struct {
+	Type string `json:"type"`
+	// Message: the text shown, with an optional ID and priority.
+	ID       string `json:"id,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Priority int    `json:"priority,omitempty"`
+	// Alert: the alert entry, with an ID generated when empty.
+	Alert *config.AlertConfig `json:"alert,omitempty"`
+	// Text: the value stored at key.
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+	// TTL expires the message, alert or text; 0 keeps it.
+	TTL config.Duration `json:"ttl,omitempty"`
+}
+
+// Store keeps pushed alerts and text. *redis.Client implements it, as do the
+// engine's in-process AlertList and TextStore together.
+type Store interface {
+	SetAlert(ctx context.Context, a config.AlertConfig) error
+	SetMessageText(ctx context.Context, key, value string, ttl time.Duration) error
+}
+
+// Handler accepts pushes authenticated by a bearer token or an HMAC-SHA256
+// signature of the body. Requests are refused unless Token or Secret is set.
+type Handler struct {
+	Store   Store
+	Token   string   // bearer token; empty disables bearer auth
+	Secret  string   // HMAC key for SignatureHeader; empty disables signatures
+	Limiter *Limiter // optional
+	NowFunc func() time.Time
+}
+
+func (h *Handler) now() time.Time {
+	if h.NowFunc != nil {
+		return h.NowFunc()
+	}
+	return time.Now()
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.Limiter != nil && !h.Limiter.Allow(h.now()) {
+		http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+	if err != nil {
+		http.Error(w, "reading body: "+err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !h.authorized(r, body) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	id, err := h.push(r.Context(), req)
+	var bad badRequest
+	switch {
+	case err == nil:
+	case errors.As(err, &bad):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	default:
+		log.Printf("webhook %s: %v", req.Type, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if id == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"id": id}) //nolint:errcheck
+}
+
+// authorized checks the bearer token or the body signature.
+func (h *Handler) authorized(r *http.Request, body []byte) bool {
+	return Auth{Token: h.Token, Secret: h.Secret}.Authorized(r, body)
+}
+
+// Sign returns the HMAC-SHA256 of body under secret.
+func Sign(secret, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// push stores req, returning the ID of a pushed message or alert.
+func (h *Handler) push(ctx context.Context, req Request) (string, error) {
+	ttl := req.TTL.Unwrap()
+	switch req.Type {
+	case TypeMessage:
+		if req.Message == "" {
+			return "", badRequest("message is required")
+		}
+		a := config.AlertConfig{
+			ID:                 req.ID,
+			Message:            req.Message,
+			Priority:           req.Priority,
+			DeleteAfterDisplay: true,
+		}
+		return h.setAlert(ctx, a, ttl)
+	case TypeAlert:
+		if req.Alert == nil || req.Alert.Message == "" {
+			return "", badRequest("alert with a message is required")
+		}
+		return h.setAlert(ctx, *req.Alert, ttl)
+	case TypeText:
+		if !strings.HasPrefix(req.Key, TextKeyPrefix) || redis.ReservedKey(req.Key) {
+			return "", badRequest(fmt.Sprintf("key must start with %q and not be reserved", TextKeyPrefix))
+		}
+		return "", h.Store.SetMessageText(ctx, req.Key, req.Value, ttl)
+	}
+	return "", badRequest(fmt.Sprintf("unknown type %q", req.Type))
+}
+
+func (h *Handler) setAlert(ctx context.Context, a config.AlertConfig, ttl time.Duration) (string, error) {
+	now := h.now()
+	if a.ID == "" {
+		a.ID = fmt.Sprintf("push-%d", now.UnixNano())
+	}
+	if ttl > 0 && a.ExpiresAt == nil {
+		expires := now.Add(ttl)
+		a.ExpiresAt = &expires
+	}
+	return a.ID, h.Store.SetAlert(ctx, a)
+}
+
+// badRequest is an error in the request itself.
+type badRequest string
+
+func (e badRequest) Error() string { return string(e) }
@@ -0,0 +1,163 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/webhook"
+)
+
+type memStore struct {
+	alerts map[string]config.AlertConfig
+	texts  map[string]string
+	ttls   map[string]time.Duration
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		alerts: map[string]config.AlertConfig{},
+		texts:  map[string]string{},
+		ttls:   map[string]time.Duration{},
+	}
+}
+
+func (m *memStore) SetAlert(_ context.Context, a config.AlertConfig) error {
+	m.alerts[a.ID] = a
+	return nil
+}
+
+func (m *memStore) SetMessageText(_ context.Context, key, value string, ttl time.Duration) error {
+	m.texts[key] = value
+	m.ttls[key] = ttl
+	return nil
+}
+
+var now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func push(h http.Handler, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/webhooks/push", strings.NewReader(body))
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_Auth(t *testing.T) {
+	h := &webhook.Handler{Store: newMemStore(), Token: "t0ken", Secret: "s3cret"}
+	body := `{"type":"message","message":"hi"}`
+	sig := "sha256=" + hex.EncodeToString(webhook.Sign([]byte("s3cret"), []byte(body)))
+
+	tests := []struct {
+		name   string
+		header []string
+		want   int
+	}{
+		{"none", nil, http.StatusUnauthorized},
+		{"wrong token", []string{"Authorization", "Bearer nope"}, http.StatusUnauthorized},
+		{"bearer", []string{"Authorization", "Bearer t0ken"}, http.StatusOK},
+		{"signature", []string{webhook.SignatureHeader, sig}, http.StatusOK},
+		{"bad signature", []string{webhook.SignatureHeader, "sha256=00"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := push(h, body, tt.header...); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	// Without a token or secret nothing is accepted.
+	open := &webhook.Handler{Store: newMemStore()}
+	if rec := push(open, body, "Authorization", "Bearer "); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unconfigured status = %d, want 401", rec.Code)
+	}
+}
+
+func TestAuth_Require(t *testing.T) {
+	body := `{"level":3}`
+	var got string
+	h := webhook.Auth{Secret: "s3cret"}.Require(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = string(b)
+	}))
+	sig := "sha256=" + hex.EncodeToString(webhook.Sign([]byte("s3cret"), []byte(body)))
+	if rec := push(h, body, webhook.SignatureHeader, sig); rec.Code != http.StatusOK || got != body {
+		t.Errorf("signed: status %d, body %q; want 200 and the body passed on", rec.Code, got)
+	}
+	got = ""
+	if rec := push(h, body); rec.Code != http.StatusUnauthorized || got != "" {
+		t.Errorf("unsigned: status %d, handler reached: %v", rec.Code, got != "")
+	}
+	if rec := push(webhook.Auth{}.Require(h), body, webhook.SignatureHeader, sig); rec.Code != http.StatusUnauthorized {
+		t.Errorf("zero Auth: status %d, want 401", rec.Code)
+	}
+}
+
+func TestHandler_Types(t *testing.T) {
+	store := newMemStore()
+	h := &webhook.Handler{Store: store, Token: "t", NowFunc: func() time.Time { return now }}
+	auth := []string{"Authorization", "Bearer t"}
+
+	rec := push(h, `{"type":"message","id":"ci","message":"build ok","ttl":"10m"}`, auth...)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"ci"`) {
+		t.Fatalf("message: %d %s", rec.Code, rec.Body)
+	}
+	msg := store.alerts["ci"]
+	if !msg.DeleteAfterDisplay || msg.ExpiresAt == nil || !msg.ExpiresAt.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("message alert = %+v, want delete_after_display and expiry in 10m", msg)
+	}
+
+	push(h, `{"type":"alert","alert":{"message":"door open","priority":1}}`, auth...)
+	id := fmt.Sprintf("push-%d", now.UnixNano())
+	if a, ok := store.alerts[id]; !ok || a.Priority != 1 || a.ExpiresAt != nil {
+		t.Errorf("alert %s = %+v, %v", id, a, ok)
+	}
+
+	if rec := push(h, `{"type":"text","key":"kurokku:temp","value":"72F","ttl":"1h"}`, auth...); rec.Code != http.StatusNoContent {
+		t.Errorf("text status = %d, want 204", rec.Code)
+	}
+	if store.texts["kurokku:temp"] != "72F" || store.ttls["kurokku:temp"] != time.Hour {
+		t.Errorf("text = %q ttl %v", store.texts["kurokku:temp"], store.ttls["kurokku:temp"])
+	}
+
+	for _, body := range []string{
+		`{"type":"text","key":"other:temp","value":"x"}`,
+		`{"type":"text","key":"kurokku:config","value":"x"}`,
+		`{"type":"message"}`,
+		`{"type":"bogus"}`,
+		`{`,
+	} {
+		if rec := push(h, body, auth...); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, rec.Code)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := webhook.NewLimiter(2)
+	if !l.Allow(now) || !l.Allow(now) {
+		t.Fatal("burst of 2 should be allowed")
+	}
+	if l.Allow(now) {
+		t.Error("third request should be limited")
+	}
+	if !l.Allow(now.Add(30 * time.Second)) {
+		t.Error("a token should be refilled after 30s")
+	}
+
+	h := &webhook.Handler{Store: newMemStore(), Token: "t", Limiter: webhook.NewLimiter(1), NowFunc: func() time.Time { return now }}
+	push(h, `{"type":"message","message":"a"}`, "Authorization", "Bearer t")
+	if rec := push(h, `{"type":"message","message":"b"}`, "Authorization", "Bearer t"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429", rec.Code)
+	}
+}
@@ -10,12 +10,23 @@ import (
 	"github.com/swilcox/led-kurokku-go/internal/cronutil"
 )
 
-// Alert displays prioritized alert messages.
+// Alert displays prioritized alert messages. Alerts that require an ack are
+// shown on every run until acknowledged; acknowledged ones are skipped.
+// Alerts outside their time window or schedule are skipped, and expired
+// ones are deleted. Each alert is presented according to the profile for
+// its severity; duplicates and groups are collapsed by GroupAlerts.
 type Alert struct {
 	Alerts      []config.AlertConfig
 	ScrollSpeed time.Duration
 	OnDelete    func(ctx context.Context, id string)
+	OnDisplay   func(ctx context.Context, a config.AlertConfig) // called as each alert is shown
 	NowFunc     func() time.Time
+	Schedules   map[int]string                    // per-priority schedules; nil uses config.DefaultAlertSchedules
+	Profiles    map[string]config.SeverityProfile // overrides config.DefaultSeverityProfiles
+	Acks        AckTracker                        // optional
+	// HoldBrightness forces maximum brightness for max_brightness profiles
+	// until release is called. When nil the display is set directly.
+	HoldBrightness func() (release func())
 }
 
 func (a *Alert) now() time.Time {
@@ -31,6 +42,10 @@ func (a *Alert) Run(ctx context.Context, disp display.Display) error {
 	if len(a.Alerts) == 0 {
 		return nil
 	}
+	alerts := a.Alerts
+	if a.Acks != nil {
+		alerts = a.Acks.Apply(alerts)
+	}
 
 	// Sort by priority (lower = higher urgency), stable to preserve config order
 	sorted := make([]int, len(a.Alerts))
@@ -38,39 +53,60 @@ func (a *Alert) Run(ctx context.Context, disp display.Display) error {
 		sorted[i] = i
 	}
 	sort.SliceStable(sorted, func(i, j int) bool {
-		return a.Alerts[sorted[i]].Priority < a.Alerts[sorted[j]].Priority
+		return alerts[sorted[i]].Priority < alerts[sorted[j]].Priority
 	})
 
-	var toDelete []int
+	var toDelete, shown []int
 	for _, idx := range sorted {
-		alert := a.Alerts[idx]
-		if alert.Priority == 10 && !cronutil.MatchesNow("*/10 * * * *", a.now()) {
+		alert := alerts[idx]
+		now := a.now()
+		if alert.Expired(now) {
+			toDelete = a.deleteAlert(ctx, alert.ID, idx, toDelete)
+			continue
+		}
+		if alert.Acked() {
+			// Kept for the ack record but no longer shown.
+			if alert.DeleteAfterDisplay {
+				toDelete = a.deleteAlert(ctx, alert.ID, idx, toDelete)
+			}
 			continue
 		}
-		dur := alert.DisplayDuration.Unwrap()
+		if !alert.Live(now) {
+			continue
+		}
+		if s := alert.ScheduleFor(a.Schedules); s != "" && !cronutil.MatchesNow(s, now) {
+			continue
+		}
+		shown = append(shown, idx)
+	}
+
+	for _, item := range GroupAlerts(alerts, shown) {
+		dur := item.Alert.DisplayDuration.Unwrap()
 		if dur == 0 {
 			dur = 5 * time.Second
 		}
+		var p config.SeverityProfile
+		if !item.Summary {
+			p = item.Alert.ProfileFor(a.Profiles)
+		}
+		if a.OnDisplay != nil {
+			for _, idx := range item.Indices {
+				a.OnDisplay(ctx, alerts[idx])
+			}
+		}
 
 		alertCtx, cancel := context.WithTimeout(ctx, dur)
-
-		msg := &Message{
-			Text:        alert.Message,
-			ScrollSpeed: a.ScrollSpeed,
-			Repeats:     -1, // scroll until context done
-		}
-		msg.Run(alertCtx, disp)
+		a.present(alertCtx, disp, p, item.Text)
 		cancel()
 
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
 
-		if alert.DeleteAfterDisplay {
-			if a.OnDelete != nil {
-				a.OnDelete(ctx, alert.ID)
-			} else {
-				toDelete = append(toDelete, idx)
+		for _, idx := range item.Indices {
+			// Alerts waiting for an ack repeat until they get one.
+			if alert := alerts[idx]; alert.DeleteAfterDisplay && !alert.Pending() {
+				toDelete = a.deleteAlert(ctx, alert.ID, idx, toDelete)
 			}
 		}
 	}
@@ -85,3 +121,35 @@ func (a *Alert) Run(ctx context.Context, disp display.Display) error {
 
 	return nil
 }
+
+// present shows text until ctx is done, styled by a severity profile.
+func (a *Alert) present(ctx context.Context, disp display.Display, p config.SeverityProfile, text string) {
+	if p.MaxBrightness {
+		defer MaxBrightness(disp, a.HoldBrightness)()
+	}
+	if Flash(ctx, disp, p.Flashes, p.FlashInterval.Unwrap(), 0) != nil {
+		return
+	}
+	if p.Invert {
+		disp = Invert(disp)
+	}
+	if p.Icon {
+		text = WarningIcon + " " + text
+	}
+	msg := &Message{
+		Text:        text,
+		ScrollSpeed: a.ScrollSpeed,
+		Repeats:     -1, // scroll until context done
+	}
+	msg.Run(ctx, disp)
+}
+
+// deleteAlert removes an alert through OnDelete, or queues its index for
+// removal from Alerts when there is no OnDelete.
+func (a *Alert) deleteAlert(ctx context.Context, id string, idx int, toDelete []int) []int {
+	if a.OnDelete != nil {
+		a.OnDelete(ctx, id)
+		return toDelete
+	}
+	return append(toDelete, idx)
+}
@@ -0,0 +1,106 @@
+package widget
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/swilcox/led-kurokku-go/config"
+)
+
+// AlertItem is one step of an alert run: an alert standing in for its
+// duplicates, or the summary line shown before a group's alerts.
+type AlertItem struct {
+	Alert   config.AlertConfig // most urgent alert of the item
+	Text    string             // message, with a " x5" count for duplicates
+	Indices []int              // alerts shown by this item; empty for summaries
+	Summary bool
+}
+
+// GroupAlerts plans the display of alerts[i] for each i in order, which must
+// be sorted by urgency. Alerts with the same fingerprint (or, without one,
+// the same non-empty message) collapse into one item with a count. Alerts sharing a
+// group are shown together where the group's most urgent alert would be,
+// after a summary line such as "3 CRIT, 2 WARN" when the group has more
+// than one item.
+func GroupAlerts(alerts []config.AlertConfig, order []int) []AlertItem {
+	type group struct {
+		name  string
+		items []*AlertItem
+		count map[string]int // alerts per severity label
+	}
+	var groups []*group
+	byName := map[string]*group{}
+	byKey := map[string]*AlertItem{}
+
+	for _, idx := range order {
+		a := alerts[idx]
+		g := byName[a.Group]
+		if g == nil || a.Group == "" {
+			g = &group{name: a.Group, count: map[string]int{}}
+			groups = append(groups, g)
+			if a.Group != "" {
+				byName[a.Group] = g
+			}
+		}
+		g.count[severityLabel(a.Severity)]++
+
+		// Alerts without a fingerprint or message are never duplicates.
+		key := a.Fingerprint
+		switch {
+		case key != "":
+		case a.Message != "":
+			key = "message:" + a.Message
+		default:
+			key = fmt.Sprintf("index:%d", idx)
+		}
+		key = a.Group + "\x00" + key
+		if it := byKey[key]; it != nil {
+			it.Indices = append(it.Indices, idx)
+			continue
+		}
+		it := &AlertItem{Alert: a, Indices: []int{idx}}
+		byKey[key] = it
+		g.items = append(g.items, it)
+	}
+
+	var plan []AlertItem
+	for _, g := range groups {
+		if g.name != "" && len(g.items) > 1 {
+			plan = append(plan, AlertItem{
+				Alert:   g.items[0].Alert,
+				Text:    severitySummary(g.count),
+				Summary: true,
+			})
+		}
+		for _, it := range g.items {
+			it.Text = it.Alert.Message
+			if n := len(it.Indices); n > 1 {
+				it.Text = fmt.Sprintf("%s x%d", it.Text, n)
+			}
+			plan = append(plan, *it)
+		}
+	}
+	return plan
+}
+
+// severityLabel is the short name used in group summaries.
+func severityLabel(severity string) string {
+	switch severity {
+	case config.SeverityCritical:
+		return "CRIT"
+	case config.SeverityWarn:
+		return "WARN"
+	}
+	return "INFO"
+}
+
+// severitySummary formats counts per label, most severe first.
+func severitySummary(count map[string]int) string {
+	var parts []string
+	for _, label := range []string{"CRIT", "WARN", "INFO"} {
+		if n := count[label]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, label))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
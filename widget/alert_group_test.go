@@ -0,0 +1,97 @@
+package widget_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+func planTexts(plan []widget.AlertItem) []string {
+	texts := make([]string, len(plan))
+	for i, it := range plan {
+		texts[i] = it.Text
+	}
+	return texts
+}
+
+func TestGroupAlerts_CollapsesDuplicates(t *testing.T) {
+	alerts := []config.AlertConfig{
+		{ID: "1", Message: "DISK", Fingerprint: "disk"},
+		{ID: "2", Message: "CPU"},
+		{ID: "3", Message: "DISK on b", Fingerprint: "disk"},
+		{ID: "4", Message: "CPU"},
+	}
+	plan := widget.GroupAlerts(alerts, []int{0, 1, 2, 3})
+
+	want := []string{"DISK x2", "CPU x2"}
+	got := planTexts(plan)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("plan = %q, want %q", got, want)
+	}
+	if len(plan[0].Indices) != 2 || plan[0].Indices[1] != 2 {
+		t.Errorf("DISK item indices = %v, want [0 2]", plan[0].Indices)
+	}
+
+	// Alerts without a fingerprint or message are kept apart.
+	if plan := widget.GroupAlerts([]config.AlertConfig{{ID: "a"}, {ID: "b"}}, []int{0, 1}); len(plan) != 2 {
+		t.Errorf("empty-message alerts collapsed: %+v", plan)
+	}
+}
+
+func TestGroupAlerts_SummarizesGroups(t *testing.T) {
+	alerts := []config.AlertConfig{
+		{ID: "a", Message: "DB DOWN", Group: "db", Severity: config.SeverityCritical},
+		{ID: "b", Message: "NOTE"},
+		{ID: "c", Message: "DB SLOW", Group: "db", Severity: config.SeverityWarn},
+		{ID: "d", Message: "DB DOWN", Group: "db", Severity: config.SeverityCritical},
+		{ID: "e", Message: "WEB", Group: "web"},
+	}
+	plan := widget.GroupAlerts(alerts, []int{0, 1, 2, 3, 4})
+
+	want := []string{"2 CRIT, 1 WARN", "DB DOWN x2", "DB SLOW", "NOTE", "WEB"}
+	got := planTexts(plan)
+	if len(got) != len(want) {
+		t.Fatalf("plan = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("plan = %q, want %q", got, want)
+		}
+	}
+	if !plan[0].Summary || len(plan[0].Indices) != 0 {
+		t.Errorf("first item should be a summary without alerts: %+v", plan[0])
+	}
+}
+
+func TestAlert_DuplicatesShownOnceAndAllDeleted(t *testing.T) {
+	var deleted, displayed []string
+	spy := &testutil.SpyDisplay{}
+	a := &widget.Alert{
+		Alerts: []config.AlertConfig{
+			{ID: "1", Message: "X", Priority: 1, Fingerprint: "f", DisplayDuration: config.Duration(time.Millisecond), DeleteAfterDisplay: true},
+			{ID: "2", Message: "X", Priority: 2, Fingerprint: "f", DisplayDuration: config.Duration(time.Millisecond), DeleteAfterDisplay: true},
+		},
+		OnDelete: func(_ context.Context, id string) {
+			deleted = append(deleted, id)
+		},
+		OnDisplay: func(_ context.Context, a config.AlertConfig) {
+			displayed = append(displayed, a.ID)
+		},
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+
+	if len(displayed) != 2 {
+		t.Errorf("expected OnDisplay for both duplicates, got %v", displayed)
+	}
+	if len(spy.Frames) != 1 {
+		t.Errorf("expected the duplicates to be shown once, got %d frames", len(spy.Frames))
+	}
+	if len(deleted) != 2 {
+		t.Errorf("expected both duplicates to be deleted, got %v", deleted)
+	}
+}
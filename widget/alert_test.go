@@ -188,3 +188,194 @@ func TestAlert_OnDelete_NotCalledWithoutFlag(t *testing.T) {
 		t.Errorf("expected OnDelete not called when DeleteAfterDisplay=false, got %v", deleted)
 	}
 }
+
+func TestAlert_Acked_SkippedAndDeleted(t *testing.T) {
+	var deleted []string
+	spy := &testutil.SpyDisplay{}
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	a := &widget.Alert{
+		Alerts: []config.AlertConfig{
+			{
+				ID:                 "acked",
+				Message:            "Hi",
+				Priority:           1,
+				DisplayDuration:    config.Duration(time.Millisecond),
+				DeleteAfterDisplay: true,
+				AckRequired:        true,
+				AckedAt:            &at,
+			},
+		},
+		OnDelete: func(_ context.Context, id string) {
+			deleted = append(deleted, id)
+		},
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+
+	if len(spy.Frames) != 0 {
+		t.Errorf("expected acked alert not to be shown, got %d frames", len(spy.Frames))
+	}
+	if len(deleted) != 1 || deleted[0] != "acked" {
+		t.Errorf("expected acked alert to be deleted, got %v", deleted)
+	}
+}
+
+func TestAlert_Pending_NotDeleted(t *testing.T) {
+	var deleted []string
+	spy := &testutil.SpyDisplay{}
+
+	a := &widget.Alert{
+		Alerts: []config.AlertConfig{
+			{
+				ID:                 "pending",
+				Message:            "Hi",
+				Priority:           1,
+				DisplayDuration:    config.Duration(time.Millisecond),
+				DeleteAfterDisplay: true,
+				AckRequired:        true,
+			},
+		},
+		OnDelete: func(_ context.Context, id string) {
+			deleted = append(deleted, id)
+		},
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+
+	if len(spy.Frames) == 0 {
+		t.Error("expected pending alert to be shown")
+	}
+	if len(deleted) != 0 {
+		t.Errorf("expected pending alert to be kept until acked, got %v", deleted)
+	}
+}
+
+// ackAll marks every ack_required alert as acknowledged.
+type ackAll struct{}
+
+func (ackAll) Apply(alerts []config.AlertConfig) []config.AlertConfig {
+	out := make([]config.AlertConfig, len(alerts))
+	now := time.Now()
+	for i, a := range alerts {
+		if a.AckRequired {
+			a.AckedAt, a.AckedBy = &now, "http"
+		}
+		out[i] = a
+	}
+	return out
+}
+
+func TestAlert_AckTracker_Applied(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	a := &widget.Alert{
+		Alerts: []config.AlertConfig{
+			{ID: "a", Message: "Hi", Priority: 1, DisplayDuration: config.Duration(time.Millisecond), AckRequired: true},
+		},
+		Acks: ackAll{},
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+
+	if len(spy.Frames) != 0 {
+		t.Errorf("expected alert acked by tracker not to be shown, got %d frames", len(spy.Frames))
+	}
+}
+
+func TestAlert_Schedules_ReplacePriorityDefault(t *testing.T) {
+	at := func() time.Time { return time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC) }
+	schedules := map[int]string{3: "0 * * * *"}
+
+	// Configured schedules replace the default priority-10 throttle...
+	spy := &testutil.SpyDisplay{}
+	a := &widget.Alert{
+		Alerts: []config.AlertConfig{
+			{ID: "p10", Message: "Lo", Priority: 10, DisplayDuration: config.Duration(time.Millisecond)},
+		},
+		Schedules: schedules,
+		NowFunc:   at,
+	}
+	a.Run(context.Background(), spy) //nolint:errcheck
+	if len(spy.Frames) == 0 {
+		t.Error("expected priority-10 alert to be shown without the default throttle")
+	}
+
+	// ...and apply to alerts at their priority.
+	spy = &testutil.SpyDisplay{}
+	a = &widget.Alert{
+		Alerts: []config.AlertConfig{
+			{ID: "p3", Message: "Hi", Priority: 3, DisplayDuration: config.Duration(time.Millisecond)},
+		},
+		Schedules: schedules,
+		NowFunc:   at,
+	}
+	a.Run(context.Background(), spy) //nolint:errcheck
+	if len(spy.Frames) != 0 {
+		t.Errorf("expected priority-3 alert to follow its schedule, got %d frames", len(spy.Frames))
+	}
+}
+
+func TestAlert_TimeWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)
+	before, after := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		alert config.AlertConfig
+		shown bool
+	}{
+		{"started", config.AlertConfig{StartsAt: &before}, true},
+		{"not started", config.AlertConfig{StartsAt: &after}, false},
+		{"not expired", config.AlertConfig{ExpiresAt: &after}, true},
+		{"snoozed", config.AlertConfig{SnoozeUntil: &after}, false},
+		{"snooze over", config.AlertConfig{SnoozeUntil: &before}, true},
+		{"schedule matches", config.AlertConfig{Schedule: "5 12 * * *"}, true},
+		{"schedule misses", config.AlertConfig{Schedule: "0 * * * *"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := &testutil.SpyDisplay{}
+			alert := tt.alert
+			alert.ID, alert.Message, alert.Priority = "a", "Hi", 1
+			alert.DisplayDuration = config.Duration(time.Millisecond)
+			a := &widget.Alert{
+				Alerts:  []config.AlertConfig{alert},
+				NowFunc: func() time.Time { return now },
+			}
+
+			a.Run(context.Background(), spy) //nolint:errcheck
+
+			if shown := len(spy.Frames) > 0; shown != tt.shown {
+				t.Errorf("shown = %v, want %v", shown, tt.shown)
+			}
+			if len(a.Alerts) != 1 {
+				t.Errorf("expected alert to be kept, got %d remaining", len(a.Alerts))
+			}
+		})
+	}
+}
+
+func TestAlert_Expired_Deleted(t *testing.T) {
+	var deleted []string
+	spy := &testutil.SpyDisplay{}
+	expired := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	a := &widget.Alert{
+		Alerts: []config.AlertConfig{
+			{ID: "old", Message: "Hi", Priority: 1, DisplayDuration: config.Duration(time.Millisecond), ExpiresAt: &expired},
+		},
+		NowFunc: func() time.Time { return expired.Add(time.Second) },
+		OnDelete: func(_ context.Context, id string) {
+			deleted = append(deleted, id)
+		},
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+
+	if len(spy.Frames) != 0 {
+		t.Errorf("expected expired alert not to be shown, got %d frames", len(spy.Frames))
+	}
+	if len(deleted) != 1 || deleted[0] != "old" {
+		t.Errorf("expected expired alert to be deleted, got %v", deleted)
+	}
+}
@@ -2,11 +2,14 @@ package animation_test
 
 import (
 	"context"
+	"math/bits"
 	"testing"
 	"time"
 
 	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display"
 	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/widget"
 	"github.com/swilcox/led-kurokku-go/widget/animation"
 )
 
@@ -185,3 +188,127 @@ func bytesEqual(a, b []byte) bool {
 	}
 	return true
 }
+
+func TestLife_SeedIsRepeatable(t *testing.T) {
+	run := func() []byte {
+		spy := &testutil.SpyDisplay{}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		(&animation.Life{Params: widget.AnimParams{Seed: 42, Speed: 10}}).Run(ctx, spy) //nolint:errcheck
+		if len(spy.Frames) == 0 {
+			t.Fatal("expected frames from Life animation")
+		}
+		return spy.Frames[0]
+	}
+	if a, b := run(), run(); !bytesEqual(a, b) {
+		t.Errorf("seeded runs differ:\n%x\n%x", a, b)
+	}
+}
+
+func TestScanner_Invert(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	(&animation.Scanner{Params: widget.AnimParams{Invert: true}}).Run(ctx, spy) //nolint:errcheck
+	if len(spy.Frames) == 0 {
+		t.Fatal("expected frames from Scanner animation")
+	}
+	if spy.Frames[0][0] != 0x00 || spy.Frames[0][31] != 0xFF {
+		t.Errorf("inverted first frame = %x, want head dark and background lit", spy.Frames[0])
+	}
+}
+
+func TestStatic_Density(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	(&animation.Static{Params: widget.AnimParams{Density: 1}}).Run(ctx, spy) //nolint:errcheck
+	if len(spy.Frames) == 0 {
+		t.Fatal("expected frames from Static animation")
+	}
+	for x, col := range spy.Frames[0] {
+		if col != 0xFF {
+			t.Fatalf("column %d = %#x, want every pixel lit at density 1", x, col)
+		}
+	}
+}
+
+func TestRegistry_PassesParams(t *testing.T) {
+	for name, factory := range animation.Registry {
+		if w := factory(widget.AnimParams{Speed: 2}); w == nil {
+			t.Errorf("Registry[%q] returned nil", name)
+		}
+	}
+}
+
+// runFor runs w for d at ten times normal speed and returns the frames.
+func runFor(t *testing.T, w interface {
+	Run(context.Context, display.Display) error
+}, d time.Duration) [][]byte {
+	t.Helper()
+	spy := &testutil.SpyDisplay{}
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	w.Run(ctx, spy) //nolint:errcheck
+	if len(spy.Frames) == 0 {
+		t.Fatal("expected frames")
+	}
+	return spy.Frames
+}
+
+func TestNewAnimations_WriteFrames(t *testing.T) {
+	fast := widget.AnimParams{Speed: 10, Seed: 1}
+	for _, name := range []string{"fire", "starfield", "plasma", "fireworks", "snake", "pong", "tetris"} {
+		t.Run(name, func(t *testing.T) {
+			factory, ok := animation.Registry[name]
+			if !ok {
+				t.Fatalf("Registry missing %q", name)
+			}
+			frames := runFor(t, factory(fast), 300*time.Millisecond)
+			changed := false
+			for _, f := range frames[1:] {
+				if !bytesEqual(f, frames[0]) {
+					changed = true
+					break
+				}
+			}
+			if !changed {
+				t.Errorf("%s frames never changed", name)
+			}
+		})
+	}
+}
+
+func TestFire_HotterAtBottom(t *testing.T) {
+	frames := runFor(t, &animation.Fire{Params: widget.AnimParams{Speed: 10}}, 200*time.Millisecond)
+	var top, bottom int
+	for _, f := range frames {
+		for _, col := range f {
+			top += int(col & 1)
+			bottom += int(col >> 7)
+		}
+	}
+	if bottom <= top {
+		t.Errorf("bottom row lit %d times, top row %d; want flames rising from the bottom", bottom, top)
+	}
+}
+
+func TestPong_DrawsPaddles(t *testing.T) {
+	frames := runFor(t, &animation.Pong{Params: widget.AnimParams{Speed: 10}}, 200*time.Millisecond)
+	for i, f := range frames {
+		// Each paddle is three pixels; the ball may add one more.
+		if n := bits.OnesCount8(f[0]); n < 3 {
+			t.Fatalf("frame %d: left paddle has %d pixels", i, n)
+		}
+		if n := bits.OnesCount8(f[31]); n < 3 {
+			t.Fatalf("frame %d: right paddle has %d pixels", i, n)
+		}
+	}
+}
+
+func TestTetris_PiecesLandOnTheLeft(t *testing.T) {
+	frames := runFor(t, &animation.Tetris{Params: widget.AnimParams{Speed: 10, Seed: 3}}, 400*time.Millisecond)
+	if frames[len(frames)-1][0] == 0 {
+		t.Error("expected the stack to build up from the left edge")
+	}
+}
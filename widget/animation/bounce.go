@@ -6,10 +6,14 @@ import (
 
 	"github.com/swilcox/led-kurokku-go/display"
 	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
 )
 
 // Bounce simulates a pixel bouncing around the display with a short trail.
-type Bounce struct{}
+// Density and Seed do not apply; the path is always the same.
+type Bounce struct {
+	Params widget.AnimParams
+}
 
 func (b *Bounce) Name() string { return "bounce" }
 
@@ -23,7 +27,7 @@ func (b *Bounce) Run(ctx context.Context, disp display.Display) error {
 	px, py := int(x), int(y)
 	ppx, ppy := int(x), int(y)
 
-	ticker := time.NewTicker(50 * time.Millisecond)
+	ticker := time.NewTicker(b.Params.Interval(50 * time.Millisecond))
 	defer ticker.Stop()
 
 	for {
@@ -58,6 +62,6 @@ func (b *Bounce) Run(ctx context.Context, disp display.Display) error {
 		f.SetPixel(ppx, ppy, true)
 		f.SetPixel(px, py, true)
 		f.SetPixel(int(x), int(y), true)
-		pd.WriteFramebuffer(f.Bytes())
+		write(pd, f, b.Params)
 	}
 }
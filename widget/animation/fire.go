@@ -0,0 +1,68 @@
+package animation
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Fire simulates flames rising from the bottom edge. Heat is fed into a
+// hidden row below the display, rises and cools, and is dithered to pixels.
+// Density is the chance each fuel cell burns on a frame (default 0.6).
+type Fire struct {
+	Params widget.AnimParams
+}
+
+func (a *Fire) Name() string { return "fire" }
+
+func (a *Fire) Run(ctx context.Context, disp display.Display) error {
+	pd := disp.(display.PixelDisplay)
+	w, h := size(pd)
+	rng := a.Params.Rand()
+	fuel := a.Params.DensityOr(0.6)
+
+	// heat[y][x]; row h is the fuel row below the visible area.
+	heat := make([][]float64, h+1)
+	for y := range heat {
+		heat[y] = make([]float64, w)
+	}
+
+	ticker := time.NewTicker(a.Params.Interval(60 * time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		for x := 0; x < w; x++ {
+			heat[h][x] = 0
+			if rng.Float64() < fuel {
+				heat[h][x] = 1
+			}
+		}
+		// Rows are updated top-down, so each reads the row below as it was
+		// on the previous frame and flames rise one row per frame.
+		for y := 0; y < h; y++ {
+			below := heat[y+1]
+			for x := 0; x < w; x++ {
+				sum := 2*below[x] + below[(x+w-1)%w] + below[(x+1)%w]
+				heat[y][x] = math.Max(0, sum/4-0.16*rng.Float64())
+			}
+		}
+
+		var f framebuf.Frame
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				f.SetPixel(x, y, dither(x, y, heat[y][x]))
+			}
+		}
+		write(pd, f, a.Params)
+	}
+}
@@ -0,0 +1,99 @@
+package animation
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Fireworks launches rockets from the bottom edge that burst into particles
+// falling under gravity. Density is the chance per frame of a launch
+// (default 0.06); at most three rockets are in flight at once.
+type Fireworks struct {
+	Params widget.AnimParams
+}
+
+func (a *Fireworks) Name() string { return "fireworks" }
+
+type particle struct {
+	x, y, vx, vy float64
+	life         int // frames left; rockets use -1
+	burstY       float64
+}
+
+func (a *Fireworks) Run(ctx context.Context, disp display.Display) error {
+	pd := disp.(display.PixelDisplay)
+	w, h := size(pd)
+	rng := a.Params.Rand()
+	launch := a.Params.DensityOr(0.06)
+
+	var rockets, sparks []particle
+
+	ticker := time.NewTicker(a.Params.Interval(50 * time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if len(rockets) < 3 && rng.Float64() < launch {
+			rockets = append(rockets, particle{
+				x:      1 + rng.Float64()*float64(w-2),
+				y:      float64(h - 1),
+				vy:     -(0.35 + 0.2*rng.Float64()),
+				life:   -1,
+				burstY: rng.Float64() * float64(h) / 2,
+			})
+		}
+
+		var f framebuf.Frame
+		live := rockets[:0]
+		for _, r := range rockets {
+			r.y += r.vy
+			if r.y > r.burstY {
+				f.SetPixel(int(r.x), int(math.Round(r.y)), true)
+				live = append(live, r)
+				continue
+			}
+			n := 10 + rng.Intn(8)
+			for i := 0; i < n; i++ {
+				angle := 2 * math.Pi * (float64(i) + rng.Float64()) / float64(n)
+				speed := 0.3 + 0.5*rng.Float64()
+				sparks = append(sparks, particle{
+					x: r.x, y: r.y,
+					vx:   speed * math.Cos(angle) * 1.5, // wider than tall, like the matrix
+					vy:   speed * math.Sin(angle),
+					life: 8 + rng.Intn(8),
+				})
+			}
+		}
+		rockets = live
+
+		liveSparks := sparks[:0]
+		for _, s := range sparks {
+			s.x += s.vx
+			s.y += s.vy
+			s.vx *= 0.9
+			s.vy = s.vy*0.9 + 0.06 // drag and gravity
+			s.life--
+			if s.life <= 0 || s.y >= float64(h) || s.x < 0 || s.x >= float64(w) {
+				continue
+			}
+			// Fading sparks flicker before going out.
+			if s.life > 3 || rng.Intn(2) == 0 {
+				f.SetPixel(int(math.Round(s.x)), int(math.Round(s.y)), true)
+			}
+			liveSparks = append(liveSparks, s)
+		}
+		sparks = liveSparks
+
+		write(pd, f, a.Params)
+	}
+}
@@ -7,21 +7,27 @@ import (
 
 	"github.com/swilcox/led-kurokku-go/display"
 	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
 )
 
 // Life runs Conway's Game of Life on the 32x8 display with toroidal wrapping.
 // When the grid stagnates (no change for 3 generations), it re-seeds randomly.
-type Life struct{}
+// Density is the fraction of cells alive in each seeding (default 1/3).
+type Life struct {
+	Params widget.AnimParams
+}
 
 func (l *Life) Name() string { return "life" }
 
 func (l *Life) Run(ctx context.Context, disp display.Display) error {
 	pd := disp.(display.PixelDisplay)
 
-	grid := lifeNewGrid()
+	rng := l.Params.Rand()
+	density := l.Params.DensityOr(1.0 / 3)
+	grid := lifeNewGrid(rng, density)
 	stagnant := 0
 
-	ticker := time.NewTicker(150 * time.Millisecond)
+	ticker := time.NewTicker(l.Params.Interval(150 * time.Millisecond))
 	defer ticker.Stop()
 
 	for {
@@ -41,7 +47,7 @@ func (l *Life) Run(ctx context.Context, disp display.Display) error {
 				}
 			}
 		}
-		pd.WriteFramebuffer(f.Bytes())
+		write(pd, f, l.Params)
 
 		if next == grid {
 			stagnant++
@@ -49,7 +55,7 @@ func (l *Life) Run(ctx context.Context, disp display.Display) error {
 			stagnant = 0
 		}
 		if stagnant >= 3 {
-			grid = lifeNewGrid()
+			grid = lifeNewGrid(rng, density)
 			stagnant = 0
 		} else {
 			grid = next
@@ -57,11 +63,11 @@ func (l *Life) Run(ctx context.Context, disp display.Display) error {
 	}
 }
 
-func lifeNewGrid() [32][8]bool {
+func lifeNewGrid(rng *rand.Rand, density float64) [32][8]bool {
 	var g [32][8]bool
 	for x := 0; x < 32; x++ {
 		for y := 0; y < 8; y++ {
-			g[x][y] = rand.Intn(3) == 0 // ~33% alive
+			g[x][y] = rng.Float64() < density
 		}
 	}
 	return g
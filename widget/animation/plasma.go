@@ -0,0 +1,59 @@
+package animation
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Plasma draws the classic demoscene plasma, a sum of moving sine fields,
+// with ordered dithering standing in for grey levels. Density shifts the
+// overall brightness (default 0.5); Seed picks the field phases.
+type Plasma struct {
+	Params widget.AnimParams
+}
+
+func (a *Plasma) Name() string { return "plasma" }
+
+func (a *Plasma) Run(ctx context.Context, disp display.Display) error {
+	pd := disp.(display.PixelDisplay)
+	w, h := size(pd)
+	rng := a.Params.Rand()
+	bias := a.Params.DensityOr(0.5) - 0.5
+
+	var phase [4]float64
+	for i := range phase {
+		phase[i] = rng.Float64() * 2 * math.Pi
+	}
+	cx, cy := float64(w)/2, float64(h)/2
+
+	var t float64
+	ticker := time.NewTicker(a.Params.Interval(60 * time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		var f framebuf.Frame
+		for x := 0; x < w; x++ {
+			for y := 0; y < h; y++ {
+				fx, fy := float64(x), float64(y)
+				v := math.Sin(fx/5+t+phase[0]) +
+					math.Sin(fy/3-1.3*t+phase[1]) +
+					math.Sin((fx+2*fy)/7+0.7*t+phase[2]) +
+					math.Sin(math.Hypot(fx-cx, 2*(fy-cy))/4-t+phase[3])
+				f.SetPixel(x, y, dither(x, y, (v+4)/8+bias))
+			}
+		}
+		write(pd, f, a.Params)
+		t += 0.1
+	}
+}
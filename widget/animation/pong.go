@@ -0,0 +1,117 @@
+package animation
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Pong plays a rally between two computer paddles at the left and right
+// edges. Each time the ball heads towards a paddle it either reads the ball
+// correctly or misjudges it by a paddle's height, so points are eventually
+// won. Density is the chance of reading it correctly (default 0.8); Seed
+// makes a match repeatable.
+type Pong struct {
+	Params widget.AnimParams
+}
+
+func (a *Pong) Name() string { return "pong" }
+
+const pongPaddle = 3 // paddle height in pixels
+
+func (a *Pong) Run(ctx context.Context, disp display.Display) error {
+	pd := disp.(display.PixelDisplay)
+	w, h := size(pd)
+	rng := a.Params.Rand()
+	skill := a.Params.DensityOr(0.8)
+
+	paddles := [2]int{(h - pongPaddle) / 2, (h - pongPaddle) / 2} // top rows
+	var aim [2]int                                                // the receiving paddle's misjudgement, in rows
+	judge := func(i int) {
+		aim[i] = 0
+		if rng.Float64() >= skill {
+			aim[i] = pongPaddle * (1 - 2*rng.Intn(2))
+		}
+	}
+	var bx, by, vx, vy float64
+	pause := 0
+	serve := func(dir float64) {
+		judge(int(dir+1) / 2)
+		bx, by = float64(w)/2, float64(rng.Intn(h))
+		vx = dir * 0.8
+		vy = (0.3 + 0.4*rng.Float64()) * float64(1-2*rng.Intn(2))
+		pause = 10
+	}
+	serve(1)
+
+	ticker := time.NewTicker(a.Params.Interval(50 * time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if pause > 0 {
+			pause--
+		} else {
+			// The receiving paddle chases the ball once it crosses the net.
+			for i := range paddles {
+				if (i == 0) != (vx < 0) || (i == 0) != (bx < float64(w)/2) {
+					continue
+				}
+				target := int(math.Round(by)) + aim[i] - pongPaddle/2
+				switch {
+				case target < paddles[i] && paddles[i] > 0:
+					paddles[i]--
+				case target > paddles[i] && paddles[i] < h-pongPaddle:
+					paddles[i]++
+				}
+			}
+
+			bx += vx
+			by += vy
+			if by < 0 {
+				by, vy = -by, -vy
+			} else if by > float64(h-1) {
+				by, vy = 2*float64(h-1)-by, -vy
+			}
+
+			// A ball reaching a paddle column is returned if the paddle
+			// covers it, with spin depending on where it hit.
+			for i, x := range [2]float64{1, float64(w - 2)} {
+				if (i == 0 && vx < 0 && bx <= x) || (i == 1 && vx > 0 && bx >= x) {
+					off := math.Round(by) - float64(paddles[i])
+					if off >= 0 && off < pongPaddle {
+						bx, vx = 2*x-bx, -vx
+						vy = math.Max(-1, math.Min(1, vy+0.3*(off-1)))
+						judge(1 - i)
+					}
+				}
+			}
+			if bx < 0 {
+				serve(1)
+			} else if bx > float64(w-1) {
+				serve(-1)
+			}
+		}
+
+		var f framebuf.Frame
+		for y := 0; y < h; y += 2 {
+			f.SetPixel(w/2, y, true) // net
+		}
+		for i, x := range [2]int{0, w - 1} {
+			for y := paddles[i]; y < paddles[i]+pongPaddle; y++ {
+				f.SetPixel(x, y, true)
+			}
+		}
+		f.SetPixel(int(math.Round(bx)), int(math.Round(by)), true)
+		write(pd, f, a.Params)
+	}
+}
@@ -1,13 +1,62 @@
 package animation
 
-import "github.com/swilcox/led-kurokku-go/widget"
+import (
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
 
 // Registry maps animation type names to constructors.
-var Registry = map[string]func() widget.Widget{
-	"rain":    func() widget.Widget { return &Rain{} },
-	"static":  func() widget.Widget { return &Static{} },
-	"bounce":  func() widget.Widget { return &Bounce{} },
-	"sine":    func() widget.Widget { return &Sine{} },
-	"scanner": func() widget.Widget { return &Scanner{} },
-	"life":    func() widget.Widget { return &Life{} },
+var Registry = map[string]func(widget.AnimParams) widget.Widget{
+	"rain":    func(p widget.AnimParams) widget.Widget { return &Rain{Params: p} },
+	"static":  func(p widget.AnimParams) widget.Widget { return &Static{Params: p} },
+	"bounce":  func(p widget.AnimParams) widget.Widget { return &Bounce{Params: p} },
+	"sine":    func(p widget.AnimParams) widget.Widget { return &Sine{Params: p} },
+	"scanner": func(p widget.AnimParams) widget.Widget { return &Scanner{Params: p} },
+	"life":    func(p widget.AnimParams) widget.Widget { return &Life{Params: p} },
+
+	"fire":      func(p widget.AnimParams) widget.Widget { return &Fire{Params: p} },
+	"starfield": func(p widget.AnimParams) widget.Widget { return &Starfield{Params: p} },
+	"plasma":    func(p widget.AnimParams) widget.Widget { return &Plasma{Params: p} },
+	"fireworks": func(p widget.AnimParams) widget.Widget { return &Fireworks{Params: p} },
+	"snake":     func(p widget.AnimParams) widget.Widget { return &Snake{Params: p} },
+	"pong":      func(p widget.AnimParams) widget.Widget { return &Pong{Params: p} },
+	"tetris":    func(p widget.AnimParams) widget.Widget { return &Tetris{Params: p} },
+}
+
+// write sends f to the display, inverted when the params ask for it.
+func write(pd display.PixelDisplay, f framebuf.Frame, p widget.AnimParams) {
+	if p.Invert {
+		for x := range f {
+			f[x] = ^f[x]
+		}
+	}
+	pd.WriteFramebuffer(f.Bytes())
+}
+
+// size returns the area an animation may draw in: the display's dimensions,
+// capped to the 32x8 framebuffer.
+func size(pd display.PixelDisplay) (w, h int) {
+	w, h = pd.Width(), pd.Height()
+	if w <= 0 || w > 32 {
+		w = 32
+	}
+	if h <= 0 || h > 8 {
+		h = 8
+	}
+	return w, h
+}
+
+// bayer4 is a 4x4 ordered-dither matrix.
+var bayer4 = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// dither reports whether a pixel of intensity v (0..1) is lit under 4x4
+// ordered dithering, giving the matrix apparent grey levels.
+func dither(x, y int, v float64) bool {
+	return v > (bayer4[y%4][x%4]+0.5)/16
 }
@@ -2,20 +2,25 @@ package animation
 
 import (
 	"context"
-	"math/rand"
 	"time"
 
 	"github.com/swilcox/led-kurokku-go/display"
 	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
 )
 
-// Rain simulates raindrops falling down the display.
-type Rain struct{}
+// Rain simulates raindrops falling down the display. Density is the chance
+// per tick that an idle column starts a drop (default 0.05).
+type Rain struct {
+	Params widget.AnimParams
+}
 
 func (r *Rain) Name() string { return "rain" }
 
 func (r *Rain) Run(ctx context.Context, disp display.Display) error {
 	pd := disp.(display.PixelDisplay)
+	rng := r.Params.Rand()
+	density := r.Params.DensityOr(0.05)
 
 	// Each column has a drop position (-1 = inactive)
 	var drops [32]int
@@ -23,7 +28,7 @@ func (r *Rain) Run(ctx context.Context, disp display.Display) error {
 		drops[i] = -1
 	}
 
-	ticker := time.NewTicker(80 * time.Millisecond)
+	ticker := time.NewTicker(r.Params.Interval(80 * time.Millisecond))
 	defer ticker.Stop()
 
 	for {
@@ -35,7 +40,7 @@ func (r *Rain) Run(ctx context.Context, disp display.Display) error {
 
 		// Randomly spawn new drops
 		for x := 0; x < 32; x++ {
-			if drops[x] < 0 && rand.Intn(20) == 0 {
+			if drops[x] < 0 && rng.Float64() < density {
 				drops[x] = 0
 			}
 		}
@@ -59,6 +64,6 @@ func (r *Rain) Run(ctx context.Context, disp display.Display) error {
 			}
 		}
 
-		pd.WriteFramebuffer(f.Bytes())
+		write(pd, f, r.Params)
 	}
 }
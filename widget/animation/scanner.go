@@ -6,11 +6,15 @@ import (
 
 	"github.com/swilcox/led-kurokku-go/display"
 	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
 )
 
 // Scanner sweeps a bright column back and forth across the display (KITT-style),
-// with a directional trail that fades away from the head.
-type Scanner struct{}
+// with a directional trail that fades away from the head. Density does not
+// apply.
+type Scanner struct {
+	Params widget.AnimParams
+}
 
 func (s *Scanner) Name() string { return "scanner" }
 
@@ -20,7 +24,7 @@ func (s *Scanner) Run(ctx context.Context, disp display.Display) error {
 	pos := 0
 	dir := 1
 
-	ticker := time.NewTicker(40 * time.Millisecond)
+	ticker := time.NewTicker(s.Params.Interval(40 * time.Millisecond))
 	defer ticker.Stop()
 
 	for {
@@ -47,7 +51,7 @@ func (s *Scanner) Run(ctx context.Context, disp display.Display) error {
 			f[t3] = 0x11 // very sparse
 		}
 
-		pd.WriteFramebuffer(f.Bytes())
+		write(pd, f, s.Params)
 
 		pos += dir
 		if pos >= 31 {
@@ -7,10 +7,15 @@ import (
 
 	"github.com/swilcox/led-kurokku-go/display"
 	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
 )
 
-// Sine displays a scrolling sine wave across the display.
-type Sine struct{}
+// Sine displays a scrolling sine wave across the display. Density sets the
+// number of wave periods across the 32 columns (density 1 = 4 periods,
+// default ~1.8).
+type Sine struct {
+	Params widget.AnimParams
+}
 
 func (s *Sine) Name() string { return "sine" }
 
@@ -18,8 +23,9 @@ func (s *Sine) Run(ctx context.Context, disp display.Display) error {
 	pd := disp.(display.PixelDisplay)
 
 	var phase float64
+	k := s.Params.DensityOr(0.35/(8*math.Pi/32)) * 8 * math.Pi / 32 // radians per column
 
-	ticker := time.NewTicker(50 * time.Millisecond)
+	ticker := time.NewTicker(s.Params.Interval(50 * time.Millisecond))
 	defer ticker.Stop()
 
 	for {
@@ -32,10 +38,10 @@ func (s *Sine) Run(ctx context.Context, disp display.Display) error {
 		var f framebuf.Frame
 		for x := 0; x < 32; x++ {
 			// Scale to full 8-row height (0-7).
-			y := int(math.Round(3.5 + 3.5*math.Sin(phase+float64(x)*0.35)))
+			y := int(math.Round(3.5 + 3.5*math.Sin(phase+float64(x)*k)))
 			f.SetPixel(x, y, true)
 		}
-		pd.WriteFramebuffer(f.Bytes())
+		write(pd, f, s.Params)
 		phase += 0.2
 	}
 }
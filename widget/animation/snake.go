@@ -0,0 +1,175 @@
+package animation
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Snake plays the game on its own: it takes the shortest path to the food
+// when doing so leaves it room to move, and otherwise heads for the largest
+// open area. When it gets stuck, or fills the board, the last board blinks
+// and a new game starts. Density does not apply; Seed sets food placement.
+type Snake struct {
+	Params widget.AnimParams
+}
+
+func (a *Snake) Name() string { return "snake" }
+
+type point struct{ x, y int }
+
+var directions = [4]point{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+
+func (a *Snake) Run(ctx context.Context, disp display.Display) error {
+	pd := disp.(display.PixelDisplay)
+	w, h := size(pd)
+	rng := a.Params.Rand()
+
+	var body []point // head first
+	var food point
+	placeFood := func() bool {
+		occupied := make(map[point]bool, len(body))
+		for _, p := range body {
+			occupied[p] = true
+		}
+		var free []point
+		for x := 0; x < w; x++ {
+			for y := 0; y < h; y++ {
+				if !occupied[point{x, y}] {
+					free = append(free, point{x, y})
+				}
+			}
+		}
+		if len(free) == 0 {
+			return false
+		}
+		food = free[rng.Intn(len(free))]
+		return true
+	}
+	reset := func() {
+		body = []point{{2, h / 2}, {1, h / 2}, {0, h / 2}}
+		placeFood()
+	}
+	reset()
+
+	gameOver := 0 // frames left of the game-over blink
+	tick := 0
+
+	ticker := time.NewTicker(a.Params.Interval(80 * time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		tick++
+
+		if gameOver > 0 {
+			gameOver--
+			if gameOver == 0 {
+				reset()
+			}
+		} else if next, ok := snakeMove(body, food, w, h); ok {
+			body = append([]point{next}, body...)
+			if next == food {
+				if !placeFood() {
+					gameOver = 20
+				}
+			} else {
+				body = body[:len(body)-1]
+			}
+		} else {
+			gameOver = 20
+		}
+
+		var f framebuf.Frame
+		if gameOver == 0 || gameOver%4 < 2 {
+			for _, p := range body {
+				f.SetPixel(p.x, p.y, true)
+			}
+		}
+		if gameOver == 0 && tick%2 == 0 {
+			f.SetPixel(food.x, food.y, true)
+		}
+		write(pd, f, a.Params)
+	}
+}
+
+// snakeMove picks the snake's next head position on a w x h board with
+// walls, or reports false when every move is fatal.
+func snakeMove(body []point, food point, w, h int) (point, bool) {
+	// The tail moves out of the way on the next step, so it is not an obstacle.
+	blocked := make(map[point]bool, len(body))
+	for _, p := range body[:len(body)-1] {
+		blocked[p] = true
+	}
+	free := func(p point) bool {
+		return p.x >= 0 && p.x < w && p.y >= 0 && p.y < h && !blocked[p]
+	}
+
+	// Breadth-first search from the head to the food.
+	head := body[0]
+	prev := map[point]point{head: head}
+	queue := []point{head}
+	for len(queue) > 0 {
+		if _, found := prev[food]; found {
+			break
+		}
+		cur := queue[0]
+		queue = queue[1:]
+		for _, d := range directions {
+			n := point{cur.x + d.x, cur.y + d.y}
+			if _, seen := prev[n]; seen || !free(n) {
+				continue
+			}
+			prev[n] = cur
+			queue = append(queue, n)
+		}
+	}
+	if _, found := prev[food]; found {
+		step := food
+		for prev[step] != head {
+			step = prev[step]
+		}
+		// Only take the short way if the snake still fits where it ends up.
+		if openArea(step, free) >= len(body) {
+			return step, true
+		}
+	}
+
+	// No safe path: move to the neighbour with the most room.
+	best, bestArea := point{}, 0
+	for _, d := range directions {
+		n := point{head.x + d.x, head.y + d.y}
+		if !free(n) {
+			continue
+		}
+		if a := openArea(n, free); a > bestArea {
+			best, bestArea = n, a
+		}
+	}
+	return best, bestArea > 0
+}
+
+// openArea counts the cells reachable from start.
+func openArea(start point, free func(point) bool) int {
+	seen := map[point]bool{start: true}
+	stack := []point{start}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, d := range directions {
+			n := point{cur.x + d.x, cur.y + d.y}
+			if !seen[n] && free(n) {
+				seen[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+	return len(seen)
+}
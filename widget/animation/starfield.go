@@ -0,0 +1,70 @@
+package animation
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Starfield scrolls stars right to left on three parallax layers; nearer
+// stars move faster and draw as two-pixel streaks. Density is the fraction
+// of pixels occupied by stars (default 0.05).
+type Starfield struct {
+	Params widget.AnimParams
+}
+
+func (a *Starfield) Name() string { return "starfield" }
+
+// Columns moved per frame by each layer, far to near.
+var starLayers = [3]float64{0.25, 0.5, 1}
+
+type star struct {
+	x     float64
+	y     int
+	layer int
+}
+
+func (a *Starfield) Run(ctx context.Context, disp display.Display) error {
+	pd := disp.(display.PixelDisplay)
+	w, h := size(pd)
+	rng := a.Params.Rand()
+
+	n := max(1, int(math.Round(a.Params.DensityOr(0.05)*float64(w*h))))
+	stars := make([]star, n)
+	for i := range stars {
+		stars[i] = star{x: rng.Float64() * float64(w), y: rng.Intn(h), layer: rng.Intn(len(starLayers))}
+	}
+
+	ticker := time.NewTicker(a.Params.Interval(50 * time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		var f framebuf.Frame
+		for i := range stars {
+			s := &stars[i]
+			s.x -= starLayers[s.layer]
+			if s.x < 0 {
+				// Re-enter at the right edge on a new row and layer.
+				s.x += float64(w)
+				s.y = rng.Intn(h)
+				s.layer = rng.Intn(len(starLayers))
+			}
+			x := int(s.x)
+			f.SetPixel(x, s.y, true)
+			if s.layer == len(starLayers)-1 && x+1 < w {
+				f.SetPixel(x+1, s.y, true)
+			}
+		}
+		write(pd, f, a.Params)
+	}
+}
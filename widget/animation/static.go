@@ -2,22 +2,27 @@ package animation
 
 import (
 	"context"
-	"math/rand"
 	"time"
 
 	"github.com/swilcox/led-kurokku-go/display"
 	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
 )
 
-// Static displays TV-static random pixel noise.
-type Static struct{}
+// Static displays TV-static random pixel noise. Density is the fraction of
+// pixels lit in each frame (default 0.5).
+type Static struct {
+	Params widget.AnimParams
+}
 
 func (r *Static) Name() string { return "static" }
 
 func (r *Static) Run(ctx context.Context, disp display.Display) error {
 	pd := disp.(display.PixelDisplay)
+	rng := r.Params.Rand()
+	density := r.Params.DensityOr(0.5)
 
-	ticker := time.NewTicker(50 * time.Millisecond)
+	ticker := time.NewTicker(r.Params.Interval(50 * time.Millisecond))
 	defer ticker.Stop()
 
 	for {
@@ -29,8 +34,10 @@ func (r *Static) Run(ctx context.Context, disp display.Display) error {
 
 		var f framebuf.Frame
 		for x := 0; x < 32; x++ {
-			f[x] = byte(rand.Intn(256))
+			for y := 0; y < 8; y++ {
+				f.SetPixel(x, y, rng.Float64() < density)
+			}
 		}
-		pd.WriteFramebuffer(f.Bytes())
+		write(pd, f, r.Params)
 	}
 }
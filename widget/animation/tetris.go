@@ -0,0 +1,275 @@
+package animation
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Tetris plays itself on a well turned on its side: pieces fall from the
+// right edge towards the left, so the well is as deep as the display is wide
+// and as wide as it is tall. Each piece is dropped at the rotation and
+// position that scores best on height, holes, bumpiness and lines cleared.
+// When the stack tops out the board is cleared and a new game starts.
+// Density does not apply; Seed sets the piece sequence.
+type Tetris struct {
+	Params widget.AnimParams
+}
+
+func (a *Tetris) Name() string { return "tetris" }
+
+// tetrominoes in their spawn rotation, as (column, row) cells with row 0 at
+// the bottom of the well.
+var tetrominoes = [7][4]point{
+	{{0, 0}, {1, 0}, {2, 0}, {3, 0}}, // I
+	{{0, 0}, {1, 0}, {0, 1}, {1, 1}}, // O
+	{{0, 0}, {1, 0}, {2, 0}, {1, 1}}, // T
+	{{0, 0}, {1, 0}, {1, 1}, {2, 1}}, // S
+	{{1, 0}, {2, 0}, {0, 1}, {1, 1}}, // Z
+	{{0, 0}, {1, 0}, {2, 0}, {0, 1}}, // J
+	{{0, 0}, {1, 0}, {2, 0}, {2, 1}}, // L
+}
+
+// tetrisRotations lists the distinct rotations of each tetromino, each
+// shifted so its lowest column and row are 0.
+var tetrisRotations = func() [7][][4]point {
+	var all [7][][4]point
+	for i, cells := range tetrominoes {
+		seen := map[[4]point]bool{}
+		for r := 0; r < 4; r++ {
+			norm := normalizePiece(cells)
+			if !seen[norm] {
+				seen[norm] = true
+				all[i] = append(all[i], norm)
+			}
+			for j, c := range cells {
+				cells[j] = point{c.y, -c.x} // quarter turn
+			}
+		}
+	}
+	return all
+}()
+
+func normalizePiece(cells [4]point) [4]point {
+	minX, minY := cells[0].x, cells[0].y
+	for _, c := range cells {
+		minX, minY = min(minX, c.x), min(minY, c.y)
+	}
+	// Sort the cells so equal shapes compare equal.
+	var out [4]point
+	for i, c := range cells {
+		out[i] = point{c.x - minX, c.y - minY}
+	}
+	for i := 1; i < 4; i++ {
+		for j := i; j > 0 && (out[j].y < out[j-1].y || out[j].y == out[j-1].y && out[j].x < out[j-1].x); j-- {
+			out[j], out[j-1] = out[j-1], out[j]
+		}
+	}
+	return out
+}
+
+// tetrisBoard is the well: cols wide, rows deep, indexed [row][col].
+type tetrisBoard struct {
+	cols, rows int
+	cells      [][]bool
+}
+
+func newTetrisBoard(cols, rows int) *tetrisBoard {
+	b := &tetrisBoard{cols: cols, rows: rows, cells: make([][]bool, rows)}
+	for r := range b.cells {
+		b.cells[r] = make([]bool, cols)
+	}
+	return b
+}
+
+func (b *tetrisBoard) fits(piece [4]point, col, row int) bool {
+	for _, c := range piece {
+		x, y := col+c.x, row+c.y
+		if x < 0 || x >= b.cols || y < 0 {
+			return false
+		}
+		if y < b.rows && b.cells[y][x] {
+			return false
+		}
+	}
+	return true
+}
+
+// landing returns the row where piece comes to rest when dropped in col.
+func (b *tetrisBoard) landing(piece [4]point, col int) int {
+	row := b.rows
+	for row > 0 && b.fits(piece, col, row-1) {
+		row--
+	}
+	return row
+}
+
+// place locks the piece in and clears full rows, returning the rows cleared,
+// or -1 if the piece sticks out of the top.
+func (b *tetrisBoard) place(piece [4]point, col, row int) int {
+	for _, c := range piece {
+		if row+c.y >= b.rows {
+			return -1
+		}
+		b.cells[row+c.y][col+c.x] = true
+	}
+	cleared := 0
+	for r := 0; r < b.rows; {
+		full := true
+		for _, on := range b.cells[r] {
+			full = full && on
+		}
+		if !full {
+			r++
+			continue
+		}
+		copy(b.cells[r:], b.cells[r+1:])
+		b.cells[b.rows-1] = make([]bool, b.cols)
+		cleared++
+	}
+	return cleared
+}
+
+func (b *tetrisBoard) clone() *tetrisBoard {
+	c := newTetrisBoard(b.cols, b.rows)
+	for r := range b.cells {
+		copy(c.cells[r], b.cells[r])
+	}
+	return c
+}
+
+// score rates a board after a placement, using the weights of the well-known
+// four-feature Tetris AI.
+func (b *tetrisBoard) score(cleared int) float64 {
+	var aggregate, holes, bumpiness int
+	prev := -1
+	for x := 0; x < b.cols; x++ {
+		height := 0
+		for y := b.rows - 1; y >= 0; y-- {
+			if b.cells[y][x] {
+				if height == 0 {
+					height = y + 1
+				}
+			} else if height > 0 {
+				holes++
+			}
+		}
+		aggregate += height
+		if prev >= 0 {
+			bumpiness += abs(height - prev)
+		}
+		prev = height
+	}
+	return -0.51*float64(aggregate) + 0.76*float64(cleared) - 0.36*float64(holes) - 0.18*float64(bumpiness)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// bestDrop picks the rotation and column for piece kind, or false if no
+// placement stays inside the well.
+func (b *tetrisBoard) bestDrop(kind int) (piece [4]point, col int, ok bool) {
+	best := 0.0
+	for _, p := range tetrisRotations[kind] {
+		for c := 0; c < b.cols; c++ {
+			if !b.fits(p, c, b.rows) {
+				continue
+			}
+			trial := b.clone()
+			cleared := trial.place(p, c, trial.landing(p, c))
+			if cleared < 0 {
+				continue
+			}
+			if s := trial.score(cleared); !ok || s > best {
+				piece, col, best, ok = p, c, s, true
+			}
+		}
+	}
+	return piece, col, ok
+}
+
+// tetrisBag deals pieces in shuffled sets of seven.
+type tetrisBag struct {
+	rng  *rand.Rand
+	next []int
+}
+
+func (g *tetrisBag) draw() int {
+	if len(g.next) == 0 {
+		g.next = g.rng.Perm(len(tetrominoes))
+	}
+	k := g.next[0]
+	g.next = g.next[1:]
+	return k
+}
+
+func (a *Tetris) Run(ctx context.Context, disp display.Display) error {
+	pd := disp.(display.PixelDisplay)
+	w, h := size(pd)
+	bag := &tetrisBag{rng: a.Params.Rand()}
+
+	board := newTetrisBoard(h, w)
+	var piece [4]point
+	var col, row int
+	falling := false
+	gameOver := 0 // frames left of the game-over blink
+
+	ticker := time.NewTicker(a.Params.Interval(40 * time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		switch {
+		case gameOver > 0:
+			gameOver--
+			if gameOver == 0 {
+				board = newTetrisBoard(h, w)
+			}
+		case !falling:
+			var ok bool
+			if piece, col, ok = board.bestDrop(bag.draw()); ok {
+				row, falling = board.rows, true
+			} else {
+				gameOver = 20
+			}
+		case board.fits(piece, col, row-1):
+			row--
+		default:
+			if board.place(piece, col, row) < 0 {
+				gameOver = 20
+			}
+			falling = false
+		}
+
+		// Well row r is display column r; well column c is display row c.
+		var f framebuf.Frame
+		if gameOver%4 < 2 {
+			for r := 0; r < board.rows; r++ {
+				for c := 0; c < board.cols; c++ {
+					if board.cells[r][c] {
+						f.SetPixel(r, c, true)
+					}
+				}
+			}
+		}
+		if falling && gameOver == 0 {
+			for _, p := range piece {
+				f.SetPixel(row+p.y, col+p.x, true)
+			}
+		}
+		write(pd, f, a.Params)
+	}
+}
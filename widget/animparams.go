@@ -0,0 +1,87 @@
+package widget
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// AnimParams tunes a procedural animation. The zero value gives each
+// animation its built-in behaviour.
+type AnimParams struct {
+	Speed   float64 // tick rate multiplier, 0.1..10; 0 means 1
+	Density float64 // 0..1, meaning depends on the animation; 0 uses its default
+	Seed    int64   // non-zero makes the random playback repeatable
+	Invert  bool    // swap lit and unlit pixels/segments
+}
+
+// ParseAnimParams validates the "params" object of an animation widget.
+// Accepted keys are speed, density, seed and invert.
+func ParseAnimParams(m map[string]interface{}) (AnimParams, error) {
+	var p AnimParams
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // report errors deterministically
+	for _, k := range keys {
+		v := m[k]
+		switch k {
+		case "speed":
+			f, ok := v.(float64)
+			if !ok || f < 0.1 || f > 10 {
+				return AnimParams{}, fmt.Errorf("params.speed must be a number between 0.1 and 10, got %v", v)
+			}
+			p.Speed = f
+		case "density":
+			f, ok := v.(float64)
+			if !ok || f <= 0 || f > 1 {
+				return AnimParams{}, fmt.Errorf("params.density must be a number in (0, 1], got %v", v)
+			}
+			p.Density = f
+		case "seed":
+			f, ok := v.(float64)
+			if !ok || f != math.Trunc(f) || math.Abs(f) > 1<<53 {
+				return AnimParams{}, fmt.Errorf("params.seed must be an integer, got %v", v)
+			}
+			p.Seed = int64(f)
+		case "invert":
+			b, ok := v.(bool)
+			if !ok {
+				return AnimParams{}, fmt.Errorf("params.invert must be a boolean, got %v", v)
+			}
+			p.Invert = b
+		default:
+			return AnimParams{}, fmt.Errorf("unknown animation param %q", k)
+		}
+	}
+	return p, nil
+}
+
+// Interval scales an animation's base tick interval by Speed.
+func (p AnimParams) Interval(base time.Duration) time.Duration {
+	if p.Speed == 0 {
+		return base
+	}
+	return time.Duration(float64(base) / p.Speed)
+}
+
+// DensityOr returns Density, or def when it is unset.
+func (p AnimParams) DensityOr(def float64) float64 {
+	if p.Density == 0 {
+		return def
+	}
+	return p.Density
+}
+
+// Rand returns the random source for one run: seeded from Seed when set,
+// otherwise from the clock.
+func (p AnimParams) Rand() *rand.Rand {
+	seed := p.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
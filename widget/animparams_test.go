@@ -0,0 +1,51 @@
+package widget_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+func TestParseAnimParams(t *testing.T) {
+	p, err := widget.ParseAnimParams(map[string]interface{}{
+		"speed": 2.0, "density": 0.25, "seed": 99.0, "invert": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := widget.AnimParams{Speed: 2, Density: 0.25, Seed: 99, Invert: true}
+	if p != want {
+		t.Errorf("got %+v, want %+v", p, want)
+	}
+	if got := p.Interval(100 * time.Millisecond); got != 50*time.Millisecond {
+		t.Errorf("Interval = %v, want 50ms", got)
+	}
+	if got := p.DensityOr(0.5); got != 0.25 {
+		t.Errorf("DensityOr = %v, want 0.25", got)
+	}
+
+	var zero widget.AnimParams
+	if zero.Interval(80*time.Millisecond) != 80*time.Millisecond || zero.DensityOr(0.5) != 0.5 {
+		t.Error("zero params should keep the animation defaults")
+	}
+	if a, b := p.Rand().Int63(), p.Rand().Int63(); a != b {
+		t.Error("seeded Rand should be repeatable")
+	}
+
+	bad := []map[string]interface{}{
+		{"speed": 0.0},
+		{"speed": 20.0},
+		{"speed": "fast"},
+		{"density": 1.5},
+		{"density": 0.0},
+		{"seed": 1.5},
+		{"invert": "yes"},
+		{"colour": "red"},
+	}
+	for _, m := range bad {
+		if _, err := widget.ParseAnimParams(m); err == nil {
+			t.Errorf("ParseAnimParams(%v): expected error", m)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package widget
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+)
+
+// Blink wraps disp so that whatever a widget writes to it blinks on and off
+// at the given interval. The returned stop function halts blinking, leaves the
+// last written content visible, and must be called before the wrapper is
+// discarded. Pixel and segment displays are both supported; any other display
+// is returned unchanged.
+func Blink(ctx context.Context, disp display.Display, interval time.Duration) (display.Display, func()) {
+	if interval <= 0 {
+		interval = 250 * time.Millisecond
+	}
+
+	var b blinker
+	var wrapped display.Display
+	switch d := disp.(type) {
+	case display.PixelDisplay:
+		pb := &blinkPixel{PixelDisplay: d}
+		b, wrapped = pb, pb
+	case display.SegmentDisplay:
+		sb := &blinkSegment{SegmentDisplay: d}
+		b, wrapped = sb, sb
+	default:
+		return disp, func() {}
+	}
+
+	bctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		off := false
+		for {
+			select {
+			case <-bctx.Done():
+				b.show(false)
+				return
+			case <-ticker.C:
+				off = !off
+				b.show(off)
+			}
+		}
+	}()
+
+	return wrapped, func() {
+		cancel()
+		<-done
+	}
+}
+
+// blinker is implemented by the display wrappers returned from Blink.
+type blinker interface {
+	// show redraws the last written content, or a blank screen when off is true.
+	show(off bool)
+}
+
+type blinkPixel struct {
+	display.PixelDisplay
+	mu   sync.Mutex
+	last []byte
+	off  bool
+}
+
+func (b *blinkPixel) WriteFramebuffer(buf []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last = append(b.last[:0], buf...)
+	if b.off {
+		return
+	}
+	b.PixelDisplay.WriteFramebuffer(buf)
+}
+
+func (b *blinkPixel) show(off bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.last == nil || b.off == off {
+		b.off = off
+		return
+	}
+	b.off = off
+	if off {
+		b.PixelDisplay.WriteFramebuffer(make([]byte, len(b.last)))
+	} else {
+		b.PixelDisplay.WriteFramebuffer(b.last)
+	}
+}
+
+type blinkSegment struct {
+	display.SegmentDisplay
+	mu    sync.Mutex
+	last  []uint16
+	colon bool
+	off   bool
+}
+
+func (b *blinkSegment) WriteSegments(segments []uint16, colon bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last = append(b.last[:0], segments...)
+	b.colon = colon
+	if b.off {
+		return
+	}
+	b.SegmentDisplay.WriteSegments(segments, colon)
+}
+
+func (b *blinkSegment) show(off bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.last == nil || b.off == off {
+		b.off = off
+		return
+	}
+	b.off = off
+	if off {
+		b.SegmentDisplay.WriteSegments(make([]uint16, len(b.last)), false)
+	} else {
+		b.SegmentDisplay.WriteSegments(b.last, b.colon)
+	}
+}
@@ -0,0 +1,155 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/ical"
+)
+
+// CalendarSource loads events from a local .ics file or an http(s) URL and
+// caches them for Refresh.
+type CalendarSource struct {
+	Source   string
+	Location *time.Location // default time.Local
+	Refresh  time.Duration  // default 15m
+	Client   *http.Client
+	NowFunc  func() time.Time
+
+	mu      sync.Mutex
+	events  []ical.Event
+	fetched time.Time
+}
+
+func (c *CalendarSource) now() time.Time {
+	if c.NowFunc != nil {
+		return c.NowFunc()
+	}
+	return time.Now()
+}
+
+// Events returns the cached events, reloading them when older than Refresh.
+// On a failed reload the previous events are kept and the error is returned.
+func (c *CalendarSource) Events(ctx context.Context) ([]ical.Event, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	refresh := c.Refresh
+	if refresh == 0 {
+		refresh = 15 * time.Minute
+	}
+	if !c.fetched.IsZero() && c.now().Sub(c.fetched) < refresh {
+		return c.events, nil
+	}
+
+	events, err := c.load(ctx)
+	if err != nil {
+		return c.events, err
+	}
+	c.events, c.fetched = events, c.now()
+	return events, nil
+}
+
+func (c *CalendarSource) load(ctx context.Context) ([]ical.Event, error) {
+	var r io.ReadCloser
+	if strings.HasPrefix(c.Source, "http://") || strings.HasPrefix(c.Source, "https://") {
+		client := c.Client
+		if client == nil {
+			client = &http.Client{Timeout: 10 * time.Second}
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.Source, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetching calendar: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("fetching calendar: HTTP %d", resp.StatusCode)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(c.Source)
+		if err != nil {
+			return nil, fmt.Errorf("opening calendar: %w", err)
+		}
+		r = f
+	}
+	defer r.Close()
+
+	loc := c.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	// Even a busy calendar is well under a few MB.
+	return ical.Parse(io.LimitReader(r, 16<<20), loc)
+}
+
+// Calendar shows the next upcoming event with a countdown ("Standup in 12m"),
+// or the current event ("Now Standup") when ShowCurrent is set. It returns
+// immediately — skipping itself — when nothing falls within LookAhead.
+type Calendar struct {
+	Source      *CalendarSource
+	LookAhead   time.Duration // default 24h
+	ShowCurrent bool
+	ScrollSpeed time.Duration
+	NowFunc     func() time.Time
+}
+
+func (c *Calendar) now() time.Time {
+	if c.NowFunc != nil {
+		return c.NowFunc()
+	}
+	return time.Now()
+}
+
+func (c *Calendar) Name() string { return "calendar" }
+
+func (c *Calendar) Run(ctx context.Context, disp display.Display) error {
+	if _, ok := c.Text(ctx); !ok {
+		return nil
+	}
+	return RunWatched(ctx, 30*time.Second, func() string {
+		text, _ := c.Text(ctx)
+		return text
+	}, func(ctx context.Context, text string) error {
+		if text == "" {
+			return nil
+		}
+		m := &Message{Text: text, ScrollSpeed: c.ScrollSpeed, Repeats: -1}
+		return m.Run(ctx, disp)
+	})
+}
+
+// Text returns the text to display and false when there is nothing to show.
+func (c *Calendar) Text(ctx context.Context) (string, bool) {
+	events, err := c.Source.Events(ctx)
+	if err != nil {
+		log.Printf("calendar %s: %v", c.Source.Source, err)
+	}
+	lookAhead := c.LookAhead
+	if lookAhead == 0 {
+		lookAhead = 24 * time.Hour
+	}
+
+	now := c.now()
+	for _, occ := range ical.Occurrences(events, now, now.Add(lookAhead)) {
+		if occ.Start.After(now) {
+			return fmt.Sprintf("%s in %s", occ.Summary, compactDuration(occ.Start.Sub(now)+time.Minute-1, 5)), true
+		}
+		if c.ShowCurrent && !occ.AllDay {
+			return "Now " + occ.Summary, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,141 @@
+package widget_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+const calendarICS = `BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:standup
+SUMMARY:Standup
+DTSTART:20260105T150000Z
+DTEND:20260105T151500Z
+RRULE:FREQ=DAILY
+END:VEVENT
+END:VCALENDAR
+`
+
+func writeCalendar(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cal.ics")
+	if err := os.WriteFile(path, []byte(calendarICS), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCalendar_Countdown(t *testing.T) {
+	now := time.Date(2026, 1, 6, 14, 48, 30, 0, time.UTC)
+	c := &widget.Calendar{
+		Source:  &widget.CalendarSource{Source: writeCalendar(t), Location: time.UTC},
+		NowFunc: func() time.Time { return now },
+	}
+	text, ok := c.Text(context.Background())
+	if !ok || text != "Standup in 12m" {
+		t.Errorf("got (%q, %v), want (\"Standup in 12m\", true)", text, ok)
+	}
+}
+
+func TestCalendar_ShowCurrent(t *testing.T) {
+	now := time.Date(2026, 1, 6, 15, 5, 0, 0, time.UTC)
+	src := &widget.CalendarSource{Source: writeCalendar(t), Location: time.UTC}
+
+	c := &widget.Calendar{Source: src, ShowCurrent: true, NowFunc: func() time.Time { return now }}
+	if text, _ := c.Text(context.Background()); text != "Now Standup" {
+		t.Errorf("text = %q, want %q", text, "Now Standup")
+	}
+
+	c.ShowCurrent = false
+	if text, _ := c.Text(context.Background()); text != "Standup in 23h" {
+		t.Errorf("text = %q, want next day's occurrence", text)
+	}
+}
+
+func TestCalendar_SkipsOutsideLookAhead(t *testing.T) {
+	now := time.Date(2026, 1, 6, 10, 0, 0, 0, time.UTC)
+	c := &widget.Calendar{
+		Source:    &widget.CalendarSource{Source: writeCalendar(t), Location: time.UTC},
+		LookAhead: time.Hour,
+		NowFunc:   func() time.Time { return now },
+	}
+	if _, ok := c.Text(context.Background()); ok {
+		t.Error("expected nothing to show within look-ahead")
+	}
+
+	spy := &testutil.SpyDisplay{}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := c.Run(ctx, spy); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) > 500*time.Millisecond || len(spy.Frames) != 0 {
+		t.Error("expected Run to return immediately without drawing")
+	}
+}
+
+func TestCalendarSource_HTTPCachesUntilRefresh(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(calendarICS)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC)
+	src := &widget.CalendarSource{
+		Source:   srv.URL,
+		Location: time.UTC,
+		Refresh:  time.Minute,
+		NowFunc:  func() time.Time { return now },
+	}
+	for i := 0; i < 3; i++ {
+		events, err := src.Events(context.Background())
+		if err != nil || len(events) != 1 {
+			t.Fatalf("Events() = %d events, %v", len(events), err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request within refresh window, got %d", requests)
+	}
+	now = now.Add(2 * time.Minute)
+	src.Events(context.Background()) //nolint:errcheck
+	if requests != 2 {
+		t.Errorf("expected reload after refresh elapsed, got %d requests", requests)
+	}
+}
+
+func TestCalendarSource_KeepsEventsOnFailedReload(t *testing.T) {
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(calendarICS)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC)
+	src := &widget.CalendarSource{Source: srv.URL, Refresh: time.Minute, NowFunc: func() time.Time { return now }}
+	src.Events(context.Background()) //nolint:errcheck
+
+	fail = true
+	now = now.Add(time.Hour)
+	events, err := src.Events(context.Background())
+	if err == nil {
+		t.Error("expected error from failed reload")
+	}
+	if len(events) != 1 {
+		t.Errorf("expected previous events to be kept, got %d", len(events))
+	}
+}
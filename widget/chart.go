@@ -0,0 +1,154 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/font"
+	"github.com/swilcox/led-kurokku-go/framebuf"
+)
+
+// SeriesFetcher reads the newest n samples of a Redis time series, oldest first.
+type SeriesFetcher interface {
+	FetchSeries(ctx context.Context, key string, n int) ([]float64, error)
+}
+
+// Chart styles.
+const (
+	ChartSparkline = "sparkline" // connected line (default)
+	ChartBars      = "bars"      // one filled column per sample
+)
+
+// Chart draws the last Samples values of a Redis series across the matrix,
+// scaled to Min/Max or, when unset, to the range of the samples shown.
+type Chart struct {
+	Fetcher   SeriesFetcher
+	Key       string
+	Samples   int // default: the matrix width
+	Style     string
+	Min, Max  *float64
+	ShowValue bool // overlay the latest value at the right edge
+	Prefix    string
+	Unit      string
+	Format    string
+	Interval  time.Duration // re-read interval while displayed, default 5s
+}
+
+func (c *Chart) Name() string { return "chart" }
+
+func (c *Chart) Run(ctx context.Context, disp display.Display) error {
+	pd := disp.(display.PixelDisplay)
+	interval := c.Interval
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+	for {
+		var f framebuf.Frame
+		series, err := c.Series(ctx, pd.Width())
+		if err != nil {
+			log.Printf("chart %s: %v", c.Key, err)
+		}
+		if len(series) == 0 {
+			framebuf.BlitText(&f, "--", (32-len(font.RenderText("--")))/2)
+		} else {
+			f = c.Frame(series)
+		}
+		pd.WriteFramebuffer(f.Bytes())
+		if err := SleepOrCancel(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// Series fetches up to Samples (default width) values.
+func (c *Chart) Series(ctx context.Context, width int) ([]float64, error) {
+	n := c.Samples
+	if n == 0 {
+		n = width
+	}
+	return c.Fetcher.FetchSeries(ctx, c.Key, n)
+}
+
+// Range returns the scale for series: Min/Max when set, otherwise the
+// smallest and largest sample.
+func (c *Chart) Range(series []float64) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range series {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if c.Min != nil {
+		lo = *c.Min
+	}
+	if c.Max != nil {
+		hi = *c.Max
+	}
+	return lo, hi
+}
+
+// Frame draws series, newest at the right. With more samples than columns
+// the oldest are dropped; with fewer the chart is right-aligned.
+func (c *Chart) Frame(series []float64) framebuf.Frame {
+	var f framebuf.Frame
+	width := 32
+	if c.ShowValue && len(series) > 0 {
+		text := FormatValue(c.Prefix, series[len(series)-1], c.Format, c.Unit)
+		w := len(font.RenderText(text))
+		if w < 32 {
+			// Value right-aligned, one blank column between it and the chart.
+			framebuf.BlitText(&f, text, 32-w)
+			width = 32 - w - 1
+		}
+	}
+	if len(series) > width {
+		series = series[len(series)-width:]
+	}
+
+	lo, hi := c.Range(series)
+	level := func(v float64) int { // 0 (bottom) .. 7 (top)
+		if hi <= lo {
+			return 3
+		}
+		frac := math.Max(0, math.Min(1, (v-lo)/(hi-lo)))
+		return int(math.Round(frac * 7))
+	}
+
+	x0 := width - len(series)
+	prev := -1
+	for i, v := range series {
+		x, y := x0+i, 7-level(v)
+		if c.Style == ChartBars {
+			for yy := y; yy <= 7; yy++ {
+				f.SetPixel(x, yy, true)
+			}
+			continue
+		}
+		f.SetPixel(x, y, true)
+		// Join to the previous point with a vertical run so steps stay visible.
+		if prev >= 0 {
+			for yy := min(prev, y) + 1; yy < max(prev, y); yy++ {
+				f.SetPixel(x, yy, true)
+			}
+		}
+		prev = y
+	}
+	return f
+}
+
+// ChartSummary describes series as text for displays that cannot draw it:
+// the latest value followed by the low and high, e.g. "12.3 lo 4 hi 20".
+func ChartSummary(series []float64, prefix, format, unit string) string {
+	if len(series) == 0 {
+		return "--"
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range series {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	return fmt.Sprintf("%s lo %s hi %s",
+		FormatValue(prefix, series[len(series)-1], format, unit),
+		FormatValue("", lo, format, ""), FormatValue("", hi, format, ""))
+}
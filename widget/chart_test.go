@@ -0,0 +1,110 @@
+package widget_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/swilcox/led-kurokku-go/framebuf"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+type seriesFetcher map[string][]float64
+
+func (m seriesFetcher) FetchSeries(_ context.Context, key string, n int) ([]float64, error) {
+	s := m[key]
+	if len(s) > n {
+		s = s[len(s)-n:]
+	}
+	return s, nil
+}
+
+// column returns the rows lit in column x, top to bottom.
+func column(f framebuf.Frame, x int) []int {
+	var rows []int
+	for y := 0; y < 8; y++ {
+		if f.GetPixel(x, y) {
+			rows = append(rows, y)
+		}
+	}
+	return rows
+}
+
+func TestChart_Frame_Sparkline(t *testing.T) {
+	c := &widget.Chart{}
+	f := c.Frame([]float64{0, 10, 5})
+
+	// Three samples are right-aligned: min at the bottom, max at the top.
+	if got := column(f, 29); len(got) != 1 || got[0] != 7 {
+		t.Errorf("column 29 = %v, want [7]", got)
+	}
+	// Rising from the bottom to the top fills the rows in between.
+	if got := column(f, 30); len(got) != 7 || got[0] != 0 {
+		t.Errorf("column 30 = %v, want rows 0..6", got)
+	}
+	if got := column(f, 31); len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("column 31 = %v, want rows 1..3", got)
+	}
+	for x := 0; x < 29; x++ {
+		if got := column(f, x); len(got) != 0 {
+			t.Fatalf("column %d = %v, want blank", x, got)
+		}
+	}
+}
+
+func TestChart_Frame_BarsFixedScale(t *testing.T) {
+	lo, hi := 0.0, 100.0
+	c := &widget.Chart{Style: widget.ChartBars, Min: &lo, Max: &hi}
+	series := make([]float64, 40)
+	series[39] = 100
+	series[38] = 50
+	f := c.Frame(series)
+
+	if got := column(f, 31); len(got) != 8 {
+		t.Errorf("full bar = %v, want 8 rows", got)
+	}
+	if got := column(f, 30); len(got) != 5 || got[0] != 3 {
+		t.Errorf("half bar = %v, want rows 3..7", got)
+	}
+	if got := column(f, 0); len(got) != 1 || got[0] != 7 {
+		t.Errorf("zero bar = %v, want [7]", got)
+	}
+}
+
+func TestChart_Frame_FlatAndShowValue(t *testing.T) {
+	c := &widget.Chart{ShowValue: true, Format: "%.0f"}
+	f := c.Frame([]float64{7, 7, 7, 7})
+
+	// The value "7" is drawn at the right edge, the flat line mid-height
+	// just left of it.
+	if len(column(f, 31)) == 0 {
+		t.Error("expected value text at the right edge")
+	}
+	lit := 0
+	for x := 0; x < 32; x++ {
+		for _, y := range column(f, x) {
+			if y == 4 {
+				lit++
+			}
+		}
+	}
+	if lit < 4 {
+		t.Errorf("flat series drew %d pixels on the middle row, want at least 4", lit)
+	}
+}
+
+func TestChart_Series(t *testing.T) {
+	c := &widget.Chart{Fetcher: seriesFetcher{"load": {1, 2, 3, 4}}, Key: "load", Samples: 2}
+	got, err := c.Series(context.Background(), 32)
+	if err != nil || len(got) != 2 || got[1] != 4 {
+		t.Errorf("Series = %v, %v; want [3 4]", got, err)
+	}
+}
+
+func TestChartSummary(t *testing.T) {
+	if got := widget.ChartSummary([]float64{4, 20, 12.3}, "", "%.1f", ""); got != "12.3 lo 4.0 hi 20.0" {
+		t.Errorf("ChartSummary = %q", got)
+	}
+	if got := widget.ChartSummary(nil, "", "", ""); got != "--" {
+		t.Errorf("empty ChartSummary = %q", got)
+	}
+}
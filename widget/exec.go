@@ -0,0 +1,230 @@
+package widget
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+)
+
+// CommandResult is the outcome of one CommandRunner invocation.
+type CommandResult struct {
+	Output   string
+	ExitCode int // -1 when the command could not be started or timed out
+	Err      error
+}
+
+// Failed reports whether the command exited non-zero or could not run.
+func (r CommandResult) Failed() bool {
+	return r.ExitCode != 0
+}
+
+// CommandRunner runs a local program and caches its output. Runs are
+// serialized: a caller arriving while the command is executing gets the
+// previous result rather than starting a second copy.
+type CommandRunner struct {
+	Command string
+	Args    []string
+	Timeout time.Duration // default 10s
+	// Interval is how long a result is reused before the command runs again.
+	// Zero re-runs it on every call.
+	Interval    time.Duration
+	WholeOutput bool // keep all of stdout instead of the first non-empty line
+	// EnvAllow names the environment variables passed to the command. PATH
+	// is always passed so the command can be resolved.
+	EnvAllow []string
+	NowFunc  func() time.Time
+
+	mu      sync.Mutex
+	running bool
+	done    chan struct{} // closed when the current run ends
+	ran     time.Time
+	last    CommandResult
+}
+
+func (c *CommandRunner) now() time.Time {
+	if c.NowFunc != nil {
+		return c.NowFunc()
+	}
+	return time.Now()
+}
+
+// fresh reports whether the cached result is younger than Interval. c.mu
+// must be held.
+func (c *CommandRunner) fresh() bool {
+	return !c.ran.IsZero() && c.now().Sub(c.ran) < c.Interval
+}
+
+// start marks a run as in progress. c.mu must be held.
+func (c *CommandRunner) start() {
+	c.running = true
+	c.done = make(chan struct{})
+}
+
+// finish ends the run started by start and caches res.
+func (c *CommandRunner) finish(res CommandResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running = false
+	close(c.done)
+	if res.Err != nil {
+		log.Printf("exec %v", res.Err)
+	}
+	c.ran = c.now()
+	c.last = res
+}
+
+// Latest returns the cached result without waiting, and whether there is one
+// yet. A stale result starts a run in the background; done is closed when
+// the run in progress ends, and is nil when there is none.
+func (c *CommandRunner) Latest() (res CommandResult, ok bool, done <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.fresh() && !c.running {
+		c.start()
+		go func() {
+			// Not tied to the widget's context, so rotating away doesn't
+			// cancel the run.
+			c.finish(c.run(context.Background()))
+		}()
+	}
+	if c.running {
+		done = c.done
+	}
+	return c.last, !c.ran.IsZero(), done
+}
+
+func (c *CommandRunner) run(ctx context.Context) CommandResult {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.Command, c.Args...)
+	cmd.Env = c.env()
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	err := cmd.Run()
+	res := CommandResult{Output: c.output(stdout.String())}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		res.ExitCode = -1
+		res.Err = fmt.Errorf("%s: %w", c.Command, ctx.Err())
+	case errors.As(err, &exitErr):
+		res.ExitCode = exitErr.ExitCode()
+		res.Err = fmt.Errorf("%s: %w", c.Command, err)
+	default:
+		res.ExitCode = -1
+		res.Err = fmt.Errorf("%s: %w", c.Command, err)
+	}
+	return res
+}
+
+func (c *CommandRunner) env() []string {
+	env := []string{}
+	if path, ok := os.LookupEnv("PATH"); ok {
+		env = append(env, "PATH="+path)
+	}
+	for _, name := range c.EnvAllow {
+		if name == "PATH" {
+			continue
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return env
+}
+
+func (c *CommandRunner) output(s string) string {
+	if c.WholeOutput {
+		return strings.Join(strings.Fields(s), " ")
+	}
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// Text returns the display text for res and whether the command failed.
+// fallback stands in for empty output, and for the result while ok is false.
+func (c *CommandRunner) Text(res CommandResult, ok bool, fallback string) (string, bool) {
+	text := res.Output
+	if text == "" {
+		text = fallback
+	}
+	if text == "" {
+		text = "--"
+	}
+	return text, ok && res.Failed()
+}
+
+// RunCommand shows the runner's latest output with show, styled by
+// alertStyle when the command failed. It never waits for the command: while
+// a run is in progress the cached output (or fallback) is shown, and the
+// display is redrawn once the run ends.
+func RunCommand(ctx context.Context, disp display.Display, r *CommandRunner, fallback, alertStyle string,
+	restore func(), show func(ctx context.Context, disp display.Display, text string) error) error {
+
+	res, ok, done := r.Latest()
+	for {
+		text, failed := r.Text(res, ok, fallback)
+		render := func(ctx context.Context, disp display.Display) error { return show(ctx, disp, text) }
+		if done == nil {
+			return RunStyled(ctx, disp, failed, alertStyle, restore, render)
+		}
+
+		rctx, cancel := context.WithCancel(ctx)
+		errc := make(chan error, 1)
+		go func() { errc <- RunStyled(rctx, disp, failed, alertStyle, restore, render) }()
+		select {
+		case <-done:
+			cancel()
+			<-errc
+		case err := <-errc:
+			cancel()
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// Redraw with the new result without starting another run.
+		r.mu.Lock()
+		res, ok, done = r.last, !r.ran.IsZero(), nil
+		r.mu.Unlock()
+	}
+}
+
+// Exec displays the output of a local command. A non-zero exit applies
+// AlertStyle; when the command produced no output, FallbackText is shown.
+type Exec struct {
+	Runner            *CommandRunner
+	FallbackText      string
+	AlertStyle        string
+	ScrollSpeed       time.Duration
+	RestoreBrightness func()
+}
+
+func (e *Exec) Name() string { return "exec" }
+
+func (e *Exec) Run(ctx context.Context, disp display.Display) error {
+	return RunCommand(ctx, disp, e.Runner, e.FallbackText, e.AlertStyle, e.RestoreBrightness, func(ctx context.Context, disp display.Display, text string) error {
+		m := &Message{Text: text, ScrollSpeed: e.ScrollSpeed, Repeats: -1}
+		return m.Run(ctx, disp)
+	})
+}
@@ -0,0 +1,185 @@
+package widget_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// latest starts a run through Latest and returns its result once it ends.
+// Runners should set a long Interval so the second call doesn't start
+// another run.
+func latest(t *testing.T, r *widget.CommandRunner) widget.CommandResult {
+	t.Helper()
+	if _, _, done := r.Latest(); done != nil {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("command did not finish")
+		}
+	}
+	res, ok, _ := r.Latest()
+	if !ok {
+		t.Fatal("no result after the run ended")
+	}
+	return res
+}
+
+func TestCommandRunner_FirstLine(t *testing.T) {
+	r := &widget.CommandRunner{Command: "sh", Args: []string{"-c", "echo; echo build ok; echo second"}, Interval: time.Hour}
+	res := latest(t, r)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.Output != "build ok" {
+		t.Errorf("Output = %q, want %q", res.Output, "build ok")
+	}
+}
+
+func TestCommandRunner_WholeOutput(t *testing.T) {
+	r := &widget.CommandRunner{Command: "sh", Args: []string{"-c", "echo a; echo b"}, WholeOutput: true, Interval: time.Hour}
+	if got := latest(t, r).Output; got != "a b" {
+		t.Errorf("Output = %q, want %q", got, "a b")
+	}
+}
+
+func TestCommandRunner_ExitCode(t *testing.T) {
+	r := &widget.CommandRunner{Command: "sh", Args: []string{"-c", "echo failing; exit 3"}, Interval: time.Hour}
+	res := latest(t, r)
+	if res.ExitCode != 3 || !res.Failed() {
+		t.Errorf("ExitCode = %d, want 3", res.ExitCode)
+	}
+	if res.Output != "failing" {
+		t.Errorf("Output = %q, want stdout captured despite failure", res.Output)
+	}
+}
+
+func TestCommandRunner_Timeout(t *testing.T) {
+	r := &widget.CommandRunner{Command: "sleep", Args: []string{"5"}, Timeout: 50 * time.Millisecond, Interval: time.Hour}
+	start := time.Now()
+	res := latest(t, r)
+	if time.Since(start) > 2*time.Second {
+		t.Error("expected command to be killed at timeout")
+	}
+	if res.ExitCode != -1 || res.Err == nil {
+		t.Errorf("expected timeout failure, got %+v", res)
+	}
+}
+
+func TestCommandRunner_EnvAllowList(t *testing.T) {
+	t.Setenv("KUROKKU_ALLOWED", "yes")
+	t.Setenv("KUROKKU_SECRET", "no")
+	r := &widget.CommandRunner{
+		Command:     "sh",
+		Args:        []string{"-c", "echo ${KUROKKU_ALLOWED:-unset} ${KUROKKU_SECRET:-unset}"},
+		EnvAllow:    []string{"KUROKKU_ALLOWED"},
+		WholeOutput: true,
+		Interval:    time.Hour,
+	}
+	if got := latest(t, r).Output; got != "yes unset" {
+		t.Errorf("Output = %q, want %q", got, "yes unset")
+	}
+}
+
+func TestCommandRunner_CachesWithinInterval(t *testing.T) {
+	var mu sync.Mutex
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := &widget.CommandRunner{
+		Command:  "date",
+		Args:     []string{"+%N"},
+		Interval: time.Minute,
+		NowFunc: func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return now
+		},
+	}
+	first := latest(t, r).Output
+	if second, _, done := r.Latest(); second.Output != first || done != nil {
+		t.Errorf("expected cached output %q and no run, got %q", first, second.Output)
+	}
+	mu.Lock()
+	now = now.Add(2 * time.Minute)
+	mu.Unlock()
+	_, _, done := r.Latest()
+	if done == nil {
+		t.Fatal("expected command to re-run after interval elapsed")
+	}
+	<-done
+	if third, _, _ := r.Latest(); third.Output == first {
+		t.Error("expected new output after the re-run")
+	}
+}
+
+func TestCommandRunner_SerializesRuns(t *testing.T) {
+	dir := t.TempDir()
+	r := &widget.CommandRunner{
+		Command:  "sh",
+		Args:     []string{"-c", "echo x >> " + dir + "/runs; sleep 0.2; cat " + dir + "/runs | wc -l"},
+		Interval: time.Hour,
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Latest()
+		}()
+	}
+	wg.Wait()
+	if got := strings.TrimSpace(latest(t, r).Output); got != "1" {
+		t.Errorf("expected exactly one run, got %s", got)
+	}
+}
+
+func TestCommandRunner_TextFallback(t *testing.T) {
+	r := &widget.CommandRunner{Command: "true", Interval: time.Hour}
+	text, failed := r.Text(latest(t, r), true, "idle")
+	if text != "idle" || failed {
+		t.Errorf("Text() = (%q, %v), want (\"idle\", false)", text, failed)
+	}
+	// Before the first run completes there is nothing to flag as failed.
+	if text, failed := r.Text(widget.CommandResult{ExitCode: -1}, false, ""); text != "--" || failed {
+		t.Errorf("Text() without a result = (%q, %v), want (\"--\", false)", text, failed)
+	}
+}
+
+func TestCommandRunner_LatestRunsInBackground(t *testing.T) {
+	r := &widget.CommandRunner{Command: "sh", Args: []string{"-c", "sleep 0.2; echo done"}, Interval: time.Hour}
+	start := time.Now()
+	_, ok, done := r.Latest()
+	if time.Since(start) > 100*time.Millisecond {
+		t.Error("Latest waited for the command")
+	}
+	if ok || done == nil {
+		t.Fatalf("Latest = ok %v, done %v; want a run in progress and no result", ok, done)
+	}
+	<-done
+	if res, ok, done := r.Latest(); !ok || res.Output != "done" || done != nil {
+		t.Errorf("Latest after run = %+v, %v, %v", res, ok, done)
+	}
+}
+
+func TestExec_ShowsFallbackWhileRunning(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	w := &widget.Exec{
+		Runner:       &widget.CommandRunner{Command: "sh", Args: []string{"-c", "sleep 0.2; echo ok"}},
+		FallbackText: "--",
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	w.Run(ctx, spy) //nolint:errcheck
+
+	// One frame for the fallback straight away, then one for the output.
+	if len(spy.Frames) != 2 {
+		t.Fatalf("frames = %d, want 2", len(spy.Frames))
+	}
+	if string(spy.Frames[0]) == string(spy.Frames[1]) {
+		t.Error("expected the display to be redrawn with the command's output")
+	}
+}
@@ -0,0 +1,261 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/feed"
+)
+
+// FeedSource fetches an RSS or Atom feed at most once per Refresh, using
+// conditional GET (ETag / Last-Modified) so unchanged feeds cost a 304.
+type FeedSource struct {
+	URL     string
+	Refresh time.Duration // default 15m
+	Client  *http.Client
+	NowFunc func() time.Time
+
+	mu           sync.Mutex
+	items        []feed.Item
+	etag         string
+	lastModified string
+	fetched      time.Time
+}
+
+func (s *FeedSource) now() time.Time {
+	if s.NowFunc != nil {
+		return s.NowFunc()
+	}
+	return time.Now()
+}
+
+// Items returns the feed's items, refetching when Refresh has elapsed. A
+// failed fetch keeps the previous items and is not retried until the next
+// refresh, so an unreachable feed is not polled every cycle.
+func (s *FeedSource) Items(ctx context.Context) ([]feed.Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refresh := s.Refresh
+	if refresh == 0 {
+		refresh = 15 * time.Minute
+	}
+	if !s.fetched.IsZero() && s.now().Sub(s.fetched) < refresh {
+		return s.items, nil
+	}
+	s.fetched = s.now()
+	return s.items, s.fetch(ctx)
+}
+
+func (s *FeedSource) fetch(ctx context.Context) error {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return err
+	}
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+	if s.lastModified != "" {
+		req.Header.Set("If-Modified-Since", s.lastModified)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("fetching feed: HTTP %d", resp.StatusCode)
+	}
+	items, err := feed.Parse(resp.Body)
+	if err != nil {
+		return err
+	}
+	s.items = items
+	s.etag = resp.Header.Get("ETag")
+	s.lastModified = resp.Header.Get("Last-Modified")
+	return nil
+}
+
+// HeadlineStore remembers which headlines have already been shown.
+type HeadlineStore interface {
+	SeenHeadlines(ctx context.Context, keys []string) (map[string]bool, error)
+	MarkHeadlinesSeen(ctx context.Context, keys []string) error
+}
+
+// MemoryHeadlines is an in-process HeadlineStore holding up to Max keys
+// (default 1000), forgetting the oldest first. The zero value is ready to use.
+type MemoryHeadlines struct {
+	Max int
+
+	mu    sync.Mutex
+	seen  map[string]bool
+	order []string
+}
+
+func (m *MemoryHeadlines) SeenHeadlines(_ context.Context, keys []string) (map[string]bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if m.seen[k] {
+			seen[k] = true
+		}
+	}
+	return seen, nil
+}
+
+func (m *MemoryHeadlines) MarkHeadlinesSeen(_ context.Context, keys []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.seen == nil {
+		m.seen = make(map[string]bool)
+	}
+	max := m.Max
+	if max == 0 {
+		max = 1000
+	}
+	for _, k := range keys {
+		if m.seen[k] {
+			continue
+		}
+		m.seen[k] = true
+		m.order = append(m.order, k)
+	}
+	for len(m.order) > max {
+		delete(m.seen, m.order[0])
+		m.order = m.order[1:]
+	}
+	return nil
+}
+
+// HeadlineKey identifies a headline for dedupe and seen tracking. It is based
+// on the normalised title so the same story syndicated by several feeds is
+// only shown once.
+func HeadlineKey(it feed.Item) string {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(it.Title)))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Feed scrolls the Count newest headlines not yet shown across all Sources.
+// Each headline is marked as seen once it has scrolled fully across the
+// display. When nothing is unseen it shows FallbackText, or skips itself.
+type Feed struct {
+	Sources      []*FeedSource
+	Count        int           // default 3
+	Seen         HeadlineStore // nil: remembered in memory for the widget's lifetime
+	FallbackText string
+	ScrollSpeed  time.Duration
+
+	memOnce sync.Once
+	mem     *MemoryHeadlines
+}
+
+func (f *Feed) store() HeadlineStore {
+	if f.Seen != nil {
+		return f.Seen
+	}
+	f.memOnce.Do(func() { f.mem = &MemoryHeadlines{} })
+	return f.mem
+}
+
+func (f *Feed) Name() string { return "feed" }
+
+func (f *Feed) Run(ctx context.Context, disp display.Display) error {
+	pd := disp.(display.PixelDisplay)
+	items := f.Headlines(ctx)
+	if len(items) == 0 {
+		if f.FallbackText == "" {
+			return nil
+		}
+		m := &Message{Text: f.FallbackText, ScrollSpeed: f.ScrollSpeed, Repeats: -1}
+		return m.Run(ctx, disp)
+	}
+
+	speed := f.ScrollSpeed
+	if speed == 0 {
+		speed = 50 * time.Millisecond
+	}
+	for _, it := range items {
+		if err := ScrollText(ctx, pd, it.Title, speed, 1, 0); err != nil {
+			return err
+		}
+		f.MarkSeen(ctx, it)
+	}
+	return nil
+}
+
+// Headlines returns up to Count unseen items, newest first, deduplicated by
+// ID and title across all sources.
+func (f *Feed) Headlines(ctx context.Context) []feed.Item {
+	var all []feed.Item
+	for _, src := range f.Sources {
+		items, err := src.Items(ctx)
+		if err != nil {
+			log.Printf("feed %s: %v", src.URL, err)
+		}
+		all = append(all, items...)
+	}
+
+	ids := make(map[string]bool)
+	titles := make(map[string]bool)
+	var keys []string
+	unique := all[:0:0]
+	for _, it := range all {
+		key := HeadlineKey(it)
+		if ids[it.ID] || titles[key] {
+			continue
+		}
+		ids[it.ID], titles[key] = true, true
+		unique = append(unique, it)
+		keys = append(keys, key)
+	}
+
+	seen, err := f.store().SeenHeadlines(ctx, keys)
+	if err != nil {
+		log.Printf("feed seen headlines: %v", err)
+	}
+	fresh := unique[:0]
+	for _, it := range unique {
+		if !seen[HeadlineKey(it)] {
+			fresh = append(fresh, it)
+		}
+	}
+
+	// Undated items sort after dated ones, keeping feed order among themselves.
+	sort.SliceStable(fresh, func(i, j int) bool {
+		return fresh[i].Published.After(fresh[j].Published)
+	})
+	count := f.Count
+	if count == 0 {
+		count = 3
+	}
+	if len(fresh) > count {
+		fresh = fresh[:count]
+	}
+	return fresh
+}
+
+// MarkSeen records that it has been shown.
+func (f *Feed) MarkSeen(ctx context.Context, it feed.Item) {
+	if err := f.store().MarkHeadlinesSeen(ctx, []string{HeadlineKey(it)}); err != nil {
+		log.Printf("feed mark seen: %v", err)
+	}
+}
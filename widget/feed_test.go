@@ -0,0 +1,130 @@
+package widget_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+func rssDoc(titles ...string) string {
+	doc := `<rss version="2.0"><channel>`
+	for i, title := range titles {
+		doc += fmt.Sprintf(`<item><title>%s</title><guid>%s</guid><pubDate>%s</pubDate></item>`,
+			title, title, time.Date(2026, 1, 1+i, 0, 0, 0, 0, time.UTC).Format(time.RFC1123Z))
+	}
+	return doc + `</channel></rss>`
+}
+
+func feedServer(t *testing.T, body string) (*httptest.Server, *int32) {
+	t.Helper()
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(body)) //nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &fetches
+}
+
+func TestFeedSource_ConditionalGET(t *testing.T) {
+	srv, fetches := feedServer(t, rssDoc("One", "Two"))
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	src := &widget.FeedSource{URL: srv.URL, Refresh: time.Minute, NowFunc: func() time.Time { return now }}
+
+	items, err := src.Items(context.Background())
+	if err != nil || len(items) != 2 {
+		t.Fatalf("Items() = %d, %v", len(items), err)
+	}
+	src.Items(context.Background()) //nolint:errcheck
+	if n := atomic.LoadInt32(fetches); n != 1 {
+		t.Errorf("expected cached result within refresh, got %d fetches", n)
+	}
+
+	now = now.Add(2 * time.Minute)
+	items, err = src.Items(context.Background())
+	if err != nil || len(items) != 2 {
+		t.Errorf("after 304: Items() = %d, %v; want previous items", len(items), err)
+	}
+	if n := atomic.LoadInt32(fetches); n != 2 {
+		t.Errorf("expected refetch after refresh, got %d fetches", n)
+	}
+}
+
+func TestFeed_NewestUnseenDeduped(t *testing.T) {
+	a, _ := feedServer(t, rssDoc("Old", "Shared", "Newest A"))
+	b, _ := feedServer(t, rssDoc("SHARED", "Newest B", "Latest"))
+	f := &widget.Feed{
+		Sources: []*widget.FeedSource{{URL: a.URL}, {URL: b.URL}},
+		Count:   3,
+	}
+
+	var got []string
+	for _, it := range f.Headlines(context.Background()) {
+		got = append(got, it.Title)
+	}
+	// Ties on date keep source order; "SHARED" duplicates "Shared".
+	want := []string{"Newest A", "Latest", "Shared"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("headlines = %q, want %q", got, want)
+	}
+
+	for _, it := range f.Headlines(context.Background())[:2] {
+		f.MarkSeen(context.Background(), it)
+	}
+	got = got[:0]
+	for _, it := range f.Headlines(context.Background()) {
+		got = append(got, it.Title)
+	}
+	want = []string{"Shared", "Newest B", "Old"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("after marking seen: headlines = %q, want %q", got, want)
+	}
+}
+
+func TestFeed_RunMarksScrolledHeadlines(t *testing.T) {
+	srv, _ := feedServer(t, rssDoc("Hi", "Yo"))
+	store := &widget.MemoryHeadlines{}
+	f := &widget.Feed{
+		Sources:     []*widget.FeedSource{{URL: srv.URL}},
+		Seen:        store,
+		ScrollSpeed: time.Millisecond,
+	}
+	spy := &testutil.SpyDisplay{}
+	if err := f.Run(context.Background(), spy); err != nil {
+		t.Fatal(err)
+	}
+	if len(spy.Frames) == 0 {
+		t.Error("expected headlines to be drawn")
+	}
+	if len(f.Headlines(context.Background())) != 0 {
+		t.Error("expected all headlines to be marked seen after scrolling")
+	}
+
+	// With nothing unseen and no fallback, the widget skips itself.
+	spy = &testutil.SpyDisplay{}
+	if err := f.Run(context.Background(), spy); err != nil || len(spy.Frames) != 0 {
+		t.Errorf("expected immediate skip, got err=%v frames=%d", err, len(spy.Frames))
+	}
+}
+
+func TestMemoryHeadlines_EvictsOldest(t *testing.T) {
+	m := &widget.MemoryHeadlines{Max: 2}
+	ctx := context.Background()
+	m.MarkHeadlinesSeen(ctx, []string{"a", "b", "c"}) //nolint:errcheck
+	seen, _ := m.SeenHeadlines(ctx, []string{"a", "b", "c"})
+	if seen["a"] || !seen["b"] || !seen["c"] {
+		t.Errorf("seen = %v, want only b and c", seen)
+	}
+}
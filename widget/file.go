@@ -0,0 +1,183 @@
+package widget
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+)
+
+// fullRescanLimit is the file size at or below which a changed file is
+// re-read from the start rather than from the last offset. Small status files
+// are usually rewritten in place, which an offset-based tail would miss.
+const fullRescanLimit = 64 * 1024
+
+// LineWatcher tracks the last line of a file that matches an optional
+// pattern. Appended data is read incrementally; rotation (a new file at the
+// same path) and truncation trigger a full re-read.
+type LineWatcher struct {
+	Path      string
+	pattern   *regexp.Regexp
+	transform string
+
+	mu     sync.Mutex
+	info   os.FileInfo
+	offset int64
+	last   string // last match among the complete lines before offset
+	found  bool
+	// tail is the match of an unterminated final line, re-read by every
+	// scan since the line may still change.
+	tail      string
+	tailFound bool
+}
+
+// NewLineWatcher creates a watcher for path. When pattern is non-empty only
+// matching lines are considered; transform, if set, rewrites the match using
+// regexp capture-group syntax ("$1", "${name}").
+func NewLineWatcher(path, pattern, transform string) (*LineWatcher, error) {
+	w := &LineWatcher{Path: path, transform: transform}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compiling pattern %q: %w", pattern, err)
+		}
+		w.pattern = re
+	}
+	return w, nil
+}
+
+// Line returns the last matching (and transformed) line. The file is only
+// read when its size, modification time or identity changed since the last call.
+func (w *LineWatcher) Line() (string, bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	info, err := os.Stat(w.Path)
+	if err != nil {
+		w.info, w.offset, w.last, w.found = nil, 0, "", false
+		w.tail, w.tailFound = "", false
+		return "", false, err
+	}
+
+	switch {
+	case w.info != nil && os.SameFile(w.info, info) &&
+		info.Size() == w.info.Size() && info.ModTime().Equal(w.info.ModTime()):
+		line, ok := w.current()
+		return line, ok, nil // unchanged
+	case w.info == nil || !os.SameFile(w.info, info) ||
+		info.Size() < w.offset || info.Size() <= fullRescanLimit:
+		// First read, rotated, truncated or small enough to rescan.
+		w.offset, w.last, w.found = 0, "", false
+	}
+
+	if err := w.scan(); err != nil {
+		return "", false, err
+	}
+	w.info = info
+	line, ok := w.current()
+	return line, ok, nil
+}
+
+// current returns the latest match, preferring one on an unterminated final
+// line. w.mu must be held.
+func (w *LineWatcher) current() (string, bool) {
+	if w.tailFound {
+		return w.tail, true
+	}
+	return w.last, w.found
+}
+
+// scan reads complete lines from w.offset to EOF, updating the last match.
+// A trailing partial line only sets the tail match and is read again by the
+// next scan.
+func (w *LineWatcher) scan() error {
+	f, err := os.Open(w.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Seek(w.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	w.tail, w.tailFound = "", false
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF {
+			// Match an unterminated final line without consuming it, so
+			// files written without a trailing newline still show up.
+			w.tail, w.tailFound = w.match(line)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		w.offset += int64(len(line))
+		if text, ok := w.match(line); ok {
+			w.last, w.found = text, true
+		}
+	}
+}
+
+// match returns the (transformed) line if it is non-blank and matches.
+func (w *LineWatcher) match(line string) (string, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.TrimSpace(line) == "" {
+		return "", false
+	}
+	if w.pattern == nil {
+		return line, true
+	}
+	m := w.pattern.FindStringSubmatchIndex(line)
+	if m == nil {
+		return "", false
+	}
+	if w.transform == "" {
+		return line, true
+	}
+	return string(w.pattern.ExpandString(nil, w.transform, line, m)), true
+}
+
+// Text returns the current line, or fallback (default "--") when the file is
+// missing or has no matching line.
+func (w *LineWatcher) Text(fallback string) string {
+	line, ok, err := w.Line()
+	if err != nil {
+		log.Printf("file %s: %v", w.Path, err)
+	}
+	if ok {
+		return line
+	}
+	if fallback == "" {
+		return "--"
+	}
+	return fallback
+}
+
+// FileTail displays the last matching line of a local file, re-checking the
+// file every Interval while it is on screen.
+type FileTail struct {
+	Watcher      *LineWatcher
+	FallbackText string
+	Interval     time.Duration
+	ScrollSpeed  time.Duration
+}
+
+func (f *FileTail) Name() string { return "file" }
+
+func (f *FileTail) Run(ctx context.Context, disp display.Display) error {
+	return RunWatched(ctx, f.Interval, func() string { return f.Watcher.Text(f.FallbackText) },
+		func(ctx context.Context, text string) error {
+			m := &Message{Text: text, ScrollSpeed: f.ScrollSpeed, Repeats: -1}
+			return m.Run(ctx, disp)
+		})
+}
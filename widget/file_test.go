@@ -0,0 +1,160 @@
+package widget_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustLine(t *testing.T, w *widget.LineWatcher, want string) {
+	t.Helper()
+	got, ok, err := w.Line()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || got != want {
+		t.Errorf("Line() = (%q, %v), want (%q, true)", got, ok, want)
+	}
+}
+
+func TestLineWatcher_LastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status")
+	writeFile(t, path, "one\ntwo\n\n")
+	w, _ := widget.NewLineWatcher(path, "", "")
+	mustLine(t, w, "two")
+}
+
+func TestLineWatcher_PatternAndTransform(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.log")
+	writeFile(t, path, "INFO start\nRESULT main=ok\nINFO noise\n")
+	w, err := widget.NewLineWatcher(path, `^RESULT (\w+)=(\w+)`, "$1 $2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustLine(t, w, "main ok")
+
+	appendFile(t, path, "RESULT dev=fail\nINFO more\n")
+	mustLine(t, w, "dev fail")
+}
+
+func TestLineWatcher_InvalidPattern(t *testing.T) {
+	if _, err := widget.NewLineWatcher("x", "(", ""); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestLineWatcher_Truncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	writeFile(t, path, "first line that is long\n")
+	w, _ := widget.NewLineWatcher(path, "", "")
+	mustLine(t, w, "first line that is long")
+
+	writeFile(t, path, "short\n")
+	mustLine(t, w, "short")
+}
+
+func TestLineWatcher_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log")
+	writeFile(t, path, "old\n")
+	w, _ := widget.NewLineWatcher(path, "", "")
+	mustLine(t, w, "old")
+
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, "new\n")
+	mustLine(t, w, "new")
+}
+
+func TestLineWatcher_IncrementalLargeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.log")
+	writeFile(t, path, strings.Repeat("filler line\n", 10000)+"MARK a\n")
+	w, _ := widget.NewLineWatcher(path, "^MARK", "")
+	mustLine(t, w, "MARK a")
+
+	appendFile(t, path, "filler\nMARK b\n")
+	mustLine(t, w, "MARK b")
+
+	appendFile(t, path, "filler only\n")
+	mustLine(t, w, "MARK b")
+}
+
+func TestLineWatcher_PartialLineCompletedWithoutMatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.log")
+	writeFile(t, path, strings.Repeat("filler line\n", 10000)+"MARK a\nMARK b")
+	w, _ := widget.NewLineWatcher(path, "^MARK b$", "")
+	mustLine(t, w, "MARK b")
+
+	// The partial line is completed as "MARK bogus", which no longer matches.
+	appendFile(t, path, "ogus\n")
+	if got, ok, err := w.Line(); err != nil || ok {
+		t.Errorf("Line() = (%q, %v, %v), want no match", got, ok, err)
+	}
+}
+
+func TestLineWatcher_NoTrailingNewline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status")
+	writeFile(t, path, "a\nbuilding")
+	w, _ := widget.NewLineWatcher(path, "", "")
+	mustLine(t, w, "building")
+}
+
+func TestLineWatcher_MissingFile(t *testing.T) {
+	w, _ := widget.NewLineWatcher(filepath.Join(t.TempDir(), "nope"), "", "")
+	if _, ok, err := w.Line(); ok || err == nil {
+		t.Errorf("expected error for missing file, got ok=%v err=%v", ok, err)
+	}
+	if got := w.Text("idle"); got != "idle" {
+		t.Errorf("Text() = %q, want fallback %q", got, "idle")
+	}
+}
+
+func TestFileTail_UpdatesWhenFileChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status")
+	writeFile(t, path, "ok\n")
+	w, _ := widget.NewLineWatcher(path, "", "")
+	spy := &testutil.SpyDisplay{}
+	ft := &widget.FileTail{Watcher: w, Interval: 10 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		writeFile(t, path, "fail\n")
+	}()
+	ft.Run(ctx, spy) //nolint:errcheck
+
+	if len(spy.Frames) < 2 {
+		t.Fatalf("expected a redraw after the file changed, got %d frames", len(spy.Frames))
+	}
+	if !bytes.Equal(spy.Frames[len(spy.Frames)-1], centeredFrame("fail")) {
+		t.Error("expected last frame to show the new file contents")
+	}
+}
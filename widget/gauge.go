@@ -0,0 +1,178 @@
+package widget
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/font"
+	"github.com/swilcox/led-kurokku-go/framebuf"
+)
+
+// Gauge styles.
+const (
+	GaugeBar     = "bar"     // framed horizontal bar (default)
+	GaugeMeter   = "meter"   // eight separate blocks
+	GaugePercent = "percent" // percentage text with a thin bar underneath
+)
+
+// Gauge renders a numeric reading as a bar, a segmented meter or a
+// percentage, scaled between Min and Max. Readings past the Threshold or
+// outside Min..Max switch to AlertStyle.
+type Gauge struct {
+	Source     ValueSource
+	Min        float64
+	Max        *float64 // nil means 100
+	Style      string
+	Prefix     string // percent style only
+	Format     string // percent style only, default "%.0f"
+	Threshold  *Threshold
+	AlertStyle string
+	Interval   time.Duration // re-read interval while displayed, default 5s
+	// RestoreBrightness is called after a brightness-boosted alert.
+	RestoreBrightness func()
+}
+
+func (g *Gauge) Name() string { return "gauge" }
+
+// Fraction maps v onto 0..1 between Min and Max, clamping, and reports
+// whether v was out of range. Every reading is out of range when Max is not
+// above Min.
+func (g *Gauge) Fraction(v float64) (float64, bool) {
+	max := 100.0
+	if g.Max != nil {
+		max = *g.Max
+	}
+	if max <= g.Min || math.IsNaN(v) {
+		return 0, true
+	}
+	f := (v - g.Min) / (max - g.Min)
+	switch {
+	case f < 0:
+		return 0, true
+	case f > 1:
+		return 1, true
+	}
+	return f, false
+}
+
+// Alert reports whether v should be shown with the alert style.
+func (g *Gauge) Alert(v float64) bool {
+	_, out := g.Fraction(v)
+	return out || g.Threshold.Crossed(v)
+}
+
+// PercentText formats the reading as a percentage of the Min..Max range.
+func (g *Gauge) PercentText(v float64) string {
+	f, _ := g.Fraction(v)
+	format := g.Format
+	if format == "" {
+		format = "%.0f"
+	}
+	return FormatValue(g.Prefix, f*100, format, "%")
+}
+
+func (g *Gauge) read(ctx context.Context) (float64, bool) {
+	v, err := g.Source.Value(ctx)
+	if err != nil {
+		log.Printf("gauge: %v", err)
+		return 0, false
+	}
+	return v, true
+}
+
+func (g *Gauge) Run(ctx context.Context, disp display.Display) error {
+	return RunGauge(ctx, disp, g, func(ctx context.Context, disp display.Display, v float64, ok bool) error {
+		var f framebuf.Frame
+		if ok {
+			f = g.Frame(v)
+		} else {
+			framebuf.BlitText(&f, "--", (32-len(font.RenderText("--")))/2)
+		}
+		disp.(display.PixelDisplay).WriteFramebuffer(f.Bytes())
+		return nil
+	})
+}
+
+// RunGauge re-reads g.Source every g.Interval and calls draw with each
+// reading (ok is false when the read failed). The alert style is applied for
+// as long as readings stay in alert, so a brightness boost is not toggled on
+// every refresh. draw may block; it is cancelled when the interval elapses.
+func RunGauge(ctx context.Context, disp display.Display, g *Gauge,
+	draw func(ctx context.Context, disp display.Display, v float64, ok bool) error) error {
+
+	interval := g.Interval
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+	v, ok := g.read(ctx)
+	for {
+		alert := ok && g.Alert(v)
+		err := RunStyled(ctx, disp, alert, g.AlertStyle, g.RestoreBrightness, func(ctx context.Context, disp display.Display) error {
+			for {
+				dctx, cancel := context.WithTimeout(ctx, interval)
+				draw(dctx, disp, v, ok)
+				<-dctx.Done()
+				cancel()
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				v, ok = g.read(ctx)
+				if (ok && g.Alert(v)) != alert {
+					return nil
+				}
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Frame draws the reading in the gauge's style.
+func (g *Gauge) Frame(v float64) framebuf.Frame {
+	frac, _ := g.Fraction(v)
+	var f framebuf.Frame
+	switch g.Style {
+	case GaugeMeter:
+		// Eight 3-pixel blocks with 1-pixel gaps; unlit blocks keep a baseline.
+		lit := int(math.Round(frac * 8))
+		for b := 0; b < 8; b++ {
+			for x := b * 4; x < b*4+3; x++ {
+				if b < lit {
+					for y := 1; y <= 6; y++ {
+						f.SetPixel(x, y, true)
+					}
+				} else {
+					f.SetPixel(x, 6, true)
+				}
+			}
+		}
+	case GaugePercent:
+		text := g.PercentText(v)
+		w := len(font.RenderText(text))
+		framebuf.BlitText(&f, text, (32-w)/2)
+		for x := 0; x < int(math.Round(frac*32)); x++ {
+			f.SetPixel(x, 7, true)
+		}
+	default:
+		// Frame rows 1 and 6, sides at columns 0 and 31; fill inside.
+		for x := 0; x < 32; x++ {
+			f.SetPixel(x, 1, true)
+			f.SetPixel(x, 6, true)
+		}
+		for y := 1; y <= 6; y++ {
+			f.SetPixel(0, y, true)
+			f.SetPixel(31, y, true)
+		}
+		fill := int(math.Round(frac * 30))
+		for x := 1; x <= fill; x++ {
+			for y := 2; y <= 5; y++ {
+				f.SetPixel(x, y, true)
+			}
+		}
+	}
+	return f
+}
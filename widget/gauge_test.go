@@ -0,0 +1,155 @@
+package widget_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+type fixedValue struct {
+	v   float64
+	err error
+}
+
+func (f *fixedValue) Value(context.Context) (float64, error) { return f.v, f.err }
+
+type textFetcher map[string]string
+
+func (m textFetcher) FetchMessageText(_ context.Context, key string) (string, bool, error) {
+	v, ok := m[key]
+	return v, ok, nil
+}
+
+func TestValueSources(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jobs":[{"progress":"40"},{"progress":62.5}]}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "capacity")
+	os.WriteFile(path, []byte("87\n"), 0o644) //nolint:errcheck
+
+	tests := []struct {
+		name string
+		src  widget.ValueSource
+		want float64
+	}{
+		{"redis", &widget.RedisValue{Fetcher: textFetcher{"ci:progress": " 35% "}, Key: "ci:progress"}, 35},
+		{"json number", &widget.JSONValue{URL: srv.URL, Path: "jobs.1.progress"}, 62.5},
+		{"json string", &widget.JSONValue{URL: srv.URL, Path: "jobs.0.progress"}, 40},
+		{"file", &widget.FileValue{Path: path}, 87},
+	}
+	for _, tt := range tests {
+		v, err := tt.src.Value(context.Background())
+		if err != nil || v != tt.want {
+			t.Errorf("%s: got (%v, %v), want %v", tt.name, v, err, tt.want)
+		}
+	}
+
+	bad := []widget.ValueSource{
+		&widget.RedisValue{Fetcher: textFetcher{}, Key: "missing"},
+		&widget.JSONValue{URL: srv.URL, Path: "jobs.5.progress"},
+		&widget.JSONValue{URL: srv.URL, Path: "jobs"},
+		&widget.FileValue{Path: filepath.Join(t.TempDir(), "nope")},
+		// Endless, so only the first bytes may be read.
+		&widget.FileValue{Path: "/dev/zero"},
+	}
+	for i, src := range bad {
+		if _, err := src.Value(context.Background()); err == nil {
+			t.Errorf("bad source %d: expected error", i)
+		}
+	}
+}
+
+func TestGauge_FractionAndAlert(t *testing.T) {
+	max := 50.0
+	g := &widget.Gauge{Min: 10, Max: &max, Threshold: &widget.Threshold{Value: 40}}
+	if f, out := g.Fraction(30); f != 0.5 || out {
+		t.Errorf("Fraction(30) = %v, %v", f, out)
+	}
+	if f, out := g.Fraction(60); f != 1 || !out {
+		t.Errorf("Fraction(60) = %v, %v; want clamped and out of range", f, out)
+	}
+	if g.Alert(30) || !g.Alert(45) || !g.Alert(5) {
+		t.Error("expected alert above threshold and below min only")
+	}
+	if got := g.PercentText(30); got != "50%" {
+		t.Errorf("PercentText(30) = %q", got)
+	}
+}
+
+func TestGauge_ExplicitZeroMax(t *testing.T) {
+	zero := 0.0
+	g := &widget.Gauge{Min: -20, Max: &zero}
+	if f, out := g.Fraction(-5); f != 0.75 || out {
+		t.Errorf("Fraction(-5) = %v, %v; want 0.75 within range", f, out)
+	}
+	// Unset, Max defaults to 100.
+	if f, _ := (&widget.Gauge{}).Fraction(25); f != 0.25 {
+		t.Errorf("Fraction(25) with default max = %v, want 0.25", f)
+	}
+	// A range with Max not above Min puts every reading out of range.
+	if _, out := (&widget.Gauge{Min: 5, Max: &zero}).Fraction(1); !out {
+		t.Error("expected out of range when max <= min")
+	}
+}
+
+func countLit(f []byte) int {
+	n := 0
+	for _, col := range f {
+		for ; col != 0; col &= col - 1 {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGauge_FrameStyles(t *testing.T) {
+	bar := &widget.Gauge{}
+	empty, half, full := bar.Frame(0), bar.Frame(50), bar.Frame(100)
+	if countLit(empty[:]) >= countLit(half[:]) || countLit(half[:]) >= countLit(full[:]) {
+		t.Error("bar fill should grow with the value")
+	}
+	if !full.GetPixel(30, 3) || half.GetPixel(20, 3) || !half.GetPixel(15, 3) {
+		t.Error("unexpected bar fill extent")
+	}
+
+	meter := &widget.Gauge{Style: widget.GaugeMeter}
+	m := meter.Frame(25)
+	if !m.GetPixel(4, 2) || m.GetPixel(8, 2) || !m.GetPixel(8, 6) {
+		t.Error("25% meter should light two blocks and leave baselines on the rest")
+	}
+
+	pct := &widget.Gauge{Style: widget.GaugePercent}
+	p := pct.Frame(50)
+	if !p.GetPixel(15, 7) || p.GetPixel(16, 7) {
+		t.Error("percent style should underline half the width")
+	}
+}
+
+func TestGauge_RunBlinksInAlertAndRefreshes(t *testing.T) {
+	src := &fixedValue{v: 95}
+	g := &widget.Gauge{Source: src, Threshold: &widget.Threshold{Value: 90}, Interval: 50 * time.Millisecond}
+	spy := &testutil.SpyDisplay{}
+	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Millisecond)
+	defer cancel()
+	g.Run(ctx, spy) //nolint:errcheck
+
+	blank := make([]byte, 32)
+	sawBlank := false
+	for _, f := range spy.Frames {
+		if bytes.Equal(f, blank) {
+			sawBlank = true
+		}
+	}
+	if !sawBlank {
+		t.Error("expected blinking while above threshold")
+	}
+}
@@ -0,0 +1,112 @@
+package widget
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/mpd"
+)
+
+// NowPlaying shows "Artist - Title" while MPD is playing and skips itself
+// otherwise. It implements Interrupter: when a new track starts it asks the
+// engine to show it immediately.
+type NowPlaying struct {
+	Client      *mpd.Client
+	ScrollSpeed time.Duration
+}
+
+func (n *NowPlaying) Name() string { return "mpd" }
+
+func (n *NowPlaying) Run(ctx context.Context, disp display.Display) error {
+	return RunNowPlaying(ctx, n.Client, func(ctx context.Context, text string) error {
+		m := &Message{Text: text, ScrollSpeed: n.ScrollSpeed, Repeats: -1}
+		return m.Run(ctx, disp)
+	})
+}
+
+// Interrupts watches the player with MPD's idle command and signals when
+// playback starts or moves to a different song.
+func (n *NowPlaying) Interrupts(ctx context.Context) <-chan struct{} {
+	return WatchNowPlaying(ctx, n.Client)
+}
+
+// RunNowPlaying shows the current song with show until playback stops or ctx
+// is done, restarting show when the song changes. It returns nil without
+// showing anything when MPD is not playing or cannot be reached.
+func RunNowPlaying(ctx context.Context, c *mpd.Client, show func(ctx context.Context, text string) error) error {
+	st, err := c.Status(ctx)
+	if err != nil {
+		log.Printf("mpd status: %v", err)
+		return nil
+	}
+	for st.Playing() {
+		text := st.Song.Text()
+		showCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			show(showCtx, text)
+			close(done)
+		}()
+
+		// Wait for a player event that changes what should be shown; seeks
+		// and option changes leave the scrolling text alone.
+		for st.Playing() && st.Song.Text() == text {
+			if _, err = c.Idle(showCtx, "player"); err == nil {
+				st, err = c.Status(showCtx)
+			}
+			if err != nil {
+				break
+			}
+		}
+		cancel()
+		<-done
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			log.Printf("mpd: %v", err)
+			return nil
+		}
+	}
+	return nil
+}
+
+// WatchNowPlaying signals on the returned channel each time MPD starts
+// playing or switches songs. Connection failures are retried with backoff.
+func WatchNowPlaying(ctx context.Context, c *mpd.Client) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	go func() {
+		var last mpd.Status
+		if st, err := c.Status(ctx); err == nil {
+			last = st
+		}
+		backoff := time.Second
+		for ctx.Err() == nil {
+			_, err := c.Idle(ctx, "player")
+			var st mpd.Status
+			if err == nil {
+				st, err = c.Status(ctx)
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("mpd watch: %v (retrying in %s)", err, backoff)
+				SleepOrCancel(ctx, backoff)
+				backoff = min(backoff*2, time.Minute)
+				continue
+			}
+			backoff = time.Second
+			if st.Playing() && (!last.Playing() || st.SongID != last.SongID) {
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			}
+			last = st
+		}
+	}()
+	return ch
+}
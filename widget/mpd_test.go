@@ -0,0 +1,88 @@
+package widget_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/mpd"
+	mpdtest "github.com/swilcox/led-kurokku-go/mpd/testutil"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+func TestNowPlaying_SkipsWhenStopped(t *testing.T) {
+	srv := mpdtest.NewServer(t)
+	n := &widget.NowPlaying{Client: &mpd.Client{Addr: srv.Addr}}
+	spy := &testutil.SpyDisplay{}
+	if err := n.Run(context.Background(), spy); err != nil {
+		t.Fatal(err)
+	}
+	if len(spy.Frames) != 0 {
+		t.Error("expected nothing drawn while stopped")
+	}
+}
+
+func TestNowPlaying_SkipsWhenUnreachable(t *testing.T) {
+	n := &widget.NowPlaying{Client: &mpd.Client{Addr: "127.0.0.1:1", Timeout: time.Second}}
+	if err := n.Run(context.Background(), &testutil.SpyDisplay{}); err != nil {
+		t.Errorf("expected nil error for unreachable MPD, got %v", err)
+	}
+}
+
+func TestRunNowPlaying_FollowsTrackChangesUntilStopped(t *testing.T) {
+	srv := mpdtest.NewServer(t)
+	srv.Play("1", "Artist", "First")
+
+	var mu sync.Mutex
+	var shown []string
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		srv.Play("2", "Artist", "Second")
+		time.Sleep(100 * time.Millisecond)
+		srv.Stop()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err := widget.RunNowPlaying(ctx, &mpd.Client{Addr: srv.Addr}, func(ctx context.Context, text string) error {
+		mu.Lock()
+		shown = append(shown, text)
+		mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("expected nil once playback stops, got %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("expected RunNowPlaying to return when playback stopped")
+	}
+	if len(shown) != 2 || shown[0] != "Artist - First" || shown[1] != "Artist - Second" {
+		t.Errorf("shown = %q", shown)
+	}
+}
+
+func TestWatchNowPlaying_SignalsOnNewTrack(t *testing.T) {
+	srv := mpdtest.NewServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := widget.WatchNowPlaying(ctx, &mpd.Client{Addr: srv.Addr})
+
+	time.Sleep(50 * time.Millisecond) // let the watcher start idling
+	srv.Play("1", "A", "B")
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected interrupt when playback starts")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	srv.Stop()
+	select {
+	case <-ch:
+		t.Error("expected no interrupt when playback stops")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
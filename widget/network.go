@@ -0,0 +1,166 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/sysinfo"
+)
+
+// Pinger checks connectivity to a remote service.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// NetworkStatus is a snapshot of the host's network state.
+type NetworkStatus struct {
+	Interfaces []sysinfo.Interface
+	Wireless   []sysinfo.Wireless
+	PingLabel  string
+	Reachable  *bool // nil when no Pinger is configured
+}
+
+// Text formats the status as a single line for scrolling on a pixel display,
+// e.g. "wlan0 192.168.1.23  Home -52dBm  redis ok".
+func (s NetworkStatus) Text() string {
+	var parts []string
+	for _, ifc := range s.Interfaces {
+		addrs := make([]string, len(ifc.Addrs))
+		for i, ip := range ifc.Addrs {
+			addrs[i] = ip.String()
+		}
+		parts = append(parts, ifc.Name+" "+strings.Join(addrs, " "))
+	}
+	if len(s.Interfaces) == 0 {
+		parts = append(parts, "no network")
+	}
+	for _, wl := range s.Wireless {
+		name := wl.SSID
+		if name == "" {
+			name = wl.Interface
+		}
+		parts = append(parts, fmt.Sprintf("%s %.0fdBm", name, wl.Signal))
+	}
+	if s.Reachable != nil {
+		state := "down"
+		if *s.Reachable {
+			state = "ok"
+		}
+		parts = append(parts, s.PingLabel+" "+state)
+	}
+	return strings.Join(parts, "  ")
+}
+
+// Pages splits the status into short pages for a 4-digit segment display:
+// the interface name, then one page per IPv4 octet or IPv6 group, then the
+// Wi-Fi SSID and signal, then reachability ("r ok" / "r --").
+func (s NetworkStatus) Pages() []string {
+	var pages []string
+	for _, ifc := range s.Interfaces {
+		pages = append(pages, ifc.Name)
+		for _, ip := range ifc.Addrs {
+			if v4 := ip.To4(); v4 != nil {
+				for _, b := range v4 {
+					pages = append(pages, fmt.Sprintf("%4d", b))
+				}
+				continue
+			}
+			for i := 0; i < 16; i += 2 {
+				pages = append(pages, fmt.Sprintf("%4x", uint16(ip[i])<<8|uint16(ip[i+1])))
+			}
+		}
+	}
+	if len(s.Interfaces) == 0 {
+		pages = append(pages, "----")
+	}
+	for _, wl := range s.Wireless {
+		if wl.SSID != "" {
+			pages = append(pages, wl.SSID)
+		}
+		pages = append(pages, fmt.Sprintf("%4.0f", wl.Signal))
+	}
+	if s.Reachable != nil {
+		if *s.Reachable {
+			pages = append(pages, "r ok")
+		} else {
+			pages = append(pages, "r --")
+		}
+	}
+	return pages
+}
+
+// NetworkProbe collects a NetworkStatus. The zero value probes all up,
+// non-loopback interfaces for IPv4 addresses.
+type NetworkProbe struct {
+	Reader     sysinfo.Reader
+	Interfaces []string
+	IPv6       bool
+	Pinger     Pinger
+	PingLabel  string // default "redis"
+	// ListInterfaces and LookupSSID override the host lookups in tests.
+	ListInterfaces func() ([]sysinfo.Interface, error)
+	LookupSSID     func(ctx context.Context, iface string) string
+}
+
+// Status gathers the current network state. Lookup failures are logged and
+// leave the corresponding section empty.
+func (p *NetworkProbe) Status(ctx context.Context) NetworkStatus {
+	st := NetworkStatus{PingLabel: p.PingLabel}
+	if st.PingLabel == "" {
+		st.PingLabel = "redis"
+	}
+
+	list := p.ListInterfaces
+	if list == nil {
+		list = func() ([]sysinfo.Interface, error) { return sysinfo.Interfaces(p.Interfaces, p.IPv6) }
+	}
+	ifaces, err := list()
+	if err != nil {
+		log.Printf("network: %v", err)
+	}
+	st.Interfaces = ifaces
+
+	wireless, err := p.Reader.Wireless()
+	if err != nil {
+		log.Printf("network: %v", err)
+	}
+	lookup := p.LookupSSID
+	if lookup == nil {
+		lookup = sysinfo.SSID
+	}
+	for i := range wireless {
+		wireless[i].SSID = lookup(ctx, wireless[i].Interface)
+	}
+	st.Wireless = wireless
+
+	if p.Pinger != nil {
+		pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		ok := p.Pinger.Ping(pingCtx) == nil
+		cancel()
+		st.Reachable = &ok
+	}
+	return st
+}
+
+// Network shows interface addresses, Wi-Fi signal and service reachability,
+// scrolling the whole status line on the pixel display.
+type Network struct {
+	Probe       NetworkProbe
+	ScrollSpeed time.Duration
+}
+
+func (n *Network) Name() string { return "network" }
+
+func (n *Network) Run(ctx context.Context, disp display.Display) error {
+	st := n.Probe.Status(ctx)
+	m := &Message{
+		Text:        st.Text(),
+		ScrollSpeed: n.ScrollSpeed,
+		Repeats:     -1,
+	}
+	return m.Run(ctx, disp)
+}
@@ -0,0 +1,68 @@
+package widget_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/swilcox/led-kurokku-go/sysinfo"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+type mockPinger struct{ err error }
+
+func (m *mockPinger) Ping(_ context.Context) error { return m.err }
+
+func testProbe(t *testing.T, pingErr error) *widget.NetworkProbe {
+	t.Helper()
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "net"), 0755) //nolint:errcheck
+	wireless := "hdr\nhdr\n wlan0: 0000   60.  -52.  -256  0 0 0 0 0 0\n"
+	if err := os.WriteFile(filepath.Join(root, "net", "wireless"), []byte(wireless), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return &widget.NetworkProbe{
+		Reader: sysinfo.Reader{ProcRoot: root},
+		Pinger: &mockPinger{err: pingErr},
+		ListInterfaces: func() ([]sysinfo.Interface, error) {
+			return []sysinfo.Interface{{Name: "wlan0", Addrs: []net.IP{net.ParseIP("192.168.1.23")}}}, nil
+		},
+		LookupSSID: func(_ context.Context, iface string) string { return "Home" },
+	}
+}
+
+func TestNetworkStatus_Text(t *testing.T) {
+	st := testProbe(t, nil).Status(context.Background())
+	want := "wlan0 192.168.1.23  Home -52dBm  redis ok"
+	if got := st.Text(); got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestNetworkStatus_Pages(t *testing.T) {
+	st := testProbe(t, errors.New("refused")).Status(context.Background())
+	want := []string{"wlan0", " 192", " 168", "   1", "  23", "Home", " -52", "r --"}
+	if got := st.Pages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Pages() = %q, want %q", got, want)
+	}
+}
+
+func TestNetworkStatus_IPv6Pages(t *testing.T) {
+	st := widget.NetworkStatus{
+		Interfaces: []sysinfo.Interface{{Name: "eth0", Addrs: []net.IP{net.ParseIP("2001:db8::1")}}},
+	}
+	want := []string{"eth0", "2001", " db8", "   0", "   0", "   0", "   0", "   0", "   1"}
+	if got := st.Pages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Pages() = %q, want %q", got, want)
+	}
+}
+
+func TestNetworkStatus_NoInterfaces(t *testing.T) {
+	if got := (widget.NetworkStatus{}).Text(); got != "no network" {
+		t.Errorf("Text() = %q, want %q", got, "no network")
+	}
+}
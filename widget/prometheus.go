@@ -0,0 +1,230 @@
+package widget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+)
+
+// PromQuery evaluates a PromQL instant query against a Prometheus-compatible
+// HTTP API (GET <URL>/api/v1/query).
+type PromQuery struct {
+	URL   string
+	Query string
+	// Label selects a series from a vector result. When LabelValue is set,
+	// the first series whose Label equals LabelValue is used; otherwise the
+	// first series is used and its Label value is reported alongside the sample.
+	Label      string
+	LabelValue string
+	Client     *http.Client
+}
+
+type promResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type promSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]interface{}    `json:"value"`
+}
+
+// Eval runs the query and returns the sample value along with the value of
+// the configured Label on the selected series (empty for scalar results).
+func (q *PromQuery) Eval(ctx context.Context) (float64, string, error) {
+	client := q.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	u := strings.TrimRight(q.URL, "/") + "/api/v1/query?" + url.Values{"query": {q.Query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, "", fmt.Errorf("building prometheus request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("prometheus query: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var pr promResponse
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return 0, "", fmt.Errorf("decoding prometheus response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if pr.Status != "success" {
+		return 0, "", fmt.Errorf("prometheus %s: %s", pr.ErrorType, pr.Error)
+	}
+
+	switch pr.Data.ResultType {
+	case "scalar":
+		var pair [2]interface{}
+		if err := json.Unmarshal(pr.Data.Result, &pair); err != nil {
+			return 0, "", fmt.Errorf("decoding scalar result: %w", err)
+		}
+		v, err := promValue(pair)
+		return v, "", err
+	case "vector":
+		var samples []promSample
+		if err := json.Unmarshal(pr.Data.Result, &samples); err != nil {
+			return 0, "", fmt.Errorf("decoding vector result: %w", err)
+		}
+		for _, s := range samples {
+			if q.LabelValue != "" && s.Metric[q.Label] != q.LabelValue {
+				continue
+			}
+			v, err := promValue(s.Value)
+			return v, s.Metric[q.Label], err
+		}
+		return 0, "", fmt.Errorf("prometheus query returned no matching series")
+	default:
+		return 0, "", fmt.Errorf("unsupported prometheus result type %q", pr.Data.ResultType)
+	}
+}
+
+// promValue parses the [timestamp, "value"] pair used by the Prometheus API.
+func promValue(pair [2]interface{}) (float64, error) {
+	s, ok := pair[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected sample value %v", pair[1])
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing sample value %q: %w", s, err)
+	}
+	return v, nil
+}
+
+// Threshold describes when a numeric widget switches to alert styling.
+type Threshold struct {
+	Value float64
+	Below bool // alert when the value drops below Value instead of above it
+}
+
+// Crossed reports whether v is past the threshold. A nil threshold is never crossed.
+func (t *Threshold) Crossed(v float64) bool {
+	if t == nil {
+		return false
+	}
+	if t.Below {
+		return v < t.Value
+	}
+	return v > t.Value
+}
+
+// Alert styles applied when a threshold is crossed.
+const (
+	AlertStyleBlink      = "blink"
+	AlertStyleBrightness = "brightness"
+)
+
+// FormatValue renders v as "<prefix> <value><unit>". An empty format picks a
+// precision based on magnitude so values stay short on small displays.
+func FormatValue(prefix string, v float64, format, unit string) string {
+	var num string
+	switch {
+	case format != "":
+		num = fmt.Sprintf(format, v)
+	case math.IsNaN(v) || math.IsInf(v, 0):
+		num = "--"
+	case math.Abs(v) >= 100 || v == math.Trunc(v):
+		num = strconv.FormatFloat(v, 'f', 0, 64)
+	case math.Abs(v) >= 10:
+		num = strconv.FormatFloat(v, 'f', 1, 64)
+	default:
+		num = strconv.FormatFloat(v, 'f', 2, 64)
+	}
+	if prefix == "" {
+		return num + unit
+	}
+	return prefix + " " + num + unit
+}
+
+// Prometheus displays the result of a PromQL instant query, switching to
+// alert styling when the configured threshold is crossed.
+type Prometheus struct {
+	Query        PromQuery
+	Prefix       string
+	Unit         string
+	Format       string
+	Scale        float64 // multiplier applied before formatting; 0 means 1
+	Threshold    *Threshold
+	AlertStyle   string
+	FallbackText string
+	ScrollSpeed  time.Duration
+	// RestoreBrightness is called after a brightness-boosted alert so the
+	// normal time-of-day level is reapplied.
+	RestoreBrightness func()
+}
+
+func (p *Prometheus) Name() string { return "prometheus" }
+
+func (p *Prometheus) Run(ctx context.Context, disp display.Display) error {
+	text, alert := p.Text(ctx)
+	return RunStyled(ctx, disp, alert, p.AlertStyle, p.RestoreBrightness, func(ctx context.Context, disp display.Display) error {
+		m := &Message{
+			Text:        text,
+			ScrollSpeed: p.ScrollSpeed,
+			Repeats:     -1,
+		}
+		return m.Run(ctx, disp)
+	})
+}
+
+// Text evaluates the query and returns the formatted display text and
+// whether the threshold was crossed. Query errors yield FallbackText (or "--").
+func (p *Prometheus) Text(ctx context.Context) (string, bool) {
+	v, label, err := p.Query.Eval(ctx)
+	if err != nil {
+		log.Printf("prometheus query %q: %v", p.Query.Query, err)
+		if p.FallbackText != "" {
+			return p.FallbackText, false
+		}
+		return "--", false
+	}
+	if p.Scale != 0 {
+		v *= p.Scale
+	}
+	prefix := p.Prefix
+	if prefix == "" {
+		prefix = label
+	}
+	return FormatValue(prefix, v, p.Format, p.Unit), p.Threshold.Crossed(v)
+}
+
+// RunStyled runs fn on disp, applying the given alert style when alert is true:
+// AlertStyleBlink blinks the output and AlertStyleBrightness forces maximum
+// brightness, calling restore (if set) afterwards.
+func RunStyled(ctx context.Context, disp display.Display, alert bool, style string,
+	restore func(), fn func(ctx context.Context, disp display.Display) error) error {
+
+	if !alert {
+		return fn(ctx, disp)
+	}
+	switch style {
+	case AlertStyleBrightness:
+		disp.SetBrightness(15)
+		if restore != nil {
+			defer restore()
+		}
+		return fn(ctx, disp)
+	default:
+		bd, stop := Blink(ctx, disp, 250*time.Millisecond)
+		defer stop()
+		return fn(ctx, bd)
+	}
+}
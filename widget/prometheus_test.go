@@ -0,0 +1,187 @@
+package widget_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+func promStub(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("query") == "" {
+			t.Error("expected query parameter")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body)) //nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+const promVector = `{"status":"success","data":{"resultType":"vector","result":[
+	{"metric":{"job":"api","quantile":"0.5"},"value":[1700000000,"0.051"]},
+	{"metric":{"job":"api","quantile":"0.99"},"value":[1700000000,"0.2123"]}
+]}}`
+
+func TestPromQuery_Scalar(t *testing.T) {
+	srv := promStub(t, `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"42.5"]}}`)
+	q := &widget.PromQuery{URL: srv.URL, Query: "scalar(up)"}
+	v, _, err := q.Eval(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 42.5 {
+		t.Errorf("got %v, want 42.5", v)
+	}
+}
+
+func TestPromQuery_VectorSelectsLabel(t *testing.T) {
+	srv := promStub(t, promVector)
+	q := &widget.PromQuery{URL: srv.URL, Query: "latency", Label: "quantile", LabelValue: "0.99"}
+	v, label, err := q.Eval(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 0.2123 || label != "0.99" {
+		t.Errorf("got (%v, %q), want (0.2123, \"0.99\")", v, label)
+	}
+}
+
+func TestPromQuery_VectorNoMatch(t *testing.T) {
+	srv := promStub(t, promVector)
+	q := &widget.PromQuery{URL: srv.URL, Query: "latency", Label: "quantile", LabelValue: "0.9"}
+	if _, _, err := q.Eval(context.Background()); err == nil {
+		t.Error("expected error when no series matches")
+	}
+}
+
+func TestPromQuery_ErrorStatus(t *testing.T) {
+	srv := promStub(t, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+	q := &widget.PromQuery{URL: srv.URL, Query: "up{"}
+	if _, _, err := q.Eval(context.Background()); err == nil {
+		t.Error("expected error for status=error response")
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		prefix, format, unit string
+		v                    float64
+		want                 string
+	}{
+		{"p99", "", "ms", 212.3, "p99 212ms"},
+		{"", "", "%", 12.34, "12.3%"},
+		{"", "", "", 0.5, "0.50"},
+		{"", "", "", 7, "7"},
+		{"up", "%.1f", "", 3, "up 3.0"},
+	}
+	for _, tt := range tests {
+		if got := widget.FormatValue(tt.prefix, tt.v, tt.format, tt.unit); got != tt.want {
+			t.Errorf("FormatValue(%q, %v, %q, %q) = %q, want %q", tt.prefix, tt.v, tt.format, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestThreshold_Crossed(t *testing.T) {
+	var none *widget.Threshold
+	if none.Crossed(1e9) {
+		t.Error("nil threshold should never be crossed")
+	}
+	above := &widget.Threshold{Value: 200}
+	if !above.Crossed(212) || above.Crossed(200) {
+		t.Error("above threshold: expected crossed only for values > 200")
+	}
+	below := &widget.Threshold{Value: 10, Below: true}
+	if !below.Crossed(5) || below.Crossed(10) {
+		t.Error("below threshold: expected crossed only for values < 10")
+	}
+}
+
+func TestPrometheus_Text(t *testing.T) {
+	srv := promStub(t, promVector)
+	p := &widget.Prometheus{
+		Query:     widget.PromQuery{URL: srv.URL, Query: "latency", Label: "quantile", LabelValue: "0.99"},
+		Prefix:    "p99",
+		Unit:      "ms",
+		Scale:     1000,
+		Threshold: &widget.Threshold{Value: 200},
+	}
+	text, alert := p.Text(context.Background())
+	if text != "p99 212ms" {
+		t.Errorf("text = %q, want %q", text, "p99 212ms")
+	}
+	if !alert {
+		t.Error("expected threshold to be crossed")
+	}
+}
+
+func TestPrometheus_FallbackOnError(t *testing.T) {
+	p := &widget.Prometheus{
+		Query:        widget.PromQuery{URL: "http://127.0.0.1:1", Query: "up"},
+		FallbackText: "N/A",
+	}
+	text, alert := p.Text(context.Background())
+	if text != "N/A" || alert {
+		t.Errorf("got (%q, %v), want (\"N/A\", false)", text, alert)
+	}
+}
+
+func TestPrometheus_BlinksWhenThresholdCrossed(t *testing.T) {
+	srv := promStub(t, `{"status":"success","data":{"resultType":"scalar","result":[0,"99"]}}`)
+	spy := &testutil.SpyDisplay{}
+	p := &widget.Prometheus{
+		Query:     widget.PromQuery{URL: srv.URL, Query: "x"},
+		Threshold: &widget.Threshold{Value: 50},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 700*time.Millisecond)
+	defer cancel()
+	p.Run(ctx, spy) //nolint:errcheck
+
+	blank := make([]byte, 32)
+	sawBlank := false
+	for _, f := range spy.Frames {
+		if bytes.Equal(f, blank) {
+			sawBlank = true
+			break
+		}
+	}
+	if !sawBlank {
+		t.Error("expected a blank frame from blink alert style")
+	}
+	if last := spy.Frames[len(spy.Frames)-1]; bytes.Equal(last, blank) {
+		t.Error("expected content to be restored after blinking stops")
+	}
+}
+
+func TestPrometheus_BrightnessAlertStyle(t *testing.T) {
+	srv := promStub(t, `{"status":"success","data":{"resultType":"scalar","result":[0,"99"]}}`)
+	spy := &testutil.SpyDisplay{}
+	restored := false
+	p := &widget.Prometheus{
+		Query:             widget.PromQuery{URL: srv.URL, Query: "x"},
+		Threshold:         &widget.Threshold{Value: 50},
+		AlertStyle:        widget.AlertStyleBrightness,
+		RestoreBrightness: func() { restored = true },
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	p.Run(ctx, spy) //nolint:errcheck
+
+	if len(spy.Brightness) == 0 || spy.Brightness[0] != 15 {
+		t.Errorf("expected brightness boosted to 15, got %v", spy.Brightness)
+	}
+	if !restored {
+		t.Error("expected RestoreBrightness to be called")
+	}
+}
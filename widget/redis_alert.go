@@ -15,6 +15,13 @@ type AlertFetcher interface {
 	DeleteAlert(ctx context.Context, id string) error
 }
 
+// AckTracker merges recorded acknowledgements into alerts before they are
+// shown, and notes which alerts are still waiting for one. Apply must keep
+// the order and length of alerts.
+type AckTracker interface {
+	Apply(alerts []config.AlertConfig) []config.AlertConfig
+}
+
 // RedisAlert wraps alert display with Redis-backed alert fetching.
 // On each Run it fetches alerts from Redis, falling back to the
 // configured JSON alerts on error.
@@ -22,6 +29,12 @@ type RedisAlert struct {
 	Fetcher     AlertFetcher
 	Fallback    []config.AlertConfig
 	ScrollSpeed time.Duration
+	Acks        AckTracker     // optional
+	Schedules   map[int]string // per-priority schedules; nil uses config.DefaultAlertSchedules
+	Profiles    map[string]config.SeverityProfile
+	// HoldBrightness and OnDisplay are passed on to Alert.
+	HoldBrightness func() (release func())
+	OnDisplay      func(ctx context.Context, a config.AlertConfig)
 }
 
 func (ra *RedisAlert) Name() string { return "redis-alert" }
@@ -34,8 +47,13 @@ func (ra *RedisAlert) Run(ctx context.Context, disp display.Display) error {
 	}
 
 	a := &Alert{
-		Alerts:      alerts,
-		ScrollSpeed: ra.ScrollSpeed,
+		Alerts:         alerts,
+		ScrollSpeed:    ra.ScrollSpeed,
+		Acks:           ra.Acks,
+		Schedules:      ra.Schedules,
+		Profiles:       ra.Profiles,
+		HoldBrightness: ra.HoldBrightness,
+		OnDisplay:      ra.OnDisplay,
 		OnDelete: func(ctx context.Context, id string) {
 			if err := ra.Fetcher.DeleteAlert(ctx, id); err != nil {
 				log.Printf("redis alert delete %s: %v", id, err)
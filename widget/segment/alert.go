@@ -9,15 +9,29 @@ import (
 	"github.com/swilcox/led-kurokku-go/display"
 	"github.com/swilcox/led-kurokku-go/internal/cronutil"
 	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
 )
 
-// Alert displays prioritized alert messages on a segment display.
+// Alert displays prioritized alert messages on a segment display. Alerts that
+// require an ack are shown on every run until acknowledged; acknowledged ones
+// are skipped. Alerts outside their time window or schedule are skipped, and
+// expired ones are deleted. Severity profiles use blink-based equivalents:
+// flashes light every segment, inverted text blinks, and the icon is a
+// leading "!" on 14-segment displays. Duplicates and groups are collapsed
+// by widget.GroupAlerts.
 type Alert struct {
 	Alerts      []config.AlertConfig
 	ScrollSpeed time.Duration
 	OnDelete    func(ctx context.Context, id string)
+	OnDisplay   func(ctx context.Context, a config.AlertConfig) // called as each alert is shown
 	NowFunc     func() time.Time
-	Encoder     segfont.Encoder
+	Schedules   map[int]string                    // per-priority schedules; nil uses config.DefaultAlertSchedules
+	Profiles    map[string]config.SeverityProfile // overrides config.DefaultSeverityProfiles
+	Acks        widget.AckTracker                 // optional
+	// HoldBrightness forces maximum brightness for max_brightness profiles
+	// until release is called. When nil the display is set directly.
+	HoldBrightness func() (release func())
+	Encoder        segfont.Encoder
 }
 
 func (a *Alert) now() time.Time {
@@ -33,46 +47,70 @@ func (a *Alert) Run(ctx context.Context, disp display.Display) error {
 	if len(a.Alerts) == 0 {
 		return nil
 	}
+	alerts := a.Alerts
+	if a.Acks != nil {
+		alerts = a.Acks.Apply(alerts)
+	}
 
 	sorted := make([]int, len(a.Alerts))
 	for i := range sorted {
 		sorted[i] = i
 	}
 	sort.SliceStable(sorted, func(i, j int) bool {
-		return a.Alerts[sorted[i]].Priority < a.Alerts[sorted[j]].Priority
+		return alerts[sorted[i]].Priority < alerts[sorted[j]].Priority
 	})
 
-	var toDelete []int
+	var toDelete, shown []int
 	for _, idx := range sorted {
-		alert := a.Alerts[idx]
-		if alert.Priority == 10 && !cronutil.MatchesNow("*/10 * * * *", a.now()) {
+		alert := alerts[idx]
+		now := a.now()
+		if alert.Expired(now) {
+			toDelete = a.deleteAlert(ctx, alert.ID, idx, toDelete)
+			continue
+		}
+		if alert.Acked() {
+			// Kept for the ack record but no longer shown.
+			if alert.DeleteAfterDisplay {
+				toDelete = a.deleteAlert(ctx, alert.ID, idx, toDelete)
+			}
 			continue
 		}
-		dur := alert.DisplayDuration.Unwrap()
+		if !alert.Live(now) {
+			continue
+		}
+		if s := alert.ScheduleFor(a.Schedules); s != "" && !cronutil.MatchesNow(s, now) {
+			continue
+		}
+		shown = append(shown, idx)
+	}
+
+	for _, item := range widget.GroupAlerts(alerts, shown) {
+		dur := item.Alert.DisplayDuration.Unwrap()
 		if dur == 0 {
 			dur = 5 * time.Second
 		}
+		var p config.SeverityProfile
+		if !item.Summary {
+			p = item.Alert.ProfileFor(a.Profiles)
+		}
+		if a.OnDisplay != nil {
+			for _, idx := range item.Indices {
+				a.OnDisplay(ctx, alerts[idx])
+			}
+		}
 
 		alertCtx, cancel := context.WithTimeout(ctx, dur)
-
-		msg := &Message{
-			Text:        alert.Message,
-			ScrollSpeed: a.ScrollSpeed,
-			Repeats:     -1,
-			Encoder:     a.Encoder,
-		}
-		msg.Run(alertCtx, disp)
+		a.present(alertCtx, disp, p, item.Text)
 		cancel()
 
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
 
-		if alert.DeleteAfterDisplay {
-			if a.OnDelete != nil {
-				a.OnDelete(ctx, alert.ID)
-			} else {
-				toDelete = append(toDelete, idx)
+		for _, idx := range item.Indices {
+			// Alerts waiting for an ack repeat until they get one.
+			if alert := alerts[idx]; alert.DeleteAfterDisplay && !alert.Pending() {
+				toDelete = a.deleteAlert(ctx, alert.ID, idx, toDelete)
 			}
 		}
 	}
@@ -86,3 +124,38 @@ func (a *Alert) Run(ctx context.Context, disp display.Display) error {
 
 	return nil
 }
+
+// present shows text until ctx is done, styled by a severity profile.
+func (a *Alert) present(ctx context.Context, disp display.Display, p config.SeverityProfile, text string) {
+	if p.MaxBrightness {
+		defer widget.MaxBrightness(disp, a.HoldBrightness)()
+	}
+	if widget.Flash(ctx, disp, p.Flashes, p.FlashInterval.Unwrap(), segmentMask(a.Encoder)) != nil {
+		return
+	}
+	if p.Invert {
+		bd, stop := widget.Blink(ctx, disp, 250*time.Millisecond)
+		defer stop()
+		disp = bd
+	}
+	if p.Icon && segfont.Is14(a.Encoder) {
+		text = "!" + text
+	}
+	msg := &Message{
+		Text:        text,
+		ScrollSpeed: a.ScrollSpeed,
+		Repeats:     -1,
+		Encoder:     a.Encoder,
+	}
+	msg.Run(ctx, disp)
+}
+
+// deleteAlert removes an alert through OnDelete, or queues its index for
+// removal from Alerts when there is no OnDelete.
+func (a *Alert) deleteAlert(ctx context.Context, id string, idx int, toDelete []int) []int {
+	if a.OnDelete != nil {
+		a.OnDelete(ctx, id)
+		return toDelete
+	}
+	return append(toDelete, idx)
+}
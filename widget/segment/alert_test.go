@@ -73,3 +73,131 @@ func TestSegmentAlert_DeleteAfterDisplay(t *testing.T) {
 		t.Errorf("expected alert to be deleted after display, got %d remaining", len(a.Alerts))
 	}
 }
+
+func TestSegmentAlert_PendingKeptUntilAcked(t *testing.T) {
+	spy := &testutil.SpySegmentDisplay{}
+	a := &segment.Alert{
+		Alerts: []config.AlertConfig{
+			{
+				ID:                 "ack",
+				Message:            "ACK",
+				Priority:           1,
+				DisplayDuration:    config.Duration(20 * time.Millisecond),
+				DeleteAfterDisplay: true,
+				AckRequired:        true,
+			},
+		},
+		ScrollSpeed: time.Millisecond,
+		Encoder:     segfont.Enc7,
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+	if len(a.Alerts) != 1 {
+		t.Fatalf("expected pending alert to be kept, got %d remaining", len(a.Alerts))
+	}
+	if len(spy.Calls) == 0 {
+		t.Error("expected pending alert to be shown")
+	}
+
+	now := time.Now()
+	a.Alerts[0].AckedAt = &now
+	spy.Calls = nil
+	a.Run(context.Background(), spy) //nolint:errcheck
+	if len(a.Alerts) != 0 {
+		t.Errorf("expected acked alert to be deleted, got %d remaining", len(a.Alerts))
+	}
+	if len(spy.Calls) != 0 {
+		t.Errorf("expected acked alert not to be shown, got %d calls", len(spy.Calls))
+	}
+}
+
+func TestSegmentAlert_ExpiredRemoved(t *testing.T) {
+	spy := &testutil.SpySegmentDisplay{}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	expired, later := now.Add(-time.Minute), now.Add(time.Hour)
+	a := &segment.Alert{
+		Alerts: []config.AlertConfig{
+			{ID: "old", Message: "OLD", Priority: 1, ExpiresAt: &expired},
+			{ID: "snz", Message: "SNZ", Priority: 1, SnoozeUntil: &later},
+		},
+		ScrollSpeed: time.Millisecond,
+		NowFunc:     func() time.Time { return now },
+		Encoder:     segfont.Enc7,
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+
+	if len(spy.Calls) != 0 {
+		t.Errorf("expected nothing shown, got %d calls", len(spy.Calls))
+	}
+	if len(a.Alerts) != 1 || a.Alerts[0].ID != "snz" {
+		t.Errorf("expected only the snoozed alert to remain, got %+v", a.Alerts)
+	}
+}
+
+func TestSegmentAlert_CriticalProfile(t *testing.T) {
+	spy := &testutil.SpySegmentDisplay{}
+	a := &segment.Alert{
+		Alerts: []config.AlertConfig{
+			{ID: "c", Message: "HOT", Priority: 1, Severity: config.SeverityCritical, DisplayDuration: config.Duration(600 * time.Millisecond)},
+		},
+		Profiles: map[string]config.SeverityProfile{
+			config.SeverityCritical: {Flashes: 1, FlashInterval: config.Duration(time.Millisecond), Invert: true, MaxBrightness: true, Icon: true},
+		},
+		ScrollSpeed: time.Millisecond,
+		Encoder:     segfont.Enc14,
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+
+	if len(spy.Brightness) != 1 || spy.Brightness[0] != 15 {
+		t.Errorf("brightness = %v, want [15]", spy.Brightness)
+	}
+	if spy.Calls[0].Segments[0] != 0x3FFF {
+		t.Errorf("flash = %#x, want all 14 segments", spy.Calls[0].Segments[0])
+	}
+	text := spy.Calls[2].Segments
+	if text[0] != segfont.Enc14('!') || text[1] != segfont.Enc14('H') {
+		t.Errorf("text = %#v, want leading ! icon", text)
+	}
+	blanks := 0
+	for _, c := range spy.Calls[3:] {
+		if c.Segments[0] == 0 {
+			blanks++
+		}
+	}
+	if blanks == 0 {
+		t.Error("expected the text to blink")
+	}
+}
+
+func TestSegmentAlert_GroupSummary(t *testing.T) {
+	spy := &testutil.SpySegmentDisplay{}
+	a := &segment.Alert{
+		Alerts: []config.AlertConfig{
+			{ID: "a", Message: "A", Priority: 1, Group: "g", Severity: config.SeverityCritical, DisplayDuration: config.Duration(5 * time.Millisecond)},
+			{ID: "b", Message: "B", Priority: 1, Group: "g", DisplayDuration: config.Duration(5 * time.Millisecond)},
+		},
+		Profiles:    map[string]config.SeverityProfile{config.SeverityCritical: {}},
+		ScrollSpeed: time.Millisecond,
+		Encoder:     segfont.Enc14,
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+
+	if len(spy.Calls) == 0 {
+		t.Fatal("expected segment writes")
+	}
+	// "1 CRIT, 1 INFO" scrolls in from the right, passing the window "1 CR".
+	want := segfont.EncodeText(segfont.Enc14, "1 CR")
+	for _, c := range spy.Calls {
+		match := true
+		for i, s := range want {
+			match = match && c.Segments[i] == s
+		}
+		if match {
+			return
+		}
+	}
+	t.Error("expected the group summary to be shown")
+}
@@ -0,0 +1,44 @@
+package segment
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Calendar shows the next calendar event with a countdown on a segment display.
+type Calendar struct {
+	Source      *widget.CalendarSource
+	LookAhead   time.Duration
+	ShowCurrent bool
+	ScrollSpeed time.Duration
+	NowFunc     func() time.Time
+	Encoder     segfont.Encoder
+}
+
+func (c *Calendar) Name() string { return "segment-calendar" }
+
+func (c *Calendar) Run(ctx context.Context, disp display.Display) error {
+	cw := &widget.Calendar{
+		Source:      c.Source,
+		LookAhead:   c.LookAhead,
+		ShowCurrent: c.ShowCurrent,
+		NowFunc:     c.NowFunc,
+	}
+	if _, ok := cw.Text(ctx); !ok {
+		return nil
+	}
+	return widget.RunWatched(ctx, 30*time.Second, func() string {
+		text, _ := cw.Text(ctx)
+		return text
+	}, func(ctx context.Context, text string) error {
+		if text == "" {
+			return nil
+		}
+		m := &Message{Text: text, ScrollSpeed: c.ScrollSpeed, Repeats: -1, Encoder: c.Encoder}
+		return m.Run(ctx, disp)
+	})
+}
@@ -0,0 +1,48 @@
+package segment
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Chart scrolls a numeric summary of a Redis series ("12.3 lo 4 hi 20"),
+// since a segment display cannot draw the chart itself.
+type Chart struct {
+	Fetcher     widget.SeriesFetcher
+	Key         string
+	Samples     int
+	Prefix      string
+	Unit        string
+	Format      string
+	Interval    time.Duration
+	ScrollSpeed time.Duration
+	Encoder     segfont.Encoder
+}
+
+func (c *Chart) Name() string { return "segment-chart" }
+
+func (c *Chart) Run(ctx context.Context, disp display.Display) error {
+	samples := c.Samples
+	if samples == 0 {
+		samples = 32
+	}
+	interval := c.Interval
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+	return widget.RunWatched(ctx, interval, func() string {
+		series, err := c.Fetcher.FetchSeries(ctx, c.Key, samples)
+		if err != nil {
+			log.Printf("chart %s: %v", c.Key, err)
+		}
+		return widget.ChartSummary(series, c.Prefix, c.Format, c.Unit)
+	}, func(ctx context.Context, text string) error {
+		m := &Message{Text: text, ScrollSpeed: c.ScrollSpeed, Repeats: -1, Encoder: c.Encoder}
+		return m.Run(ctx, disp)
+	})
+}
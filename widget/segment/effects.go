@@ -0,0 +1,249 @@
+package segment
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// segmentMask returns the all-segments mask for enc's digit type.
+func segmentMask(enc segfont.Encoder) uint16 {
+	if segfont.Is14(enc) {
+		return mask14
+	}
+	return mask7
+}
+
+// Spinner spins every digit: a two-segment comet chasing round the outer
+// ring on 7-segment displays, a bar turning through | / - \ on 14-segment
+// ones. Density does not apply.
+type Spinner struct {
+	Params  widget.AnimParams
+	Encoder segfont.Encoder
+}
+
+func (s *Spinner) Name() string { return "segment-spinner" }
+
+func (s *Spinner) Run(ctx context.Context, disp display.Display) error {
+	sd := disp.(display.SegmentDisplay)
+	n := sd.DisplayLength()
+
+	var frames []uint16
+	if segfont.Is14(s.Encoder) {
+		frames = []uint16{
+			0x1200, // I, L
+			0x0C00, // J, K
+			0x00C0, // G1, G2
+			0x2100, // H, M
+		}
+	} else {
+		ring := []uint16{0x01, 0x02, 0x04, 0x08, 0x10, 0x20} // a b c d e f
+		for i, seg := range ring {
+			frames = append(frames, seg|ring[(i+len(ring)-1)%len(ring)])
+		}
+	}
+
+	ticker := time.NewTicker(s.Params.Interval(100 * time.Millisecond))
+	defer ticker.Stop()
+
+	for k := 0; ; k++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		segments := make([]uint16, n)
+		for i := range segments {
+			segments[i] = frames[k%len(frames)]
+		}
+		writeSegments(sd, segments, false, s.Params, segmentMask(s.Encoder))
+	}
+}
+
+// LoadingBar fills the display left to right one vertical at a time (two
+// per digit on 7-segment displays, five on 14-segment), blinks when full
+// and starts over. Density is the chance the bar advances on each frame
+// (default 0.7), so it stalls now and then like a real progress bar.
+type LoadingBar struct {
+	Params  widget.AnimParams
+	Encoder segfont.Encoder
+}
+
+func (l *LoadingBar) Name() string { return "segment-loading" }
+
+func (l *LoadingBar) Run(ctx context.Context, disp display.Display) error {
+	sd := disp.(display.SegmentDisplay)
+	n := sd.DisplayLength()
+	rng := l.Params.Rand()
+	advance := l.Params.DensityOr(0.7)
+
+	bars := []uint16{0x30, 0x06} // e+f, b+c
+	if segfont.Is14(l.Encoder) {
+		bars = []uint16{0x0030, 0x0900, 0x1200, 0x2400, 0x0006}
+	}
+	total := n * len(bars)
+
+	level, done := 0, 0
+
+	ticker := time.NewTicker(l.Params.Interval(120 * time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		switch {
+		case level < total:
+			if rng.Float64() < advance {
+				level++
+			}
+		case done < 6:
+			done++
+		default:
+			level, done = 0, 0
+		}
+
+		segments := make([]uint16, n)
+		if done%2 == 0 { // blink three times when full
+			for i := 0; i < level; i++ {
+				segments[i/len(bars)] |= bars[i%len(bars)]
+			}
+		}
+		writeSegments(sd, segments, false, l.Params, segmentMask(l.Encoder))
+	}
+}
+
+// SlotMachine rolls every digit through 0-9 and stops the reels one by one
+// from the left on a random result; three or more of a kind blink. Density
+// does not apply; Seed sets the results.
+type SlotMachine struct {
+	Params  widget.AnimParams
+	Encoder segfont.Encoder
+}
+
+func (s *SlotMachine) Name() string { return "segment-slots" }
+
+func (s *SlotMachine) enc() segfont.Encoder {
+	if s.Encoder != nil {
+		return s.Encoder
+	}
+	return segfont.Enc7
+}
+
+func (s *SlotMachine) Run(ctx context.Context, disp display.Display) error {
+	sd := disp.(display.SegmentDisplay)
+	n := sd.DisplayLength()
+	rng := s.Params.Rand()
+	enc := s.enc()
+
+	const (
+		spinUp  = 12 // frames before the first reel stops
+		stagger = 6  // frames between reels stopping
+		hold    = 25 // frames the result stays up
+	)
+	result := make([]int, n)
+	offset := make([]int, n)
+	jackpot := false
+	newRound := func() {
+		counts := make(map[int]int)
+		for i := range result {
+			result[i], offset[i] = rng.Intn(10), rng.Intn(10)
+			counts[result[i]]++
+		}
+		jackpot = false
+		for _, c := range counts {
+			jackpot = jackpot || c >= 3
+		}
+	}
+	newRound()
+
+	ticker := time.NewTicker(s.Params.Interval(70 * time.Millisecond))
+	defer ticker.Stop()
+
+	tick := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		stopped := spinUp + stagger*(n-1)
+		if tick >= stopped+hold {
+			newRound()
+			tick = 0
+		}
+
+		segments := make([]uint16, n)
+		for i := range segments {
+			d := result[i]
+			if tick < spinUp+stagger*i {
+				d = (offset[i] + tick) % 10
+			}
+			segments[i] = enc(rune('0' + d))
+		}
+		if jackpot && tick > stopped && (tick/3)%2 == 1 {
+			segments = make([]uint16, n)
+		}
+		writeSegments(sd, segments, false, s.Params, segmentMask(enc))
+		tick++
+	}
+}
+
+// Bounce moves a single horizontal segment diagonally between the top,
+// middle and bottom rows, bouncing off the ends of the display. Density and
+// Seed do not apply.
+type Bounce struct {
+	Params  widget.AnimParams
+	Encoder segfont.Encoder
+}
+
+func (b *Bounce) Name() string { return "segment-bounce" }
+
+func (b *Bounce) Run(ctx context.Context, disp display.Display) error {
+	sd := disp.(display.SegmentDisplay)
+	n := sd.DisplayLength()
+	if n == 0 {
+		return nil
+	}
+
+	rows := []uint16{0x01, 0x40, 0x08} // a, g, d
+	if segfont.Is14(b.Encoder) {
+		rows[1] = 0xC0 // G1, G2
+	}
+
+	x, y, dx, dy := 0, 0, 1, 1
+	if n == 1 {
+		dx = 0
+	}
+
+	ticker := time.NewTicker(b.Params.Interval(150 * time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		segments := make([]uint16, n)
+		segments[x] = rows[y]
+		writeSegments(sd, segments, false, b.Params, segmentMask(b.Encoder))
+
+		if x+dx < 0 || x+dx >= n {
+			dx = -dx
+		}
+		if y+dy < 0 || y+dy >= len(rows) {
+			dy = -dy
+		}
+		x, y = x+dx, y+dy
+	}
+}
@@ -0,0 +1,116 @@
+package segment_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+	"github.com/swilcox/led-kurokku-go/widget/segment"
+)
+
+func runSegments(t *testing.T, name string, enc segfont.Encoder, d time.Duration) [][]uint16 {
+	t.Helper()
+	spy := &testutil.SpySegmentDisplay{}
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	segment.Registry[name](widget.AnimParams{Speed: 10, Seed: 1}, enc).Run(ctx, spy) //nolint:errcheck
+	if len(spy.Calls) == 0 {
+		t.Fatalf("%s: expected segment writes", name)
+	}
+	var out [][]uint16
+	for _, c := range spy.Calls {
+		out = append(out, c.Segments)
+	}
+	return out
+}
+
+func TestSegmentEffects_StayWithinDigitType(t *testing.T) {
+	for _, name := range []string{"spinner", "loading", "slots", "life", "bounce"} {
+		for _, enc := range []struct {
+			name string
+			enc  segfont.Encoder
+			mask uint16
+		}{{"7", segfont.Enc7, 0x7F}, {"14", segfont.Enc14, 0x3FFF}} {
+			calls := runSegments(t, name, enc.enc, 200*time.Millisecond)
+			changed := false
+			for _, segs := range calls {
+				for i, s := range segs {
+					if s&^enc.mask != 0 {
+						t.Fatalf("%s/%s: digit %d = %#x outside mask %#x", name, enc.name, i, s, enc.mask)
+					}
+					if s != calls[0][i] {
+						changed = true
+					}
+				}
+			}
+			if !changed {
+				t.Errorf("%s/%s: segments never changed", name, enc.name)
+			}
+		}
+	}
+}
+
+func TestSegmentSpinner_14SegmentUsesCentreBar(t *testing.T) {
+	calls := runSegments(t, "spinner", segfont.Enc14, 100*time.Millisecond)
+	if calls[0][0] != 0x1200 {
+		t.Errorf("first 14-segment spinner frame = %#x, want the vertical centre bar 0x1200", calls[0][0])
+	}
+}
+
+func TestSegmentLoadingBar_FillsLeftToRight(t *testing.T) {
+	calls := runSegments(t, "loading", segfont.Enc7, 300*time.Millisecond)
+	full := false
+	for _, segs := range calls {
+		lit := 0
+		for _, s := range segs {
+			if s == 0x36 {
+				lit++
+			}
+		}
+		// Digits fill in order: no lit digit may follow an unfilled one.
+		for i := 1; i < len(segs); i++ {
+			if segs[i] != 0 && segs[i-1] != 0x36 {
+				t.Fatalf("bar not contiguous: %#x", segs)
+			}
+		}
+		full = full || lit == len(segs)
+	}
+	if !full {
+		t.Error("expected the bar to fill the display")
+	}
+}
+
+func TestSegmentSlotMachine_ShowsDigits(t *testing.T) {
+	calls := runSegments(t, "slots", segfont.Enc7, 150*time.Millisecond)
+	digits := make(map[uint16]bool)
+	for r := '0'; r <= '9'; r++ {
+		digits[segfont.Enc7(r)] = true
+	}
+	for _, segs := range calls {
+		for _, s := range segs {
+			if s != 0 && !digits[s] {
+				t.Fatalf("slot reel shows %#x, not a digit", s)
+			}
+		}
+	}
+}
+
+func TestSegmentBounce_OneSegmentAtATime(t *testing.T) {
+	for _, segs := range runSegments(t, "bounce", segfont.Enc7, 100*time.Millisecond) {
+		lit := 0
+		for _, s := range segs {
+			if s != 0 {
+				lit++
+				if s != 0x01 && s != 0x40 && s != 0x08 {
+					t.Fatalf("bounce lit %#x, want a, g or d", s)
+				}
+			}
+		}
+		if lit != 1 {
+			t.Fatalf("bounce lit %d digits, want 1", lit)
+		}
+	}
+}
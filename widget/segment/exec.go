@@ -0,0 +1,29 @@
+package segment
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Exec displays the output of a local command on a segment display.
+type Exec struct {
+	Runner            *widget.CommandRunner
+	FallbackText      string
+	AlertStyle        string
+	ScrollSpeed       time.Duration
+	RestoreBrightness func()
+	Encoder           segfont.Encoder
+}
+
+func (e *Exec) Name() string { return "segment-exec" }
+
+func (e *Exec) Run(ctx context.Context, disp display.Display) error {
+	return widget.RunCommand(ctx, disp, e.Runner, e.FallbackText, e.AlertStyle, e.RestoreBrightness, func(ctx context.Context, disp display.Display, text string) error {
+		m := &Message{Text: text, ScrollSpeed: e.ScrollSpeed, Repeats: -1, Encoder: e.Encoder}
+		return m.Run(ctx, disp)
+	})
+}
@@ -0,0 +1,59 @@
+package segment
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Feed scrolls the newest unseen feed headlines on a segment display.
+type Feed struct {
+	Sources      []*widget.FeedSource
+	Count        int
+	Seen         widget.HeadlineStore
+	FallbackText string
+	ScrollSpeed  time.Duration
+	Encoder      segfont.Encoder
+
+	once  sync.Once
+	inner *widget.Feed // keeps the in-memory seen set across runs
+}
+
+func (f *Feed) Name() string { return "segment-feed" }
+
+func (f *Feed) Run(ctx context.Context, disp display.Display) error {
+	f.once.Do(func() {
+		f.inner = &widget.Feed{Sources: f.Sources, Count: f.Count, Seen: f.Seen}
+	})
+	dispLen := disp.(display.SegmentDisplay).DisplayLength()
+
+	items := f.inner.Headlines(ctx)
+	if len(items) == 0 {
+		if f.FallbackText == "" {
+			return nil
+		}
+		m := &Message{Text: f.FallbackText, ScrollSpeed: f.ScrollSpeed, Repeats: -1, Encoder: f.Encoder}
+		return m.Run(ctx, disp)
+	}
+
+	for _, it := range items {
+		m := &Message{Text: it.Title, ScrollSpeed: f.ScrollSpeed, Repeats: 1, Encoder: f.Encoder}
+		if len([]rune(it.Title)) <= dispLen {
+			// Short headlines would otherwise hold the display indefinitely.
+			hctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+			m.Run(hctx, disp)
+			cancel()
+		} else {
+			m.Run(ctx, disp)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		f.inner.MarkSeen(ctx, it)
+	}
+	return nil
+}
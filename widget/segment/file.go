@@ -0,0 +1,29 @@
+package segment
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// FileTail displays the last matching line of a local file on a segment display.
+type FileTail struct {
+	Watcher      *widget.LineWatcher
+	FallbackText string
+	Interval     time.Duration
+	ScrollSpeed  time.Duration
+	Encoder      segfont.Encoder
+}
+
+func (f *FileTail) Name() string { return "segment-file" }
+
+func (f *FileTail) Run(ctx context.Context, disp display.Display) error {
+	return widget.RunWatched(ctx, f.Interval, func() string { return f.Watcher.Text(f.FallbackText) },
+		func(ctx context.Context, text string) error {
+			m := &Message{Text: text, ScrollSpeed: f.ScrollSpeed, Repeats: -1, Encoder: f.Encoder}
+			return m.Run(ctx, disp)
+		})
+}
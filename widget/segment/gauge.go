@@ -0,0 +1,88 @@
+package segment
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Gauge shows a numeric reading on a segment display. The percent style
+// shows only the percentage; bar and meter alternate between the percentage
+// and a bar drawn with the vertical segments of each digit.
+type Gauge struct {
+	Source            widget.ValueSource
+	Min               float64
+	Max               *float64
+	Style             string
+	Prefix            string
+	Format            string
+	Threshold         *widget.Threshold
+	AlertStyle        string
+	Interval          time.Duration
+	RestoreBrightness func()
+	Encoder           segfont.Encoder
+}
+
+func (g *Gauge) Name() string { return "segment-gauge" }
+
+func (g *Gauge) Run(ctx context.Context, disp display.Display) error {
+	gw := &widget.Gauge{
+		Source:            g.Source,
+		Min:               g.Min,
+		Max:               g.Max,
+		Style:             g.Style,
+		Prefix:            g.Prefix,
+		Format:            g.Format,
+		Threshold:         g.Threshold,
+		AlertStyle:        g.AlertStyle,
+		Interval:          g.Interval,
+		RestoreBrightness: g.RestoreBrightness,
+	}
+	return widget.RunGauge(ctx, disp, gw, func(ctx context.Context, disp display.Display, v float64, ok bool) error {
+		sd := disp.(display.SegmentDisplay)
+		text := "--"
+		if ok {
+			text = gw.PercentText(v)
+		}
+		m := &Message{Text: text, Repeats: 1, Encoder: g.Encoder}
+		if !ok || gw.Style == widget.GaugePercent {
+			return m.Run(ctx, disp)
+		}
+		frac, _ := gw.Fraction(v)
+		bar := GaugeSegments(frac, sd.DisplayLength())
+		for {
+			tctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+			m.Run(tctx, disp)
+			cancel()
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			sd.WriteSegments(bar, false)
+			if err := widget.SleepOrCancel(ctx, 2*time.Second); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// GaugeSegments draws frac as a left-to-right bar over n digits. Each digit
+// holds two steps: its left verticals (e, f) then its right verticals (b, c).
+// The bits are the same on 7- and 14-segment displays.
+func GaugeSegments(frac float64, n int) []uint16 {
+	const left, right = 0x30, 0x06
+	segs := make([]uint16, n)
+	lit := int(math.Round(frac * float64(2*n)))
+	for i := range segs {
+		switch {
+		case lit >= 2*(i+1):
+			segs[i] = left | right
+		case lit == 2*i+1:
+			segs[i] = left
+		}
+	}
+	return segs
+}
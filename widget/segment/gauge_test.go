@@ -0,0 +1,26 @@
+package segment_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/swilcox/led-kurokku-go/widget/segment"
+)
+
+func TestGaugeSegments(t *testing.T) {
+	tests := []struct {
+		frac float64
+		want []uint16
+	}{
+		{0, []uint16{0, 0, 0, 0}},
+		{0.125, []uint16{0x30, 0, 0, 0}},
+		{0.5, []uint16{0x36, 0x36, 0, 0}},
+		{0.625, []uint16{0x36, 0x36, 0x30, 0}},
+		{1, []uint16{0x36, 0x36, 0x36, 0x36}},
+	}
+	for _, tt := range tests {
+		if got := segment.GaugeSegments(tt.frac, 4); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("GaugeSegments(%v) = %#x, want %#x", tt.frac, got, tt.want)
+		}
+	}
+}
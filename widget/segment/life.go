@@ -0,0 +1,178 @@
+package segment
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Life runs a Game of Life whose cells are the segments themselves. Two
+// segments are neighbours when they touch within a digit, or face each
+// other across adjacent digits (a to a, b to the next digit's f, and so on).
+// Because segments have far fewer neighbours than grid cells, a dead segment
+// is born with two live neighbours and a live one survives with one or two.
+// The board re-seeds when it dies out or stops changing. Density is the
+// fraction of segments lit when seeding (default 0.4).
+type Life struct {
+	Params  widget.AnimParams
+	Encoder segfont.Encoder
+}
+
+func (l *Life) Name() string { return "segment-life" }
+
+// Segment endpoints within a digit, for working out which segments touch.
+const (
+	vTL = iota // top left corner
+	vTC        // top centre (14-segment only)
+	vTR
+	vML
+	vC // centre (14-segment only)
+	vMR
+	vBL
+	vBC // bottom centre (14-segment only)
+	vBR
+)
+
+// Endpoints of each segment, indexed by bit.
+var (
+	lifeEnds7 = [][]int{
+		{vTL, vTR}, {vTR, vMR}, {vMR, vBR}, {vBL, vBR}, // a b c d
+		{vML, vBL}, {vTL, vML}, {vML, vMR}, // e f g
+	}
+	lifeEnds14 = [][]int{
+		{vTL, vTC, vTR}, {vTR, vMR}, {vMR, vBR}, {vBL, vBC, vBR}, // A B C D
+		{vML, vBL}, {vTL, vML}, {vML, vC}, {vC, vMR}, // E F G1 G2
+		{vTL, vC}, {vTC, vC}, {vTR, vC}, // H I J
+		{vBL, vC}, {vC, vBC}, {vBR, vC}, // K L M
+	}
+)
+
+// Segments linked to a segment of the next digit: (this digit, next digit).
+var (
+	lifeLinks7  = [][2]int{{0, 0}, {6, 6}, {3, 3}, {1, 5}, {2, 4}}
+	lifeLinks14 = [][2]int{{0, 0}, {7, 6}, {3, 3}, {1, 5}, {2, 4}}
+)
+
+// lifeGraph returns the neighbour lists of every segment on an n-digit
+// display; segment s of digit d is cell d*len(ends)+s.
+func lifeGraph(n int, ends [][]int, links [][2]int) [][]int {
+	per := len(ends)
+	nbrs := make([][]int, n*per)
+	for d := 0; d < n; d++ {
+		for s := 0; s < per; s++ {
+			for t := 0; t < per; t++ {
+				if s != t && shareEnd(ends[s], ends[t]) {
+					nbrs[d*per+s] = append(nbrs[d*per+s], d*per+t)
+				}
+			}
+		}
+		if d+1 < n {
+			for _, l := range links {
+				a, b := d*per+l[0], (d+1)*per+l[1]
+				nbrs[a] = append(nbrs[a], b)
+				nbrs[b] = append(nbrs[b], a)
+			}
+		}
+	}
+	return nbrs
+}
+
+func shareEnd(a, b []int) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lifeStep advances cells one generation over the neighbour graph.
+func lifeStep(cells []bool, nbrs [][]int) []bool {
+	next := make([]bool, len(cells))
+	for i := range cells {
+		live := 0
+		for _, j := range nbrs[i] {
+			if cells[j] {
+				live++
+			}
+		}
+		if cells[i] {
+			next[i] = live == 1 || live == 2
+		} else {
+			next[i] = live == 2
+		}
+	}
+	return next
+}
+
+func (l *Life) Run(ctx context.Context, disp display.Display) error {
+	sd := disp.(display.SegmentDisplay)
+	n := sd.DisplayLength()
+	rng := l.Params.Rand()
+	density := l.Params.DensityOr(0.4)
+
+	ends, links := lifeEnds7, lifeLinks7
+	if segfont.Is14(l.Encoder) {
+		ends, links = lifeEnds14, lifeLinks14
+	}
+	per := len(ends)
+	nbrs := lifeGraph(n, ends, links)
+
+	seed := func() []bool {
+		cells := make([]bool, n*per)
+		for i := range cells {
+			cells[i] = rng.Float64() < density
+		}
+		return cells
+	}
+	cells := seed()
+	var prev []bool // two generations back, to catch blinkers
+	stagnant := 0
+
+	ticker := time.NewTicker(l.Params.Interval(250 * time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		segments := make([]uint16, n)
+		for i, on := range cells {
+			if on {
+				segments[i/per] |= 1 << (i % per)
+			}
+		}
+		writeSegments(sd, segments, false, l.Params, segmentMask(l.Encoder))
+
+		next := lifeStep(cells, nbrs)
+		if equalCells(next, cells) || equalCells(next, prev) {
+			stagnant++
+		} else {
+			stagnant = 0
+		}
+		prev, cells = cells, next
+		if stagnant >= 3 {
+			cells, prev, stagnant = seed(), nil, 0
+		}
+	}
+}
+
+func equalCells(a, b []bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
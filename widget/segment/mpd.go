@@ -0,0 +1,31 @@
+package segment
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/mpd"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// NowPlaying shows the current MPD song on a segment display.
+type NowPlaying struct {
+	Client      *mpd.Client
+	ScrollSpeed time.Duration
+	Encoder     segfont.Encoder
+}
+
+func (n *NowPlaying) Name() string { return "segment-mpd" }
+
+func (n *NowPlaying) Run(ctx context.Context, disp display.Display) error {
+	return widget.RunNowPlaying(ctx, n.Client, func(ctx context.Context, text string) error {
+		m := &Message{Text: text, ScrollSpeed: n.ScrollSpeed, Repeats: -1, Encoder: n.Encoder}
+		return m.Run(ctx, disp)
+	})
+}
+
+func (n *NowPlaying) Interrupts(ctx context.Context) <-chan struct{} {
+	return widget.WatchNowPlaying(ctx, n.Client)
+}
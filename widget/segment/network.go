@@ -0,0 +1,49 @@
+package segment
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Network pages through interface addresses, Wi-Fi signal and service
+// reachability on a segment display, one short page per Interval.
+type Network struct {
+	Probe       widget.NetworkProbe
+	Interval    time.Duration
+	ScrollSpeed time.Duration
+	Encoder     segfont.Encoder
+}
+
+func (n *Network) Name() string { return "segment-network" }
+
+func (n *Network) Run(ctx context.Context, disp display.Display) error {
+	interval := n.Interval
+	if interval == 0 {
+		interval = 1500 * time.Millisecond
+	}
+	dispLen := disp.(display.SegmentDisplay).DisplayLength()
+	pages := n.Probe.Status(ctx).Pages()
+
+	for {
+		for _, page := range pages {
+			m := &Message{Text: page, ScrollSpeed: n.ScrollSpeed, Repeats: 1, Encoder: n.Encoder}
+			if len([]rune(page)) > dispLen {
+				// Long pages (SSIDs, interface names) scroll through once.
+				if err := m.Run(ctx, disp); err != nil {
+					return err
+				}
+				continue
+			}
+			pctx, cancel := context.WithTimeout(ctx, interval)
+			m.Run(pctx, disp)
+			cancel()
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+}
@@ -6,27 +6,87 @@ import (
 	"time"
 
 	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
 	"github.com/swilcox/led-kurokku-go/widget"
 )
 
-// Registry maps segment animation type names to constructors.
-var Registry = map[string]func() widget.Widget{
-	"rain":    func() widget.Widget { return &Rain{} },
-	"static":  func() widget.Widget { return &Static{} },
-	"scanner": func() widget.Widget { return &Scanner{} },
-	"race":    func() widget.Widget { return &Race{} },
+// Registry maps segment animation type names to constructors. enc is the
+// display's encoder; animations with a 14-segment variant use it when enc
+// is a 14-segment font.
+var Registry = map[string]func(p widget.AnimParams, enc segfont.Encoder) widget.Widget{
+	"rain": func(p widget.AnimParams, enc segfont.Encoder) widget.Widget {
+		if segfont.Is14(enc) {
+			return &Rain14{Params: p}
+		}
+		return &Rain{Params: p}
+	},
+	"static": func(p widget.AnimParams, enc segfont.Encoder) widget.Widget {
+		if segfont.Is14(enc) {
+			return &Static14{Params: p}
+		}
+		return &Static{Params: p}
+	},
+	"scanner": func(p widget.AnimParams, enc segfont.Encoder) widget.Widget {
+		if segfont.Is14(enc) {
+			return &Scanner14{Params: p}
+		}
+		return &Scanner{Params: p}
+	},
+	"race": func(p widget.AnimParams, enc segfont.Encoder) widget.Widget {
+		if segfont.Is14(enc) {
+			return &Race14{Params: p}
+		}
+		return &Race{Params: p}
+	},
+	"spinner": func(p widget.AnimParams, enc segfont.Encoder) widget.Widget {
+		return &Spinner{Params: p, Encoder: enc}
+	},
+	"loading": func(p widget.AnimParams, enc segfont.Encoder) widget.Widget {
+		return &LoadingBar{Params: p, Encoder: enc}
+	},
+	"slots": func(p widget.AnimParams, enc segfont.Encoder) widget.Widget {
+		return &SlotMachine{Params: p, Encoder: enc}
+	},
+	"life": func(p widget.AnimParams, enc segfont.Encoder) widget.Widget {
+		return &Life{Params: p, Encoder: enc}
+	},
+	"bounce": func(p widget.AnimParams, enc segfont.Encoder) widget.Widget {
+		return &Bounce{Params: p, Encoder: enc}
+	},
 }
 
-// Rain simulates segments lighting up and "falling" through each digit.
-// For 7-segment: top → middle → bottom segments cycle.
-// For 14-segment: top → middle → bottom segments cycle with diagonals.
-type Rain struct{}
+// Segment masks used when inverting: all segments of a 7- or 14-segment digit.
+const (
+	mask7  = 0x7F
+	mask14 = 0x3FFF
+)
+
+// writeSegments sends segments to the display, inverted within mask when the
+// params ask for it.
+func writeSegments(sd display.SegmentDisplay, segments []uint16, colon bool, p widget.AnimParams, mask uint16) {
+	if p.Invert {
+		for i := range segments {
+			segments[i] = ^segments[i] & mask
+		}
+	}
+	sd.WriteSegments(segments, colon)
+}
+
+// Rain simulates segments lighting up and "falling" through each digit:
+// top → middle → bottom. See Rain14 for the 14-segment variant.
+// Density is the chance per tick that an idle digit starts a drop
+// (default 1/6).
+type Rain struct {
+	Params widget.AnimParams
+}
 
 func (r *Rain) Name() string { return "segment-rain" }
 
 func (r *Rain) Run(ctx context.Context, disp display.Display) error {
 	sd := disp.(display.SegmentDisplay)
 	n := sd.DisplayLength()
+	rng := r.Params.Rand()
+	density := r.Params.DensityOr(1.0 / 6)
 
 	// Each digit tracks a "drop" position (0-5 = falling stages, -1 = inactive)
 	drops := make([]int, n)
@@ -35,7 +95,7 @@ func (r *Rain) Run(ctx context.Context, disp display.Display) error {
 	}
 
 	// 7-segment rain stages: top(a) → upper-sides(f,b) → middle(g) → lower-sides(e,c) → bottom(d) → off
-	seg7Stages := []uint16{
+	stages := []uint16{
 		0x01, // a
 		0x22, // f, b
 		0x40, // g
@@ -44,30 +104,7 @@ func (r *Rain) Run(ctx context.Context, disp display.Display) error {
 		0x00, // off
 	}
 
-	// 14-segment rain stages: top(A) → diagonals(H,J)+verticals(F,B) → middle(G1,G2) →
-	//   diagonals(K,M)+verticals(E,C) → bottom(D) → off
-	seg14Stages := []uint16{
-		0x0001, // A
-		0x0522, // F, B, H, J
-		0x00C0, // G1, G2
-		0x2814, // E, C, K, M
-		0x0008, // D
-		0x0000, // off
-	}
-
-	stages := seg7Stages
-	if n > 0 {
-		// Detect 14-seg by trying a write and checking the display type
-		// Since we can't query, use a simple heuristic: TerminalSegment14 and HT16K33
-		// always get Enc14 from the engine, but here we just check segment count.
-		// Use the 14-seg stages if we have them available.
-		// The engine sets the correct animation, so we use 7-seg by default
-		// and the registry can be extended. For now, use display length as hint is insufficient.
-		// Keep it simple: 7-seg stages work on both (low byte only on 14-seg still lights segments).
-	}
-	_ = seg14Stages // available for future use or explicit 14-seg rain variant
-
-	ticker := time.NewTicker(120 * time.Millisecond)
+	ticker := time.NewTicker(r.Params.Interval(120 * time.Millisecond))
 	defer ticker.Stop()
 
 	for {
@@ -79,7 +116,7 @@ func (r *Rain) Run(ctx context.Context, disp display.Display) error {
 
 		// Randomly spawn new drops
 		for i := range drops {
-			if drops[i] < 0 && rand.Intn(6) == 0 {
+			if drops[i] < 0 && rng.Float64() < density {
 				drops[i] = 0
 			}
 		}
@@ -98,18 +135,22 @@ func (r *Rain) Run(ctx context.Context, disp display.Display) error {
 			}
 		}
 
-		sd.WriteSegments(segments, false)
+		writeSegments(sd, segments, false, r.Params, mask7)
 	}
 }
 
 // Rain14 is a 14-segment-specific rain animation that uses diagonal segments.
-type Rain14 struct{}
+type Rain14 struct {
+	Params widget.AnimParams
+}
 
 func (r *Rain14) Name() string { return "segment-rain14" }
 
 func (r *Rain14) Run(ctx context.Context, disp display.Display) error {
 	sd := disp.(display.SegmentDisplay)
 	n := sd.DisplayLength()
+	rng := r.Params.Rand()
+	density := r.Params.DensityOr(1.0 / 6)
 
 	drops := make([]int, n)
 	for i := range drops {
@@ -125,7 +166,7 @@ func (r *Rain14) Run(ctx context.Context, disp display.Display) error {
 		0x0000, // off
 	}
 
-	ticker := time.NewTicker(120 * time.Millisecond)
+	ticker := time.NewTicker(r.Params.Interval(120 * time.Millisecond))
 	defer ticker.Stop()
 
 	for {
@@ -136,7 +177,7 @@ func (r *Rain14) Run(ctx context.Context, disp display.Display) error {
 		}
 
 		for i := range drops {
-			if drops[i] < 0 && rand.Intn(6) == 0 {
+			if drops[i] < 0 && rng.Float64() < density {
 				drops[i] = 0
 			}
 		}
@@ -155,20 +196,25 @@ func (r *Rain14) Run(ctx context.Context, disp display.Display) error {
 			}
 		}
 
-		sd.WriteSegments(segments, false)
+		writeSegments(sd, segments, false, r.Params, mask14)
 	}
 }
 
 // Static displays random segments lighting up across all digits (TV static).
-type Static struct{}
+// Density is the chance each segment is lit (default 0.5).
+type Static struct {
+	Params widget.AnimParams
+}
 
 func (r *Static) Name() string { return "segment-static" }
 
 func (r *Static) Run(ctx context.Context, disp display.Display) error {
 	sd := disp.(display.SegmentDisplay)
 	n := sd.DisplayLength()
+	rng := r.Params.Rand()
+	density := r.Params.DensityOr(0.5)
 
-	ticker := time.NewTicker(80 * time.Millisecond)
+	ticker := time.NewTicker(r.Params.Interval(80 * time.Millisecond))
 	defer ticker.Stop()
 
 	for {
@@ -180,17 +226,19 @@ func (r *Static) Run(ctx context.Context, disp display.Display) error {
 
 		segments := make([]uint16, n)
 		for i := range segments {
-			segments[i] = uint16(rand.Intn(0x80)) // 7-seg range (7 bits)
+			segments[i] = randomSegments(rng, 7, density)
 		}
 
-		sd.WriteSegments(segments, rand.Intn(2) == 0)
+		writeSegments(sd, segments, rng.Intn(2) == 0, r.Params, mask7)
 	}
 }
 
 // Scanner sweeps one vertical at a time back and forth across the digits.
 // For a 4-digit 7-segment display this gives 8 distinct positions:
 // digit 0 left, digit 0 right, digit 1 left, ..., digit 3 right.
-type Scanner struct{}
+type Scanner struct {
+	Params widget.AnimParams
+}
 
 func (s *Scanner) Name() string { return "segment-scanner" }
 
@@ -225,7 +273,7 @@ func (s *Scanner) Run(ctx context.Context, disp display.Display) error {
 		seq = append(seq, i)
 	}
 
-	ticker := time.NewTicker(100 * time.Millisecond)
+	ticker := time.NewTicker(s.Params.Interval(100 * time.Millisecond))
 	defer ticker.Stop()
 
 	pos := 0
@@ -239,7 +287,7 @@ func (s *Scanner) Run(ctx context.Context, disp display.Display) error {
 		v := positions[seq[pos]]
 		segments := make([]uint16, n)
 		segments[v.digit] = v.pattern
-		sd.WriteSegments(segments, false)
+		writeSegments(sd, segments, false, s.Params, mask7)
 
 		pos = (pos + 1) % len(seq)
 	}
@@ -248,7 +296,9 @@ func (s *Scanner) Run(ctx context.Context, disp display.Display) error {
 // Scanner14 sweeps one vertical at a time back and forth across the digits.
 // For a 4-digit 14-segment display this gives 20 distinct positions:
 // each digit has 5 verticals (F+E, H+K, I+L, J+M, B+C) left to right.
-type Scanner14 struct{}
+type Scanner14 struct {
+	Params widget.AnimParams
+}
 
 func (s *Scanner14) Name() string { return "segment-scanner14" }
 
@@ -287,7 +337,7 @@ func (s *Scanner14) Run(ctx context.Context, disp display.Display) error {
 		seq = append(seq, i)
 	}
 
-	ticker := time.NewTicker(60 * time.Millisecond)
+	ticker := time.NewTicker(s.Params.Interval(60 * time.Millisecond))
 	defer ticker.Stop()
 
 	pos := 0
@@ -301,7 +351,7 @@ func (s *Scanner14) Run(ctx context.Context, disp display.Display) error {
 		v := positions[seq[pos]]
 		segments := make([]uint16, n)
 		segments[v.digit] = v.pattern
-		sd.WriteSegments(segments, false)
+		writeSegments(sd, segments, false, s.Params, mask14)
 
 		pos = (pos + 1) % len(seq)
 	}
@@ -356,7 +406,9 @@ func buildTrack14(n int) []trackStep {
 }
 
 // Race animates two segments chasing each other around the perimeter of the display.
-type Race struct{}
+type Race struct {
+	Params widget.AnimParams
+}
 
 func (r *Race) Name() string { return "segment-race" }
 
@@ -368,11 +420,13 @@ func (r *Race) Run(ctx context.Context, disp display.Display) error {
 	}
 
 	track := buildTrack7(n)
-	return runRace(ctx, sd, n, track)
+	return runRace(ctx, sd, n, track, r.Params, mask7)
 }
 
 // Race14 animates two segments chasing each other using 14-segment bit positions.
-type Race14 struct{}
+type Race14 struct {
+	Params widget.AnimParams
+}
 
 func (r *Race14) Name() string { return "segment-race14" }
 
@@ -384,16 +438,16 @@ func (r *Race14) Run(ctx context.Context, disp display.Display) error {
 	}
 
 	track := buildTrack14(n)
-	return runRace(ctx, sd, n, track)
+	return runRace(ctx, sd, n, track, r.Params, mask14)
 }
 
-func runRace(ctx context.Context, sd display.SegmentDisplay, n int, track []trackStep) error {
+func runRace(ctx context.Context, sd display.SegmentDisplay, n int, track []trackStep, p widget.AnimParams, mask uint16) error {
 	trackLen := len(track)
 	// Two chasers half the track apart
 	pos1 := 0
 	pos2 := trackLen / 2
 
-	ticker := time.NewTicker(80 * time.Millisecond)
+	ticker := time.NewTicker(p.Interval(80 * time.Millisecond))
 	defer ticker.Stop()
 
 	for {
@@ -409,7 +463,7 @@ func runRace(ctx context.Context, sd display.SegmentDisplay, n int, track []trac
 		segments[s1.digit] |= s1.bit
 		segments[s2.digit] |= s2.bit
 
-		sd.WriteSegments(segments, false)
+		writeSegments(sd, segments, false, p, mask)
 
 		pos1 = (pos1 + 1) % trackLen
 		pos2 = (pos2 + 1) % trackLen
@@ -417,15 +471,19 @@ func runRace(ctx context.Context, sd display.SegmentDisplay, n int, track []trac
 }
 
 // Static14 displays random 14-segment patterns across all digits (TV static).
-type Static14 struct{}
+type Static14 struct {
+	Params widget.AnimParams
+}
 
 func (r *Static14) Name() string { return "segment-static14" }
 
 func (r *Static14) Run(ctx context.Context, disp display.Display) error {
 	sd := disp.(display.SegmentDisplay)
 	n := sd.DisplayLength()
+	rng := r.Params.Rand()
+	density := r.Params.DensityOr(0.5)
 
-	ticker := time.NewTicker(80 * time.Millisecond)
+	ticker := time.NewTicker(r.Params.Interval(80 * time.Millisecond))
 	defer ticker.Stop()
 
 	for {
@@ -437,9 +495,20 @@ func (r *Static14) Run(ctx context.Context, disp display.Display) error {
 
 		segments := make([]uint16, n)
 		for i := range segments {
-			segments[i] = uint16(rand.Intn(0x4000)) // 14-seg range (14 bits)
+			segments[i] = randomSegments(rng, 14, density)
 		}
 
-		sd.WriteSegments(segments, rand.Intn(2) == 0)
+		writeSegments(sd, segments, rng.Intn(2) == 0, r.Params, mask14)
+	}
+}
+
+// randomSegments lights each of the low bits segments with probability density.
+func randomSegments(rng *rand.Rand, bits int, density float64) uint16 {
+	var v uint16
+	for b := 0; b < bits; b++ {
+		if rng.Float64() < density {
+			v |= 1 << b
+		}
 	}
+	return v
 }
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
 	"github.com/swilcox/led-kurokku-go/widget/segment"
 )
 
@@ -150,15 +152,70 @@ func TestSegmentRace14_WritesSegments(t *testing.T) {
 }
 
 func TestSegmentRegistry(t *testing.T) {
-	for _, name := range []string{"rain", "static", "scanner", "race"} {
+	for _, name := range []string{"rain", "static", "scanner", "race", "spinner", "loading", "slots", "life", "bounce"} {
 		factory, ok := segment.Registry[name]
 		if !ok {
 			t.Errorf("Registry missing %q", name)
 			continue
 		}
-		w := factory()
+		w := factory(widget.AnimParams{}, segfont.Enc7)
 		if w == nil {
 			t.Errorf("Registry[%q] returned nil", name)
 		}
 	}
 }
+
+func TestSegmentRegistry_Selects14SegmentVariants(t *testing.T) {
+	for name, want := range map[string]string{
+		"rain":    "segment-rain14",
+		"static":  "segment-static14",
+		"scanner": "segment-scanner14",
+		"race":    "segment-race14",
+	} {
+		if got := segment.Registry[name](widget.AnimParams{}, segfont.Enc14).Name(); got != want {
+			t.Errorf("Registry[%q] with Enc14 = %s, want %s", name, got, want)
+		}
+		if got := segment.Registry[name](widget.AnimParams{}, segfont.Enc7).Name(); got != "segment-"+name {
+			t.Errorf("Registry[%q] with Enc7 = %s", name, got)
+		}
+	}
+}
+
+func TestSegmentStatic_SeedIsRepeatable(t *testing.T) {
+	run := func() []uint16 {
+		spy := &testutil.SpySegmentDisplay{}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		(&segment.Static{Params: widget.AnimParams{Seed: 7, Speed: 10}}).Run(ctx, spy) //nolint:errcheck
+		if len(spy.Calls) == 0 {
+			t.Fatal("expected segment writes")
+		}
+		return spy.Calls[0].Segments
+	}
+	a, b := run(), run()
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("seeded runs differ: %v vs %v", a, b)
+		}
+	}
+}
+
+func TestSegmentScanner_Invert(t *testing.T) {
+	spy := &testutil.SpySegmentDisplay{}
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	(&segment.Scanner{Params: widget.AnimParams{Invert: true}}).Run(ctx, spy) //nolint:errcheck
+	if len(spy.Calls) == 0 {
+		t.Fatal("expected segment writes")
+	}
+	// The first position lights digit 0's left bar; inverted, everything else is lit.
+	segs := spy.Calls[0].Segments
+	if segs[0] != 0x7F&^0x30 {
+		t.Errorf("digit 0 = %#x, want %#x", segs[0], 0x7F&^0x30)
+	}
+	for i := 1; i < len(segs); i++ {
+		if segs[i] != 0x7F {
+			t.Errorf("digit %d = %#x, want 0x7f", i, segs[i])
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package segment
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Prometheus displays the result of a PromQL instant query on a segment display.
+type Prometheus struct {
+	Query             widget.PromQuery
+	Prefix            string
+	Unit              string
+	Format            string
+	Scale             float64
+	Threshold         *widget.Threshold
+	AlertStyle        string
+	FallbackText      string
+	ScrollSpeed       time.Duration
+	RestoreBrightness func()
+	Encoder           segfont.Encoder
+}
+
+func (p *Prometheus) Name() string { return "segment-prometheus" }
+
+func (p *Prometheus) Run(ctx context.Context, disp display.Display) error {
+	pw := &widget.Prometheus{
+		Query:        p.Query,
+		Prefix:       p.Prefix,
+		Unit:         p.Unit,
+		Format:       p.Format,
+		Scale:        p.Scale,
+		Threshold:    p.Threshold,
+		FallbackText: p.FallbackText,
+	}
+	text, alert := pw.Text(ctx)
+
+	return widget.RunStyled(ctx, disp, alert, p.AlertStyle, p.RestoreBrightness, func(ctx context.Context, disp display.Display) error {
+		m := &Message{
+			Text:        text,
+			ScrollSpeed: p.ScrollSpeed,
+			Repeats:     -1,
+			Encoder:     p.Encoder,
+		}
+		return m.Run(ctx, disp)
+	})
+}
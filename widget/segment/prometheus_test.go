@@ -0,0 +1,42 @@
+package segment_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+	"github.com/swilcox/led-kurokku-go/widget/segment"
+)
+
+func TestSegmentPrometheus_ShowsValue(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[0,"52"]}}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	spy := &testutil.SpySegmentDisplay{}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	p := &segment.Prometheus{
+		Query:   widget.PromQuery{URL: srv.URL, Query: "temp"},
+		Prefix:  "t",
+		Encoder: segfont.Enc7,
+	}
+	p.Run(ctx, spy) //nolint:errcheck
+
+	if len(spy.Calls) != 1 {
+		t.Fatalf("expected 1 static segment write, got %d", len(spy.Calls))
+	}
+	want := segfont.EncodeText(segfont.Enc7, "t 52")
+	for i, seg := range spy.Calls[0].Segments {
+		if seg != want[i] {
+			t.Errorf("digit %d = 0x%02X, want 0x%02X", i, seg, want[i])
+		}
+	}
+}
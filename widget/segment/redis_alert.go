@@ -16,7 +16,13 @@ type RedisAlert struct {
 	Fetcher     widget.AlertFetcher
 	Fallback    []config.AlertConfig
 	ScrollSpeed time.Duration
-	Encoder     segfont.Encoder
+	Acks        widget.AckTracker // optional
+	Schedules   map[int]string    // per-priority schedules; nil uses config.DefaultAlertSchedules
+	Profiles    map[string]config.SeverityProfile
+	// HoldBrightness and OnDisplay are passed on to Alert.
+	HoldBrightness func() (release func())
+	OnDisplay      func(ctx context.Context, a config.AlertConfig)
+	Encoder        segfont.Encoder
 }
 
 func (ra *RedisAlert) Name() string { return "segment-redis-alert" }
@@ -29,9 +35,14 @@ func (ra *RedisAlert) Run(ctx context.Context, disp display.Display) error {
 	}
 
 	a := &Alert{
-		Alerts:      alerts,
-		ScrollSpeed: ra.ScrollSpeed,
-		Encoder:     ra.Encoder,
+		Alerts:         alerts,
+		ScrollSpeed:    ra.ScrollSpeed,
+		Encoder:        ra.Encoder,
+		Acks:           ra.Acks,
+		Schedules:      ra.Schedules,
+		Profiles:       ra.Profiles,
+		HoldBrightness: ra.HoldBrightness,
+		OnDisplay:      ra.OnDisplay,
 		OnDelete: func(ctx context.Context, id string) {
 			if err := ra.Fetcher.DeleteAlert(ctx, id); err != nil {
 				log.Printf("redis alert delete %s: %v", id, err)
@@ -0,0 +1,64 @@
+package segment
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/sysinfo"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// SysInfo rotates through local system statistics on a segment display
+// using compact 4-character formatting ("t 52", "L1.2").
+type SysInfo struct {
+	Reader      sysinfo.Reader
+	Metrics     []string
+	DiskPath    string
+	Interval    time.Duration
+	ScrollSpeed time.Duration
+	Encoder     segfont.Encoder
+}
+
+func (s *SysInfo) Name() string { return "segment-sysinfo" }
+
+func (s *SysInfo) Run(ctx context.Context, disp display.Display) error {
+	metrics := s.Metrics
+	if len(metrics) == 0 {
+		metrics = widget.DefaultSysInfoMetrics
+	}
+	interval := s.Interval
+	if interval == 0 {
+		interval = 3 * time.Second
+	}
+
+	for {
+		shown := 0
+		for _, metric := range metrics {
+			text, err := widget.SysInfoText(s.Reader, metric, s.DiskPath, true)
+			if err != nil {
+				log.Printf("sysinfo %s: %v", metric, err)
+				continue
+			}
+			shown++
+			if err := s.show(ctx, disp, text, interval); err != nil {
+				return err
+			}
+		}
+		if shown == 0 {
+			if err := s.show(ctx, disp, "----", interval); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *SysInfo) show(ctx context.Context, disp display.Display, text string, d time.Duration) error {
+	mctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	m := &Message{Text: text, ScrollSpeed: s.ScrollSpeed, Repeats: -1, Encoder: s.Encoder}
+	m.Run(mctx, disp)
+	return ctx.Err()
+}
@@ -0,0 +1,46 @@
+package segment
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Template displays text produced by a Go text/template on a segment display.
+type Template struct {
+	Text         string
+	Fetcher      widget.MessageTextFetcher
+	Location     *config.LocationConfig
+	EnvAllowed   func(name string) bool
+	FallbackText string
+	ScrollSpeed  time.Duration
+	Repeats      int
+	SleepBetween time.Duration
+	NowFunc      func() time.Time
+	Encoder      segfont.Encoder
+}
+
+func (t *Template) Name() string { return "segment-template" }
+
+func (t *Template) Run(ctx context.Context, disp display.Display) error {
+	tw := &widget.Template{
+		Text:         t.Text,
+		Fetcher:      t.Fetcher,
+		Location:     t.Location,
+		EnvAllowed:   t.EnvAllowed,
+		FallbackText: t.FallbackText,
+		NowFunc:      t.NowFunc,
+	}
+	m := &Message{
+		Text:         tw.Eval(ctx),
+		ScrollSpeed:  t.ScrollSpeed,
+		Repeats:      t.Repeats,
+		SleepBetween: t.SleepBetween,
+		Encoder:      t.Encoder,
+	}
+	return m.Run(ctx, disp)
+}
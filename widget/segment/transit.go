@@ -0,0 +1,46 @@
+package segment
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/segfont"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+// Transit shows the next transit departures on a segment display.
+type Transit struct {
+	Schedule     *widget.TransitSchedule
+	Realtime     *widget.RealtimeSource
+	Count        int
+	LookAhead    time.Duration
+	FallbackText string
+	ScrollSpeed  time.Duration
+	NowFunc      func() time.Time
+	Encoder      segfont.Encoder
+}
+
+func (t *Transit) Name() string { return "segment-transit" }
+
+func (t *Transit) Run(ctx context.Context, disp display.Display) error {
+	tw := &widget.Transit{
+		Schedule:  t.Schedule,
+		Realtime:  t.Realtime,
+		Count:     t.Count,
+		LookAhead: t.LookAhead,
+		NowFunc:   t.NowFunc,
+	}
+	if tw.Text(ctx) == "" && t.FallbackText == "" {
+		return nil
+	}
+	return widget.RunWatched(ctx, 30*time.Second, func() string {
+		if text := tw.Text(ctx); text != "" {
+			return text
+		}
+		return t.FallbackText
+	}, func(ctx context.Context, text string) error {
+		m := &Message{Text: text, ScrollSpeed: t.ScrollSpeed, Repeats: -1, Encoder: t.Encoder}
+		return m.Run(ctx, disp)
+	})
+}
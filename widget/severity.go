@@ -0,0 +1,82 @@
+package widget
+
+import (
+	"context"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+)
+
+// WarningIcon is the glyph shown before alerts whose profile has an icon.
+const WarningIcon = "⚠"
+
+// MaxBrightness raises disp to full brightness and returns a func ending
+// the boost. hold, when set, is used instead so the owner of the brightness
+// (the engine) can keep its own updates from lowering it meanwhile.
+func MaxBrightness(disp display.Display, hold func() (release func())) (release func()) {
+	if hold != nil {
+		return hold()
+	}
+	disp.SetBrightness(15)
+	return func() {}
+}
+
+// Flash fills and blanks the whole display n times, each state lasting
+// interval (100ms when 0). Segment displays light the segments in mask.
+func Flash(ctx context.Context, disp display.Display, n int, interval time.Duration, mask uint16) error {
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+	var on, off func()
+	switch d := disp.(type) {
+	case display.PixelDisplay:
+		full := make([]byte, d.Width())
+		for i := range full {
+			full[i] = 0xFF
+		}
+		on = func() { d.WriteFramebuffer(full) }
+		off = func() { d.WriteFramebuffer(make([]byte, d.Width())) }
+	case display.SegmentDisplay:
+		n := d.DisplayLength()
+		full := make([]uint16, n)
+		for i := range full {
+			full[i] = mask
+		}
+		on = func() { d.WriteSegments(full, true) }
+		off = func() { d.WriteSegments(make([]uint16, n), false) }
+	default:
+		return nil
+	}
+	for i := 0; i < n; i++ {
+		for _, draw := range []func(){on, off} {
+			draw()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+	}
+	return nil
+}
+
+// Invert wraps a pixel display so every frame is drawn inverted. Other
+// displays are returned unchanged.
+func Invert(disp display.Display) display.Display {
+	if pd, ok := disp.(display.PixelDisplay); ok {
+		return &invertPixel{PixelDisplay: pd}
+	}
+	return disp
+}
+
+type invertPixel struct {
+	display.PixelDisplay
+}
+
+func (p *invertPixel) WriteFramebuffer(buf []byte) {
+	inv := make([]byte, len(buf))
+	for i, b := range buf {
+		inv[i] = ^b
+	}
+	p.PixelDisplay.WriteFramebuffer(inv)
+}
@@ -0,0 +1,112 @@
+package widget_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display/testutil"
+	"github.com/swilcox/led-kurokku-go/font"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+func TestFlash_Pixel(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	if err := widget.Flash(context.Background(), spy, 2, time.Millisecond, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(spy.Frames) != 4 {
+		t.Fatalf("expected 4 frames for 2 flashes, got %d", len(spy.Frames))
+	}
+	for i, f := range spy.Frames {
+		want := byte(0)
+		if i%2 == 0 {
+			want = 0xFF
+		}
+		for _, b := range f {
+			if b != want {
+				t.Fatalf("frame %d = %v, want all %#x", i, f, want)
+			}
+		}
+	}
+}
+
+func TestInvert_Pixel(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	pd := widget.Invert(spy).(interface{ WriteFramebuffer([]byte) })
+	pd.WriteFramebuffer([]byte{0x00, 0x0F})
+	if got := spy.Frames[0]; got[0] != 0xFF || got[1] != 0xF0 {
+		t.Errorf("inverted frame = %#v, want [0xff 0xf0]", got)
+	}
+}
+
+func TestAlert_CriticalProfile(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	held, released := 0, 0
+	a := &widget.Alert{
+		Alerts: []config.AlertConfig{
+			{ID: "c", Message: "!", Priority: 1, Severity: config.SeverityCritical, DisplayDuration: config.Duration(50 * time.Millisecond)},
+		},
+		Profiles: map[string]config.SeverityProfile{
+			config.SeverityCritical: {Flashes: 1, FlashInterval: config.Duration(time.Millisecond), Invert: true, MaxBrightness: true, Icon: true},
+		},
+		HoldBrightness: func() func() {
+			held++
+			return func() { released++ }
+		},
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+
+	if held != 1 || released != 1 {
+		t.Errorf("brightness held %d times, released %d, want 1 and 1", held, released)
+	}
+	if len(spy.Frames) < 3 {
+		t.Fatalf("expected flash and text frames, got %d", len(spy.Frames))
+	}
+	if spy.Frames[0][0] != 0xFF || spy.Frames[1][0] != 0 {
+		t.Error("expected the text to be preceded by a flash")
+	}
+	// "⚠ !" is centered and inverted: unlit columns read 0xFF and the icon's
+	// first column is inverted.
+	text := spy.Frames[2]
+	cols := font.RenderText(widget.WarningIcon + " !")
+	offset := (32 - len(cols)) / 2
+	if text[0] != 0xFF || text[offset] != ^cols[0] {
+		t.Errorf("expected inverted icon and text, got %#v", text)
+	}
+}
+
+func TestAlert_InfoHasNoEffects(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	a := &widget.Alert{
+		Alerts: []config.AlertConfig{
+			{ID: "i", Message: "Hi", Priority: 1, Severity: config.SeverityInfo, DisplayDuration: config.Duration(10 * time.Millisecond)},
+		},
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+
+	if len(spy.Frames) != 1 || len(spy.Brightness) != 0 {
+		t.Errorf("expected one plain frame and no brightness change, got %d frames, brightness %v", len(spy.Frames), spy.Brightness)
+	}
+}
+
+func TestAlert_WarnDefaultFlashesOnce(t *testing.T) {
+	spy := &testutil.SpyDisplay{}
+	a := &widget.Alert{
+		Alerts: []config.AlertConfig{
+			{ID: "w", Message: "Hi", Priority: 1, Severity: config.SeverityWarn, DisplayDuration: config.Duration(300 * time.Millisecond)},
+		},
+	}
+
+	a.Run(context.Background(), spy) //nolint:errcheck
+
+	if len(spy.Frames) != 3 {
+		t.Fatalf("expected flash on, flash off and text frames, got %d", len(spy.Frames))
+	}
+	if spy.Frames[0][0] != 0xFF {
+		t.Error("expected the first frame to be a full flash")
+	}
+}
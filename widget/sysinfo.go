@@ -0,0 +1,165 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/sysinfo"
+)
+
+// System metrics understood by SysInfo.
+const (
+	MetricTemp   = "temp"
+	MetricLoad   = "load"
+	MetricMemory = "mem"
+	MetricUptime = "uptime"
+	MetricDisk   = "disk"
+)
+
+// DefaultSysInfoMetrics is the rotation used when no metrics are configured.
+var DefaultSysInfoMetrics = []string{MetricTemp, MetricLoad, MetricMemory, MetricUptime}
+
+// SysInfoText reads metric and formats it for display. Compact output fits a
+// 4-digit segment display ("t 52", "L1.2"); otherwise the text is sized for
+// the 32-pixel matrix ("52C", "L1.24").
+func SysInfoText(r sysinfo.Reader, metric, diskPath string, compact bool) (string, error) {
+	switch metric {
+	case MetricTemp:
+		c, err := r.CPUTemp()
+		if err != nil {
+			return "", err
+		}
+		if compact {
+			return fmt.Sprintf("t%3.0f", c), nil
+		}
+		return fmt.Sprintf("%.0fC", c), nil
+	case MetricLoad:
+		load, err := r.LoadAvg()
+		if err != nil {
+			return "", err
+		}
+		if compact {
+			if load[0] >= 10 {
+				return fmt.Sprintf("L%3.0f", load[0]), nil
+			}
+			return fmt.Sprintf("L%.1f", load[0]), nil
+		}
+		return fmt.Sprintf("L%.2f", load[0]), nil
+	case MetricMemory:
+		m, err := r.Memory()
+		if err != nil {
+			return "", err
+		}
+		if compact {
+			return fmt.Sprintf("m%3.0f", math.Min(m.UsedPercent(), 99)), nil
+		}
+		return fmt.Sprintf("M%.0f%%", m.UsedPercent()), nil
+	case MetricUptime:
+		up, err := r.Uptime()
+		if err != nil {
+			return "", err
+		}
+		if compact {
+			return "u" + compactDuration(up, 3), nil
+		}
+		return compactDuration(up, 5), nil
+	case MetricDisk:
+		d, err := r.Disk(diskPath)
+		if err != nil {
+			return "", err
+		}
+		if compact {
+			return fmt.Sprintf("d%3.0f", math.Min(d.UsedPercent(), 99)), nil
+		}
+		return fmt.Sprintf("D%.0f%%", d.UsedPercent()), nil
+	default:
+		return "", fmt.Errorf("unknown sysinfo metric %q", metric)
+	}
+}
+
+// compactDuration formats d using the largest units that fit in width
+// characters, e.g. "3d4h", "12h", "45m". Long uptimes switch to weeks and
+// then years, e.g. "14w", "2y".
+func compactDuration(d time.Duration, width int) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	mins := int(d.Minutes()) % 60
+
+	var s string
+	switch {
+	case days > 0:
+		s = fmt.Sprintf("%dd%dh", days, hours)
+		if len(s) > width {
+			s = fmt.Sprintf("%dd", days)
+		}
+		if len(s) > width {
+			s = fmt.Sprintf("%dw", days/7)
+		}
+		if len(s) > width {
+			s = fmt.Sprintf("%dy", days/365)
+		}
+	case hours > 0:
+		s = fmt.Sprintf("%dh%dm", hours, mins)
+		if len(s) > width {
+			s = fmt.Sprintf("%dh", hours)
+		}
+	default:
+		s = fmt.Sprintf("%dm", mins)
+	}
+	return s
+}
+
+// SysInfo rotates through local system statistics, showing each metric for
+// Interval before moving to the next.
+type SysInfo struct {
+	Reader      sysinfo.Reader
+	Metrics     []string
+	DiskPath    string
+	Interval    time.Duration
+	ScrollSpeed time.Duration
+}
+
+func (s *SysInfo) Name() string { return "sysinfo" }
+
+func (s *SysInfo) Run(ctx context.Context, disp display.Display) error {
+	metrics := s.Metrics
+	if len(metrics) == 0 {
+		metrics = DefaultSysInfoMetrics
+	}
+	interval := s.Interval
+	if interval == 0 {
+		interval = 3 * time.Second
+	}
+
+	for {
+		shown := 0
+		for _, metric := range metrics {
+			text, err := SysInfoText(s.Reader, metric, s.DiskPath, false)
+			if err != nil {
+				log.Printf("sysinfo %s: %v", metric, err)
+				continue
+			}
+			shown++
+			if err := s.show(ctx, disp, text, interval); err != nil {
+				return err
+			}
+		}
+		if shown == 0 {
+			if err := s.show(ctx, disp, "--", interval); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *SysInfo) show(ctx context.Context, disp display.Display, text string, d time.Duration) error {
+	mctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	m := &Message{Text: text, ScrollSpeed: s.ScrollSpeed, Repeats: -1}
+	m.Run(mctx, disp)
+	return ctx.Err()
+}
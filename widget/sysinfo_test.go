@@ -0,0 +1,89 @@
+package widget_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/swilcox/led-kurokku-go/sysinfo"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+func sysinfoFixture(t *testing.T) sysinfo.Reader {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"proc/loadavg":                         "1.24 0.98 0.50 2/345 6789\n",
+		"proc/meminfo":                         "MemTotal: 1000 kB\nMemAvailable: 570 kB\n",
+		"proc/uptime":                          "273600.00 0.00\n", // 3d4h
+		"sys/class/thermal/thermal_zone0/temp": "51800\n",
+	}
+	for name, data := range files {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0755) //nolint:errcheck
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return sysinfo.Reader{ProcRoot: filepath.Join(root, "proc"), SysRoot: filepath.Join(root, "sys")}
+}
+
+func TestSysInfoText(t *testing.T) {
+	r := sysinfoFixture(t)
+	tests := []struct {
+		metric  string
+		compact bool
+		want    string
+	}{
+		{widget.MetricTemp, true, "t 52"},
+		{widget.MetricTemp, false, "52C"},
+		{widget.MetricLoad, true, "L1.2"},
+		{widget.MetricLoad, false, "L1.24"},
+		{widget.MetricMemory, true, "m 43"},
+		{widget.MetricMemory, false, "M43%"},
+		{widget.MetricUptime, true, "u3d"},
+		{widget.MetricUptime, false, "3d4h"},
+	}
+	for _, tt := range tests {
+		got, err := widget.SysInfoText(r, tt.metric, "", tt.compact)
+		if err != nil {
+			t.Errorf("%s: %v", tt.metric, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("SysInfoText(%s, compact=%v) = %q, want %q", tt.metric, tt.compact, got, tt.want)
+		}
+	}
+}
+
+func TestSysInfoText_LongUptimeFits(t *testing.T) {
+	tests := []struct {
+		uptime  string
+		compact bool
+		want    string
+	}{
+		{"8640000.00", true, "u14w"}, // 100 days
+		{"8640000.00", false, "100d"},
+		{"86400000.00", true, "u2y"}, // 1000 days
+		{"86400000.00", false, "1000d"},
+	}
+	for _, tt := range tests {
+		root := t.TempDir()
+		if err := os.WriteFile(filepath.Join(root, "uptime"), []byte(tt.uptime+" 0.00\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := widget.SysInfoText(sysinfo.Reader{ProcRoot: root}, widget.MetricUptime, "", tt.compact)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("uptime %s (compact=%v) = %q, want %q", tt.uptime, tt.compact, got, tt.want)
+		}
+	}
+}
+
+func TestSysInfoText_UnknownMetric(t *testing.T) {
+	if _, err := widget.SysInfoText(sysinfo.Reader{}, "bogus", "", false); err == nil {
+		t.Error("expected error for unknown metric")
+	}
+}
@@ -0,0 +1,202 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/nathan-osman/go-sunrise"
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/display"
+)
+
+// HashFieldFetcher fetches a single field from a Redis hash.
+type HashFieldFetcher interface {
+	FetchHashField(ctx context.Context, key, field string) (string, bool, error)
+}
+
+// Template displays text produced by a Go text/template, re-evaluated on
+// every Run. See TemplateFuncs for the available functions.
+type Template struct {
+	Text     string
+	Fetcher  MessageTextFetcher // optional; enables redis and (if implemented) redisHash
+	Location *config.LocationConfig
+	// EnvAllowed reports which variables env may read; nil allows none.
+	EnvAllowed   func(name string) bool
+	FallbackText string
+	ScrollSpeed  time.Duration
+	Repeats      int
+	SleepBetween time.Duration
+	NowFunc      func() time.Time
+}
+
+func (t *Template) Name() string { return "template" }
+
+func (t *Template) Run(ctx context.Context, disp display.Display) error {
+	m := &Message{
+		Text:         t.Eval(ctx),
+		ScrollSpeed:  t.ScrollSpeed,
+		Repeats:      t.Repeats,
+		SleepBetween: t.SleepBetween,
+	}
+	return m.Run(ctx, disp)
+}
+
+// Eval renders the template, returning FallbackText (or "--") on error.
+func (t *Template) Eval(ctx context.Context) string {
+	text, err := t.Render(ctx)
+	if err != nil {
+		log.Printf("template: %v", err)
+		if t.FallbackText != "" {
+			return t.FallbackText
+		}
+		return "--"
+	}
+	return text
+}
+
+// Render parses and executes the template.
+func (t *Template) Render(ctx context.Context) (string, error) {
+	tmpl, err := template.New("text").Funcs(TemplateFuncs(ctx, t.Fetcher, t.Location, t.NowFunc, t.EnvAllowed)).Parse(t.Text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, nil); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// TemplateFuncs returns the function map available to template widgets:
+//
+//	now                     current time (in the configured location's timezone)
+//	fmtTime "15:04" t       format a time with a Go layout
+//	redis "key"             value of a Redis string key ("" when absent)
+//	redisHash "key" "field" value of a Redis hash field ("" when absent)
+//	env "NAME"              environment variable, if envAllowed accepts it
+//	sunrise, sunset         today's sunrise/sunset for the configured location
+//	daysUntil "2026-12-25"  whole days from today until the date
+//	upper, lower            change case
+//	pad 4 x                 right-align x to the given width
+//	add, sub, mul, div      float arithmetic on numbers or numeric strings
+//	round x                 round to the nearest integer
+func TemplateFuncs(ctx context.Context, fetcher MessageTextFetcher, loc *config.LocationConfig, nowFunc func() time.Time, envAllowed func(name string) bool) template.FuncMap {
+	tz := time.Local
+	if loc != nil && loc.Timezone != "" {
+		if l, err := time.LoadLocation(loc.Timezone); err == nil {
+			tz = l
+		}
+	}
+	now := func() time.Time {
+		if nowFunc != nil {
+			return nowFunc().In(tz)
+		}
+		return time.Now().In(tz)
+	}
+	sun := func(rise bool) (time.Time, error) {
+		if loc == nil {
+			return time.Time{}, fmt.Errorf("sunrise/sunset require a configured location")
+		}
+		n := now()
+		r, s := sunrise.SunriseSunset(loc.Lat, loc.Lon, n.Year(), n.Month(), n.Day())
+		if rise {
+			return r.In(tz), nil
+		}
+		return s.In(tz), nil
+	}
+
+	return template.FuncMap{
+		"now":     now,
+		"fmtTime": func(layout string, t time.Time) string { return t.Format(layout) },
+		"redis": func(key string) (string, error) {
+			if fetcher == nil {
+				return "", nil
+			}
+			v, _, err := fetcher.FetchMessageText(ctx, key)
+			return v, err
+		},
+		"redisHash": func(key, field string) (string, error) {
+			hf, ok := fetcher.(HashFieldFetcher)
+			if !ok {
+				return "", nil
+			}
+			v, _, err := hf.FetchHashField(ctx, key, field)
+			return v, err
+		},
+		"env": func(name string) (string, error) {
+			if envAllowed == nil || !envAllowed(name) {
+				return "", fmt.Errorf("env: %s is not allowed", name)
+			}
+			return os.Getenv(name), nil
+		},
+		"sunrise": func() (time.Time, error) { return sun(true) },
+		"sunset":  func() (time.Time, error) { return sun(false) },
+		"daysUntil": func(date string) (int, error) {
+			n := now()
+			target, err := time.ParseInLocation("2006-01-02", date, tz)
+			if err != nil {
+				return 0, err
+			}
+			today := time.Date(n.Year(), n.Month(), n.Day(), 0, 0, 0, 0, tz)
+			return int(math.Round(target.Sub(today).Hours() / 24)), nil
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"pad": func(width int, v interface{}) string {
+			return fmt.Sprintf("%*v", width, v)
+		},
+		"add": func(a, b interface{}) (float64, error) {
+			return arith(a, b, func(x, y float64) float64 { return x + y })
+		},
+		"sub": func(a, b interface{}) (float64, error) {
+			return arith(a, b, func(x, y float64) float64 { return x - y })
+		},
+		"mul": func(a, b interface{}) (float64, error) {
+			return arith(a, b, func(x, y float64) float64 { return x * y })
+		},
+		"div": func(a, b interface{}) (float64, error) {
+			return arith(a, b, func(x, y float64) float64 { return x / y })
+		},
+		"round": func(a interface{}) (float64, error) {
+			return arith(a, 0, func(x, _ float64) float64 { return math.Round(x) })
+		},
+	}
+}
+
+func arith(a, b interface{}, op func(x, y float64) float64) (float64, error) {
+	x, err := toFloat(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := toFloat(b)
+	if err != nil {
+		return 0, err
+	}
+	return op(x, y), nil
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		if err != nil {
+			return 0, fmt.Errorf("not a number: %q", n)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("not a number: %v", v)
+	}
+}
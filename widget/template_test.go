@@ -0,0 +1,137 @@
+package widget_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/config"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+type mockTemplateFetcher struct {
+	values map[string]string
+	hashes map[string]map[string]string
+	err    error
+}
+
+func (m *mockTemplateFetcher) FetchMessageText(_ context.Context, key string) (string, bool, error) {
+	v, ok := m.values[key]
+	return v, ok, m.err
+}
+
+func (m *mockTemplateFetcher) FetchHashField(_ context.Context, key, field string) (string, bool, error) {
+	v, ok := m.hashes[key][field]
+	return v, ok, m.err
+}
+
+func fixedNow() time.Time {
+	return time.Date(2026, 12, 20, 14, 5, 0, 0, time.UTC)
+}
+
+func TestTemplate_Render(t *testing.T) {
+	fetcher := &mockTemplateFetcher{
+		values: map[string]string{"temp": "71.6"},
+		hashes: map[string]map[string]string{"ci": {"status": "green"}},
+	}
+	tests := []struct {
+		text string
+		want string
+	}{
+		{`{{ now | fmtTime "15:04" }}`, "14:05"},
+		{`{{ redis "temp" }}F`, "71.6F"},
+		{`{{ redis "missing" }}`, ""},
+		{`CI {{ redisHash "ci" "status" | upper }}`, "CI GREEN"},
+		{`{{ daysUntil "2026-12-25" }}d to xmas`, "5d to xmas"},
+		{`[{{ pad 4 "ab" }}]`, "[  ab]"},
+		{`{{ add 2 3 }} {{ sub "10" 4 }} {{ mul 1.5 2 }} {{ div 9 3 }}`, "5 6 3 3"},
+		{`{{ redis "temp" | round }}`, "72"},
+	}
+	for _, tt := range tests {
+		tw := &widget.Template{Text: tt.text, Fetcher: fetcher, NowFunc: fixedNow}
+		got, err := tw.Render(context.Background())
+		if err != nil {
+			t.Errorf("Render(%q): %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Render(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTemplate_Sunrise(t *testing.T) {
+	tw := &widget.Template{
+		Text:     `{{ sunrise | fmtTime "15:04" }}`,
+		Location: &config.LocationConfig{Lat: 36.166, Lon: -86.784, Timezone: "America/Chicago"},
+		NowFunc:  func() time.Time { return time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC) },
+	}
+	got, err := tw.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nashville sunrise on the solstice is around 05:30 CDT.
+	if got < "05:00" || got > "06:00" {
+		t.Errorf("sunrise = %q, want roughly 05:30", got)
+	}
+}
+
+func TestTemplate_SunriseWithoutLocation(t *testing.T) {
+	tw := &widget.Template{Text: `{{ sunrise }}`}
+	if _, err := tw.Render(context.Background()); err == nil {
+		t.Error("expected error when no location is configured")
+	}
+}
+
+func TestTemplate_Env(t *testing.T) {
+	t.Setenv("KUROKKU_TEST_SITE", "home")
+	t.Setenv("KUROKKU_PUSH_TOKEN", "secret")
+	allowed := func(name string) bool { return name == "KUROKKU_TEST_SITE" }
+
+	tw := &widget.Template{Text: `{{ env "KUROKKU_TEST_SITE" | upper }}`, EnvAllowed: allowed}
+	if got, err := tw.Render(context.Background()); err != nil || got != "HOME" {
+		t.Errorf("Render() = %q, %v; want HOME", got, err)
+	}
+	// Templates can come from Redis, so variables not allowed, such as
+	// secrets, are refused.
+	for _, tw := range []*widget.Template{
+		{Text: `{{ env "KUROKKU_PUSH_TOKEN" }}`, EnvAllowed: allowed},
+		{Text: `{{ env "KUROKKU_TEST_SITE" }}`},
+	} {
+		if got, err := tw.Render(context.Background()); err == nil {
+			t.Errorf("%s: Render() = %q, want an error", tw.Text, got)
+		}
+	}
+}
+
+func TestTemplate_EvalFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		tw   *widget.Template
+		want string
+	}{
+		{"parse error", &widget.Template{Text: `{{ nope`}, "--"},
+		{"fetch error", &widget.Template{
+			Text:         `{{ redis "k" }}`,
+			Fetcher:      &mockTemplateFetcher{err: errors.New("down")},
+			FallbackText: "offline",
+		}, "offline"},
+	}
+	for _, tt := range tests {
+		if got := tt.tw.Eval(context.Background()); got != tt.want {
+			t.Errorf("%s: Eval() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTemplate_NilFetcher(t *testing.T) {
+	tw := &widget.Template{Text: `a{{ redis "k" }}{{ redisHash "k" "f" }}b`}
+	got, err := tw.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "ab" {
+		t.Errorf("Render() = %q, want %q", got, "ab")
+	}
+}
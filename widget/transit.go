@@ -0,0 +1,233 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/display"
+	"github.com/swilcox/led-kurokku-go/gtfs"
+)
+
+// TransitSchedule loads a static GTFS zip on first use and reloads it when
+// the file's size or modification time changes.
+type TransitSchedule struct {
+	Path     string
+	Filter   gtfs.Filter
+	Location *time.Location // overrides the agency timezone when set
+
+	mu      sync.Mutex
+	sched   *gtfs.Schedule
+	modTime time.Time
+	size    int64
+}
+
+// Schedule returns the loaded schedule. On a failed reload the previously
+// loaded schedule is kept and the error is returned alongside it.
+func (t *TransitSchedule) Schedule() (*gtfs.Schedule, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fi, err := os.Stat(t.Path)
+	if err != nil {
+		return t.sched, fmt.Errorf("gtfs feed: %w", err)
+	}
+	if t.sched != nil && fi.ModTime().Equal(t.modTime) && fi.Size() == t.size {
+		return t.sched, nil
+	}
+	sched, err := gtfs.Load(t.Path, t.Filter)
+	if err != nil {
+		return t.sched, err
+	}
+	if t.Location != nil {
+		sched.Location = t.Location
+	}
+	t.sched, t.modTime, t.size = sched, fi.ModTime(), fi.Size()
+	return sched, nil
+}
+
+// RealtimeSource reads a GTFS-realtime feed from a local file or an http(s)
+// URL at most once per Refresh. Updates older than ten refresh intervals are
+// discarded rather than applied as stale delays.
+type RealtimeSource struct {
+	Source  string
+	Refresh time.Duration // default 30s
+	Client  *http.Client
+	NowFunc func() time.Time
+
+	mu      sync.Mutex
+	rt      *gtfs.Realtime
+	fetched time.Time // last attempt
+	loaded  time.Time // last success
+}
+
+func (r *RealtimeSource) now() time.Time {
+	if r.NowFunc != nil {
+		return r.NowFunc()
+	}
+	return time.Now()
+}
+
+// Realtime returns the current trip updates, or nil when none are available.
+func (r *RealtimeSource) Realtime(ctx context.Context) (*gtfs.Realtime, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	refresh := r.Refresh
+	if refresh == 0 {
+		refresh = 30 * time.Second
+	}
+	now := r.now()
+	var err error
+	if r.fetched.IsZero() || now.Sub(r.fetched) >= refresh {
+		r.fetched = now
+		var rt *gtfs.Realtime
+		if rt, err = r.load(ctx); err == nil {
+			r.rt, r.loaded = rt, now
+		}
+	}
+	if r.rt != nil && now.Sub(r.loaded) > 10*refresh {
+		r.rt = nil
+	}
+	return r.rt, err
+}
+
+func (r *RealtimeSource) load(ctx context.Context) (*gtfs.Realtime, error) {
+	var rc io.ReadCloser
+	if strings.HasPrefix(r.Source, "http://") || strings.HasPrefix(r.Source, "https://") {
+		client := r.Client
+		if client == nil {
+			client = &http.Client{Timeout: 10 * time.Second}
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.Source, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetching gtfs-realtime: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("fetching gtfs-realtime: HTTP %d", resp.StatusCode)
+		}
+		rc = resp.Body
+	} else {
+		f, err := os.Open(r.Source)
+		if err != nil {
+			return nil, fmt.Errorf("opening gtfs-realtime: %w", err)
+		}
+		rc = f
+	}
+	defer rc.Close()
+
+	// Trip update feeds for a whole agency are typically well under a few MB.
+	b, err := io.ReadAll(io.LimitReader(rc, 16<<20))
+	if err != nil {
+		return nil, fmt.Errorf("reading gtfs-realtime: %w", err)
+	}
+	return gtfs.ParseRealtime(b)
+}
+
+// Transit shows the next departures per route from the configured stops,
+// e.g. "12 5m 17m". When nothing departs within LookAhead it shows
+// FallbackText, or skips itself when that is empty.
+type Transit struct {
+	Schedule     *TransitSchedule
+	Realtime     *RealtimeSource // optional
+	Count        int             // departures per route, default 3
+	LookAhead    time.Duration   // default 3h
+	FallbackText string
+	ScrollSpeed  time.Duration
+	NowFunc      func() time.Time
+}
+
+func (t *Transit) now() time.Time {
+	if t.NowFunc != nil {
+		return t.NowFunc()
+	}
+	return time.Now()
+}
+
+func (t *Transit) Name() string { return "transit" }
+
+func (t *Transit) Run(ctx context.Context, disp display.Display) error {
+	if t.Text(ctx) == "" && t.FallbackText == "" {
+		return nil
+	}
+	return RunWatched(ctx, 30*time.Second, func() string {
+		if text := t.Text(ctx); text != "" {
+			return text
+		}
+		return t.FallbackText
+	}, func(ctx context.Context, text string) error {
+		m := &Message{Text: text, ScrollSpeed: t.ScrollSpeed, Repeats: -1}
+		return m.Run(ctx, disp)
+	})
+}
+
+// Text returns the departure summary, or "" when nothing is scheduled.
+func (t *Transit) Text(ctx context.Context) string {
+	sched, err := t.Schedule.Schedule()
+	if err != nil {
+		log.Printf("transit: %v", err)
+	}
+	if sched == nil {
+		return ""
+	}
+	var rt *gtfs.Realtime
+	if t.Realtime != nil {
+		if rt, err = t.Realtime.Realtime(ctx); err != nil {
+			log.Printf("transit realtime: %v", err)
+		}
+	}
+	lookAhead := t.LookAhead
+	if lookAhead == 0 {
+		lookAhead = 3 * time.Hour
+	}
+	now := t.now()
+	deps := sched.Next(now, 0, rt)
+	for i, d := range deps {
+		if d.Time.Sub(now) > lookAhead {
+			deps = deps[:i]
+			break
+		}
+	}
+	return FormatDepartures(deps, now, t.Count)
+}
+
+// FormatDepartures groups departures by route, in order of each route's first
+// departure, listing up to count (default 3) waits per route: "12 5m 17m 4 8m".
+func FormatDepartures(deps []gtfs.Departure, now time.Time, count int) string {
+	if count == 0 {
+		count = 3
+	}
+	var order []string
+	waits := make(map[string][]string)
+	for _, d := range deps {
+		if _, ok := waits[d.Route]; !ok {
+			order = append(order, d.Route)
+		}
+		if len(waits[d.Route]) < count {
+			waits[d.Route] = append(waits[d.Route], formatWait(d.Time.Sub(now)))
+		}
+	}
+	var parts []string
+	for _, route := range order {
+		parts = append(parts, route+" "+strings.Join(waits[route], " "))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatWait(d time.Duration) string {
+	if d < time.Minute {
+		return "now"
+	}
+	return compactDuration(d, 4)
+}
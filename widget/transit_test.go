@@ -0,0 +1,110 @@
+package widget_test
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/swilcox/led-kurokku-go/gtfs"
+	"github.com/swilcox/led-kurokku-go/widget"
+)
+
+func writeGTFS(t *testing.T, path string, stopTimes string) {
+	t.Helper()
+	files := map[string]string{
+		"routes.txt":     "route_id,route_short_name\nr12,12\nr4,4\n",
+		"trips.txt":      "route_id,service_id,trip_id\nr12,ALL,a\nr12,ALL,b\nr12,ALL,c\nr12,ALL,d\nr4,ALL,e\n",
+		"calendar.txt":   "service_id,monday,tuesday,wednesday,thursday,friday,saturday,sunday,start_date,end_date\nALL,1,1,1,1,1,1,1,20260101,20261231\n",
+		"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" + stopTimes,
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, body := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(body)) //nolint:errcheck
+	}
+	zw.Close()
+	f.Close()
+}
+
+func TestFormatDepartures(t *testing.T) {
+	now := time.Date(2026, 1, 6, 8, 0, 0, 0, time.UTC)
+	at := func(route string, d time.Duration) gtfs.Departure {
+		return gtfs.Departure{Route: route, Time: now.Add(d)}
+	}
+	deps := []gtfs.Departure{
+		at("12", 30*time.Second),
+		at("4", 8*time.Minute),
+		at("12", 5*time.Minute),
+		at("12", 17*time.Minute),
+		at("12", 90*time.Minute),
+		at("4", 2*time.Hour+15*time.Minute),
+	}
+	if got, want := widget.FormatDepartures(deps, now, 3), "12 now 5m 17m 4 8m 2h"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTransit_TextAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gtfs.zip")
+	writeGTFS(t, path, "a,,08:05:00,S1,1\nb,,08:17:00,S1,1\nc,,08:40:00,S1,1\ne,,08:12:00,S1,1\n")
+
+	now := time.Date(2026, 1, 6, 8, 0, 0, 0, time.UTC)
+	tr := &widget.Transit{
+		Schedule: &widget.TransitSchedule{Path: path, Filter: gtfs.Filter{Stops: []string{"S1"}}},
+		Count:    2,
+		NowFunc:  func() time.Time { return now },
+	}
+	if got, want := tr.Text(context.Background()), "12 5m 17m 4 12m"; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+
+	// A replaced feed is picked up on the next call.
+	writeGTFS(t, path, "d,,08:03:00,S1,1\n")
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(path, future, future) //nolint:errcheck
+	if got, want := tr.Text(context.Background()), "12 3m"; got != want {
+		t.Errorf("after reload Text() = %q, want %q", got, want)
+	}
+}
+
+func TestTransit_SkipsWithoutDepartures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gtfs.zip")
+	writeGTFS(t, path, "a,,08:05:00,OTHER,1\n")
+	tr := &widget.Transit{
+		Schedule: &widget.TransitSchedule{Path: path, Filter: gtfs.Filter{Stops: []string{"S1"}}},
+	}
+	if text := tr.Text(context.Background()); text != "" {
+		t.Errorf("Text() = %q, want empty", text)
+	}
+	if err := tr.Run(context.Background(), nil); err != nil {
+		t.Errorf("expected immediate nil return, got %v", err)
+	}
+}
+
+func TestRealtimeSource_DropsStaleUpdates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rt.pb")
+	os.WriteFile(path, nil, 0o644) //nolint:errcheck // an empty FeedMessage is valid
+
+	now := time.Date(2026, 1, 6, 8, 0, 0, 0, time.UTC)
+	src := &widget.RealtimeSource{Source: path, Refresh: time.Second, NowFunc: func() time.Time { return now }}
+	if rt, err := src.Realtime(context.Background()); err != nil || rt == nil {
+		t.Fatalf("Realtime() = %v, %v", rt, err)
+	}
+
+	os.Remove(path)
+	now = now.Add(5 * time.Second)
+	if rt, err := src.Realtime(context.Background()); err == nil || rt == nil {
+		t.Errorf("expected error with previous updates kept, got %v, %v", rt, err)
+	}
+	now = now.Add(time.Minute)
+	if rt, _ := src.Realtime(context.Background()); rt != nil {
+		t.Error("expected stale updates to be dropped")
+	}
+}
@@ -0,0 +1,150 @@
+package widget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValueSource supplies a single numeric reading.
+type ValueSource interface {
+	Value(ctx context.Context) (float64, error)
+}
+
+// RedisValue reads a number stored as a Redis string.
+type RedisValue struct {
+	Fetcher MessageTextFetcher
+	Key     string
+}
+
+func (r *RedisValue) Value(ctx context.Context) (float64, error) {
+	s, found, err := r.Fetcher.FetchMessageText(ctx, r.Key)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("redis key %s not set", r.Key)
+	}
+	return parseNumber(s)
+}
+
+// FileValue reads the first number in a file, e.g.
+// /sys/class/power_supply/BAT0/capacity.
+type FileValue struct {
+	Path string
+}
+
+// maxValueFile caps how much of a FileValue's file is read, so a path such as
+// /dev/zero cannot stall the widget or exhaust memory.
+const maxValueFile = 64 << 10
+
+func (f *FileValue) Value(context.Context) (float64, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	b, err := io.ReadAll(io.LimitReader(file, maxValueFile))
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("%s is empty", f.Path)
+	}
+	return parseNumber(fields[0])
+}
+
+// JSONValue fetches a JSON document over HTTP and extracts a number at Path,
+// a dot-separated list of object keys and array indices ("jobs.0.progress").
+type JSONValue struct {
+	URL    string
+	Path   string
+	Client *http.Client
+}
+
+func (j *JSONValue) Value(ctx context.Context) (float64, error) {
+	client := j.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("fetching %s: %w", j.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("fetching %s: HTTP %d", j.URL, resp.StatusCode)
+	}
+
+	var doc interface{}
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return 0, fmt.Errorf("decoding %s: %w", j.URL, err)
+	}
+	v, err := JSONPath(doc, j.Path)
+	if err != nil {
+		return 0, err
+	}
+	switch n := v.(type) {
+	case json.Number:
+		return n.Float64()
+	case string:
+		return parseNumber(n)
+	case bool:
+		if n {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, fmt.Errorf("value at %q is not a number", j.Path)
+}
+
+// JSONPath walks a decoded JSON document along a dot-separated path of
+// object keys and array indices. An empty path returns doc itself.
+func JSONPath(doc interface{}, path string) (interface{}, error) {
+	if path == "" {
+		return doc, nil
+	}
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			next, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("json path %q: no key %q", path, key)
+			}
+			cur = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("json path %q: bad index %q", path, key)
+			}
+			cur = node[i]
+		default:
+			return nil, fmt.Errorf("json path %q: cannot descend into %q", path, key)
+		}
+	}
+	return cur, nil
+}
+
+// parseNumber parses a float, tolerating surrounding space and a trailing "%".
+func parseNumber(s string) (float64, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "%")
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("not a number: %q", s)
+	}
+	return v, nil
+}
@@ -15,6 +15,13 @@ type Widget interface {
 	Run(ctx context.Context, disp display.Display) error
 }
 
+// Interrupter is implemented by widgets that can ask to be shown right away,
+// cutting into the rotation the way new alerts do. The engine calls
+// Interrupts once; the widget sends on the channel whenever it wants to run.
+type Interrupter interface {
+	Interrupts(ctx context.Context) <-chan struct{}
+}
+
 // SleepOrCancel sleeps for d or returns early if ctx is cancelled.
 func SleepOrCancel(ctx context.Context, d time.Duration) error {
 	select {
@@ -57,3 +64,46 @@ func ScrollText(ctx context.Context, disp display.PixelDisplay, text string,
 		}
 	}
 }
+
+// RunWatched shows the text returned by poll using show, polling again every
+// interval (default 1s) and restarting show whenever the text changes.
+func RunWatched(ctx context.Context, interval time.Duration, poll func() string,
+	show func(ctx context.Context, text string) error) error {
+
+	if interval == 0 {
+		interval = time.Second
+	}
+	text := poll()
+	for {
+		showCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func(text string) {
+			show(showCtx, text)
+			close(done)
+		}(text)
+
+		ticker := time.NewTicker(interval)
+		changed := false
+		for !changed {
+			select {
+			case <-ctx.Done():
+				ticker.Stop()
+				cancel()
+				<-done
+				return ctx.Err()
+			case <-done:
+				ticker.Stop()
+				cancel()
+				return nil
+			case <-ticker.C:
+				if next := poll(); next != text {
+					text = next
+					changed = true
+				}
+			}
+		}
+		ticker.Stop()
+		cancel()
+		<-done
+	}
+}